@@ -0,0 +1,105 @@
+package cfg
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// normalizeKeys renames the keys of a document spelled like lowerCamel, snake_case or kebab-case, like hostPort,
+// host_port or api-endpoints, to the names of their fields, and records the keys as written by the path of the
+// field when the aliases are not nil
+func normalizeKeys(v any, t reflect.Type, path string, aliases map[string]string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch vv := v.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := make(map[string]reflect.StructField, t.NumField())
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				if !f.IsExported() || f.Tag.Get("json") == "-" {
+					continue
+				}
+				fields[KeyNormalize.normalize(jsonName(f))] = f
+			}
+			keys := make([]string, 0, len(vv))
+			for k := range vv {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				f, ok := fields[KeyNormalize.normalize(k)]
+				if !ok {
+					continue
+				}
+				name := jsonName(f)
+				if k != name {
+					if _, exists := vv[name]; exists {
+						// the field is also spelled as its name
+						continue
+					}
+					vv[name] = vv[k]
+					delete(vv, k)
+					if aliases != nil {
+						aliases[joinPath(path, name)] = k
+					}
+				}
+				if f.Type == reflect.TypeOf(EnvironmentOverrides{}) {
+					// the overrides of each environment are fields of the entry
+					if envs, ok := vv[name].(map[string]any); ok {
+						for env, ov := range envs {
+							normalizeKeys(ov, t, joinPath(joinPath(path, name), env), aliases)
+						}
+					}
+					continue
+				}
+				normalizeKeys(vv[name], f.Type, joinPath(path, name), aliases)
+			}
+		case reflect.Map:
+			for k, e := range vv {
+				normalizeKeys(e, t.Elem(), joinPath(path, k), aliases)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, e := range vv {
+				normalizeKeys(e, t.Elem(), path+"["+strconv.Itoa(i)+"]", aliases)
+			}
+		}
+	}
+}
+
+// restoreAliases renames the fields of a document back to the keys as written, deepest first
+func restoreAliases(doc map[string]any, aliases map[string]string) {
+	paths := make([]string, 0, len(aliases))
+	for p := range aliases {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if di, dj := strings.Count(paths[i], "."), strings.Count(paths[j], "."); di != dj {
+			return di > dj
+		}
+		return paths[i] < paths[j]
+	})
+	for _, p := range paths {
+		parent, name := doc, p
+		if i := strings.LastIndex(p, "."); i >= 0 {
+			v, err := getPath(doc, p[:i])
+			if err != nil {
+				continue
+			}
+			if parent, _ = v.(map[string]any); parent == nil {
+				continue
+			}
+			name = p[i+1:]
+		}
+		if k, ok := lookupKey(parent, name); ok {
+			parent[aliases[p]] = parent[k]
+			delete(parent, k)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyAliases(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"application_id": "orders",
+		"hostPort": 8000,
+		"cookie-domain": "example.com",
+		"api_endpoints": [{"id": "DEFAULT", "address": "https://api.example.com"}],
+		"databases": [{
+			"ID": "DEFAULT",
+			"connection_string": "sqlserver://localhost",
+			"Environments": {"prod": {"connection_string": "sqlserver://db.prod"}}
+		}]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithEnvironment("prod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" || *config.HostPort != 8000 || *config.CookieDomain != "example.com" {
+		t.Fatalf(`Unexpected fields %v %v %v`, config.ApplicationID, config.HostPort, config.CookieDomain)
+	}
+	if ep := config.GetEndpointInfo("DEFAULT"); ep == nil || ep.Address != "https://api.example.com" {
+		t.Fatalf(`Unexpected endpoint %+v`, ep)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); db.ConnectionString != "sqlserver://db.prod" {
+		t.Fatalf(`Unexpected database %+v`, db)
+	}
+	if keys := config.LoadReport().UnknownKeys; len(keys) != 0 {
+		t.Fatalf(`Unexpected unknown keys %v`, keys)
+	}
+
+	// the keys are saved as written
+	config.ApplicationID = new_string("billing")
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := make(map[string]any)
+	if err = json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	db := saved["databases"].([]any)[0].(map[string]any)
+	prod := db["Environments"].(map[string]any)["prod"].(map[string]any)
+	if saved["application_id"] != "billing" || saved["cookie-domain"] != "example.com" || saved["api_endpoints"] == nil ||
+		db["connection_string"] != "sqlserver://localhost" || prod["connection_string"] != "sqlserver://db.prod" || saved["ApplicationID"] != nil || saved["hostPort"] != 8000.0 {
+		t.Fatalf(`Unexpected saved file %s`, b)
+	}
+	if err = config.Reload(); err != nil || *config.ApplicationID != "billing" {
+		t.Fatalf(`Unexpected reload %v`, err)
+	}
+}
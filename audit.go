@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// AccessReport - access counts of the sections and entries of the configuration
+	AccessReport struct {
+		Accessed   map[string]uint64 // Access counts by section or entry like Databases[DEFAULT] or Flags[MaxLimit]
+		Unaccessed []string          // Sections and entries present in the configuration that were never accessed
+	}
+
+	// accessAudit - access counts shared across reloads
+	accessAudit struct {
+		sync.Mutex
+		counts map[string]uint64
+	}
+)
+
+// auditedSections are the sections where access is recorded by the lookup methods
+var auditedSections = []string{
+	"APIEndpoints", "Databases", "Directories", "Domains", "Flags", "Health", "JWT", "JWTSecret",
+	"Jobs", "Maintenance", "Notifications", "OAuths", "Proxy", "RateLimits", "Sessions", "Sources", "Webhooks",
+}
+
+// WithAccessAudit records the sections, entries and flags accessed through the lookup methods
+// like GetDatabaseInfo, Flag, JWTSigner and GetField. Fields read directly are not recorded.
+func WithAccessAudit() Option {
+	return func(o *options) {
+		o.audit = true
+	}
+}
+
+// record counts an access to a section or to an entry of a section
+func (c *Configuration) record(section, id string) {
+	if c.audit == nil {
+		return
+	}
+	k := section
+	if id != "" {
+		k += "[" + id + "]"
+	}
+	c.audit.Lock()
+	c.audit.counts[k]++
+	c.audit.Unlock()
+}
+
+// AccessReport returns the access counts when loaded with WithAccessAudit, and the audited sections
+// and entries that were never accessed. It returns nil when auditing is not enabled.
+func (c *Configuration) AccessReport() *AccessReport {
+	if c.audit == nil {
+		return nil
+	}
+	rpt := &AccessReport{Accessed: make(map[string]uint64)}
+	c.audit.Lock()
+	for k, n := range c.audit.counts {
+		rpt.Accessed[k] = n
+	}
+	c.audit.Unlock()
+
+	accessed := make(map[string]bool, len(rpt.Accessed))
+	for k := range rpt.Accessed {
+		accessed[strings.ToLower(k)] = true
+	}
+	doc, err := configDocument(c)
+	if err != nil {
+		return rpt
+	}
+	for _, sec := range auditedSections {
+		v := doc[sec]
+		if v == nil {
+			continue
+		}
+		s, ok := v.([]any)
+		if !ok {
+			if !accessed[strings.ToLower(sec)] {
+				rpt.Unaccessed = append(rpt.Unaccessed, sec)
+			}
+			continue
+		}
+		key := entryKey(s, nil)
+		if key == "" {
+			continue
+		}
+		for _, e := range s {
+			id := fieldValue(e.(map[string]any), key).(string)
+			if k := sec + "[" + id + "]"; !accessed[strings.ToLower(k)] {
+				rpt.Unaccessed = append(rpt.Unaccessed, k)
+			}
+		}
+	}
+	sort.Strings(rpt.Unaccessed)
+	return rpt
+}
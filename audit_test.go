@@ -0,0 +1,47 @@
+package cfg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccessReport(t *testing.T) {
+	config, err := Load("samples/config.mssql.json", WithAccessAudit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.GetDatabaseInfo("DEFAULT")
+	config.GetDatabaseInfo("DEFAULT")
+	config.Flag("MaxLimit")
+	config.GetRateLimit("public")
+	config.GetField("Jobs[cleanup].Schedule")
+
+	rpt := config.AccessReport()
+	if rpt == nil {
+		t.Fatal(`Expected an access report`)
+	}
+	if rpt.Accessed["Databases[DEFAULT]"] != 2 || rpt.Accessed["Flags[MaxLimit]"] != 1 {
+		t.Fatalf(`Unexpected access counts %v`, rpt.Accessed)
+	}
+	want := []string{
+		"APIEndpoints[DEFAULT]",
+		"Directories[IMAGE]",
+		"Directories[IMPORT]",
+		"Domains[VDIMDCI]",
+		"Flags[Joan]",
+		"JWTSecret",
+		"Jobs[sync]",
+		"Notifications[DEFAULT]",
+		"OAuths[AppCore]",
+		"Sessions[web]",
+		"Sources[order]",
+		"Webhooks[orders]",
+	}
+	if !reflect.DeepEqual(rpt.Unaccessed, want) {
+		t.Fatalf("Unexpected unaccessed entries\nwant: %v\ngot:  %v", want, rpt.Unaccessed)
+	}
+
+	if plain, _ := Load("samples/config.mssql.json"); plain.AccessReport() != nil {
+		t.Fatal(`Expected no report without the audit option`)
+	}
+}
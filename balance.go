@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Strategies of selecting the addresses of an endpoint
+const (
+	StrategyRoundRobin = "ROUND-ROBIN" // Rotates the addresses on every call
+	StrategyRandom     = "RANDOM"      // Picks an address at random on every call
+	StrategyFailover   = "FAILOVER"    // Keeps the first address until it is marked failed
+)
+
+type (
+	// balancer - selection state of the addresses of an endpoint
+	balancer struct {
+		next atomic.Uint64
+	}
+
+	// balancers - selection state of the addresses of the endpoints of a configuration by lower case id
+	balancers struct {
+		sync.Mutex
+		byID map[string]*balancer
+	}
+)
+
+// unattached is the selection state of the endpoints not found through a loaded configuration,
+// like the entries built in code
+var unattached = &balancers{byID: make(map[string]*balancer)}
+
+// get returns the selection state of an endpoint, created on first use
+func (bs *balancers) get(id string) *balancer {
+	if bs == nil {
+		bs = unattached
+	}
+	bs.Lock()
+	defer bs.Unlock()
+	k := strings.ToLower(id)
+	b, ok := bs.byID[k]
+	if !ok {
+		b = &balancer{}
+		bs.byID[k] = b
+	}
+	return b
+}
+
+// addresses returns the Addresses, or the Address when there are none
+func (ep *EndpointInfo) addresses() []string {
+	if len(ep.Addresses) > 0 {
+		return ep.Addresses
+	}
+	if ep.Address == "" {
+		return nil
+	}
+	return []string{ep.Address}
+}
+
+// NextAddress returns the address to call by the Strategy of the endpoint. Round-robin rotates the
+// addresses on every call, random picks one on every call and failover keeps the current address
+// until MarkFailed is called with it. The selection is kept by the configuration for the id of the
+// endpoint, so the copies of the endpoint share it until the configuration is reloaded. Endpoints
+// not found through a loaded configuration share the selection of their id.
+// It returns the Address when there are no Addresses.
+func (ep *EndpointInfo) NextAddress() string {
+	addrs := ep.addresses()
+	switch len(addrs) {
+	case 0:
+		return ""
+	case 1:
+		return addrs[0]
+	}
+	b := ep.balance.get(ep.ID)
+	switch strings.ToUpper(ep.Strategy) {
+	case StrategyRandom:
+		return addrs[rand.Intn(len(addrs))]
+	case StrategyFailover:
+		return addrs[b.next.Load()%uint64(len(addrs))]
+	}
+	return addrs[(b.next.Add(1)-1)%uint64(len(addrs))]
+}
+
+// MarkFailed moves a failover endpoint to the next address when the address is the current one,
+// so concurrent callers failing on the same address move only once
+func (ep *EndpointInfo) MarkFailed(address string) {
+	addrs := ep.addresses()
+	if len(addrs) < 2 || !strings.EqualFold(ep.Strategy, StrategyFailover) {
+		return
+	}
+	b := ep.balance.get(ep.ID)
+	cur := b.next.Load()
+	if addrs[cur%uint64(len(addrs))] == address {
+		b.next.CompareAndSwap(cur, cur+1)
+	}
+}
@@ -0,0 +1,83 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestNextAddress(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"APIEndpoints": [
+			{"ID": "RR", "Addresses": ["https://a", "https://b", "https://c"]},
+			{"ID": "FO", "Addresses": ["https://primary", "https://standby"], "Strategy": "failover"},
+			{"ID": "RND", "Addresses": ["https://x", "https://y"], "Strategy": "RANDOM"},
+			{"ID": "ONE", "Address": "https://one"}
+		]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the copies of the endpoint share the rotation
+	got := make([]string, 0)
+	for i := 0; i < 4; i++ {
+		got = append(got, config.GetEndpointInfo("RR").NextAddress())
+	}
+	if got[0] != "https://a" || got[1] != "https://b" || got[2] != "https://c" || got[3] != "https://a" {
+		t.Fatalf(`Unexpected round-robin %v`, got)
+	}
+
+	fo := config.GetEndpointInfo("FO")
+	if fo.Strategy != StrategyFailover || fo.NextAddress() != "https://primary" || fo.NextAddress() != "https://primary" {
+		t.Fatalf(`Expected the primary address`)
+	}
+	fo.MarkFailed("https://primary")
+	fo.MarkFailed("https://primary")
+	if a := config.GetEndpointInfo("FO").NextAddress(); a != "https://standby" {
+		t.Fatalf(`Expected the standby address after a failure, got %s`, a)
+	}
+
+	for i := 0; i < 10; i++ {
+		if a := config.GetEndpointInfo("RND").NextAddress(); a != "https://x" && a != "https://y" {
+			t.Fatalf(`Unexpected random address %s`, a)
+		}
+	}
+	if a := config.GetEndpointInfo("ONE").NextAddress(); a != "https://one" {
+		t.Fatalf(`Expected the address, got %s`, a)
+	}
+
+	// the copies of extracted configurations and of the entries built in code share the rotation too
+	ex, err := config.Extract("APIEndpoints")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, b := ex.GetEndpointInfo("RR").NextAddress(), ex.GetEndpointInfo("RR").NextAddress(); a != "https://b" || b != "https://c" {
+		t.Fatalf(`Unexpected round-robin of the extract %s %s`, a, b)
+	}
+	ep := EndpointInfo{ID: "built", Addresses: []string{"https://a", "https://b"}}
+	if a, b := ep.NextAddress(), ep.NextAddress(); a != "https://a" || b != "https://b" {
+		t.Fatalf(`Unexpected round-robin of the entry %s %s`, a, b)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config.GetEndpointInfo("RR").NextAddress()
+		}()
+	}
+	wg.Wait()
+
+	(*config.APIEndpoints)[0].Strategy = "STICKY"
+	if err = config.Validate(); !errors.Is(err, ErrInvalidEnum) {
+		t.Fatalf(`Expected ErrInvalidEnum, got %v`, err)
+	}
+}
@@ -0,0 +1,39 @@
+package cfg
+
+import "time"
+
+// FailureThreshold returns the consecutive failures that open the circuit of the endpoint, or zero
+// when the endpoint has no circuit breaker
+func (ep *EndpointInfo) FailureThreshold() int {
+	if ep.Breaker == nil {
+		return 0
+	}
+	if ep.Breaker.FailureThreshold == 0 {
+		return 5
+	}
+	return ep.Breaker.FailureThreshold
+}
+
+// OpenDuration returns the time the circuit of the endpoint stays open before probing, or zero
+// when the endpoint has no circuit breaker
+func (ep *EndpointInfo) OpenDuration() time.Duration {
+	if ep.Breaker == nil {
+		return 0
+	}
+	if ep.Breaker.OpenDuration == nil {
+		return 30 * time.Second
+	}
+	return ep.Breaker.OpenDuration.Duration()
+}
+
+// HalfOpenProbes returns the probe calls let through while the circuit of the endpoint is half-open,
+// or zero when the endpoint has no circuit breaker
+func (ep *EndpointInfo) HalfOpenProbes() int {
+	if ep.Breaker == nil {
+		return 0
+	}
+	if ep.Breaker.HalfOpenProbes == 0 {
+		return 1
+	}
+	return ep.Breaker.HalfOpenProbes
+}
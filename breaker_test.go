@@ -0,0 +1,47 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"APIEndpoints": [
+			{"ID": "PAYMENTS", "Address": "https://pay", "Breaker": {"FailureThreshold": 3, "OpenDuration": "1m", "HalfOpenProbes": 2}},
+			{"ID": "SEARCH", "Address": "https://search", "Breaker": {}},
+			{"ID": "PLAIN", "Address": "https://plain"}
+		]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		id        string
+		threshold int
+		open      time.Duration
+		probes    int
+	}{
+		{"PAYMENTS", 3, time.Minute, 2},
+		{"SEARCH", 5, 30 * time.Second, 1},
+		{"PLAIN", 0, 0, 0},
+	} {
+		ep := config.GetEndpointInfo(tt.id)
+		if ep.FailureThreshold() != tt.threshold || ep.OpenDuration() != tt.open || ep.HalfOpenProbes() != tt.probes {
+			t.Errorf(`Unexpected breaker of %s: %d %s %d`, tt.id, ep.FailureThreshold(), ep.OpenDuration(), ep.HalfOpenProbes())
+		}
+	}
+
+	(*config.APIEndpoints)[0].Breaker.HalfOpenProbes = -1
+	if err = config.Validate(); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf(`Expected ErrOutOfRange, got %v`, err)
+	}
+}
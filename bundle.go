@@ -0,0 +1,174 @@
+package cfg
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// BundleMetadata - metadata of a configuration bundle
+	BundleMetadata struct {
+		Version   string    // Version of the configuration like 2024.03.1
+		CreatedAt time.Time // Time the bundle was written. Default is the time of WriteBundle
+		Author    string    // Author of the configuration
+		Digest    string    // SHA-256 of the configuration in hex, set by WriteBundle
+	}
+
+	// Signer signs the metadata of a bundle
+	Signer func(payload []byte) []byte
+)
+
+// Entries of a bundle
+const (
+	bundleConfig    = "config.json"
+	bundleMetadata  = "metadata.json"
+	bundleSignature = "metadata.json.sig"
+)
+
+var (
+	ErrInvalidBundle = errors.New("invalid configuration bundle")
+	ErrSaveBundle    = errors.New("configuration loaded from a bundle can not be saved")
+)
+
+// HMACSigner signs with HMAC-SHA256, verified on load with WithHMACSignature
+func HMACSigner(secret []byte) Signer {
+	return func(payload []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(payload)
+		return mac.Sum(nil)
+	}
+}
+
+// Ed25519Signer signs with Ed25519, verified on load with WithEd25519Signature
+func Ed25519Signer(priv ed25519.PrivateKey) Signer {
+	return func(payload []byte) []byte {
+		return ed25519.Sign(priv, payload)
+	}
+}
+
+// WriteBundle writes a tar bundle of the JSON configuration document, its metadata and, when the signer
+// is set, the signature of the metadata. The metadata carries the digest of the document so the signature
+// covers both. The digest, and the creation time when not set, are filled in.
+func WriteBundle(w io.Writer, doc []byte, meta BundleMetadata, sign Signer) error {
+	sum := sha256.Sum256(doc)
+	meta.Digest = hex.EncodeToString(sum[:])
+	if meta.CreatedAt.IsZero() {
+		meta.CreatedAt = time.Now().UTC()
+	}
+	mb, err := json.MarshalIndent(meta, "", "\t")
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(w)
+	add := func(name string, b []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(b)), ModTime: meta.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(b)
+		return err
+	}
+	if err = add(bundleConfig, doc); err != nil {
+		return err
+	}
+	if err = add(bundleMetadata, mb); err != nil {
+		return err
+	}
+	if sign != nil {
+		if err = add(bundleSignature, []byte(hex.EncodeToString(sign(mb)))); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// LoadBundle loads the configuration from a bundle written by WriteBundle. When WithHMACSignature
+// or WithEd25519Signature is set, the signature of the metadata is verified before parsing.
+// The digest of the document is always checked against the metadata.
+func LoadBundle(source string, opts ...Option) (*Configuration, BundleMetadata, error) {
+	o := newOptions(opts...)
+	start := o.now()
+	config, err := loadBundle(source, o)
+	if config == nil {
+		(&Configuration{instrumentation: o.instrumentation, clock: o.clock}).observe(EventLoad, source, start, err)
+		return nil, BundleMetadata{}, err
+	}
+	if o.audit {
+		config.audit = &accessAudit{counts: make(map[string]uint64)}
+	}
+	if err == nil && o.history != nil {
+		config.history = o.history
+		config.snapshot(EventLoad)
+	}
+	if err == nil {
+		config.touch(o.now())
+	}
+	config.observe(EventLoad, source, start, err)
+	return config, *config.bundle, err
+}
+
+// BundleMetadata returns the metadata of the bundle the configuration was loaded from, or nil
+func (c *Configuration) BundleMetadata() *BundleMetadata {
+	if c.bundle == nil {
+		return nil
+	}
+	m := *c.bundle
+	return &m
+}
+
+// loadBundle reads the bundle, verifies it and parses its configuration
+func loadBundle(source string, o *options) (*Configuration, error) {
+	config := newConfiguration(o)
+	config.local = !(strings.HasPrefix(source, `http://`) || strings.HasPrefix(source, `https://`))
+	b, err := readSource(context.Background(), source, config.local, o, o.limits.withDefaults())
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(ErrInvalidBundle, err)
+		}
+		if entries[hdr.Name], err = io.ReadAll(tr); err != nil {
+			return nil, wrapError(ErrInvalidBundle, err)
+		}
+	}
+	doc, mb := entries[bundleConfig], entries[bundleMetadata]
+	if doc == nil || mb == nil {
+		return nil, fmt.Errorf("%w: %s and %s are required", ErrInvalidBundle, bundleConfig, bundleMetadata)
+	}
+	if o.verifier != nil {
+		sig, ok := entries[bundleSignature]
+		if !ok || !o.verifier(mb, decodeSignature(sig)) {
+			return nil, ErrInvalidSignature
+		}
+	}
+	meta := &BundleMetadata{}
+	if err = json.Unmarshal(mb, meta); err != nil {
+		return nil, wrapError(ErrInvalidBundle, err)
+	}
+	if sum := sha256.Sum256(doc); hex.EncodeToString(sum[:]) != meta.Digest {
+		return nil, fmt.Errorf("%w: digest does not match the configuration", ErrInvalidBundle)
+	}
+	config.bundle = meta
+	if len(doc) == 0 {
+		return config, ErrNoDataFromSource
+	}
+	return parse(config, source, doc, o)
+}
@@ -0,0 +1,66 @@
+package cfg
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBundle(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := []byte(`{"ApplicationID": "orders", "HostPort": 8000}`)
+	created := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	buf := &bytes.Buffer{}
+	if err = WriteBundle(buf, doc, BundleMetadata{Version: "2024.03.1", Author: "ops", CreatedAt: created}, Ed25519Signer(priv)); err != nil {
+		t.Fatal(err)
+	}
+	fn := filepath.Join(t.TempDir(), "config.tar")
+	if err = os.WriteFile(fn, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, meta, err := LoadBundle(fn, WithEd25519Signature(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" || meta.Version != "2024.03.1" || meta.Author != "ops" || !meta.CreatedAt.Equal(created) || meta.Digest == "" {
+		t.Fatalf(`Unexpected bundle %+v`, meta)
+	}
+	if bm := config.BundleMetadata(); bm == nil || bm.Version != meta.Version {
+		t.Fatalf(`Unexpected bundle metadata %+v`, bm)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatalf(`Expected the bundle to reload, got %v`, err)
+	}
+	if err = config.Save(); !errors.Is(err, ErrSaveBundle) {
+		t.Fatalf(`Expected ErrSaveBundle, got %v`, err)
+	}
+
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, _, err = LoadBundle(fn, WithEd25519Signature(other)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature, got %v`, err)
+	}
+
+	buf.Reset()
+	if err = WriteBundle(buf, doc, BundleMetadata{Version: "1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	b := bytes.Replace(buf.Bytes(), []byte(`"orders"`), []byte(`"evil!!"`), 1)
+	if err = os.WriteFile(fn, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = LoadBundle(fn); !errors.Is(err, ErrInvalidBundle) {
+		t.Fatalf(`Expected ErrInvalidBundle on digest mismatch, got %v`, err)
+	}
+	if _, _, err = LoadBundle(fn, WithEd25519Signature(pub)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature on unsigned bundle, got %v`, err)
+	}
+}
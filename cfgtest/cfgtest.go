@@ -0,0 +1,81 @@
+// Package cfgtest provides configuration fixtures for tests of packages using the configuration
+package cfgtest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// Option sets a section of the fixture
+type Option func(*cfg.Configuration)
+
+// NewConfig builds a configuration from the options, writes it to a temporary file and loads it,
+// so the defaults and validation of Load apply. The test fails if the configuration does not load.
+func NewConfig(t testing.TB, opts ...Option) *cfg.Configuration {
+	t.Helper()
+	config, err := cfg.Load(WriteFile(t, opts...))
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	return config
+}
+
+// WriteFile builds a configuration from the options and writes it to a temporary file
+// removed when the test ends. It returns the name of the file.
+func WriteFile(t testing.TB, opts ...Option) string {
+	t.Helper()
+	config := &cfg.Configuration{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	b, err := json.MarshalIndent(config, "", "\t")
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err = os.WriteFile(fn, b, 0644); err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	return fn
+}
+
+// WithApplicationID sets the application id
+func WithApplicationID(id string) Option {
+	return func(c *cfg.Configuration) {
+		c.ApplicationID = &id
+	}
+}
+
+// WithDatabase adds a database
+func WithDatabase(db cfg.DatabaseInfo) Option {
+	return func(c *cfg.Configuration) {
+		if c.Databases == nil {
+			c.Databases = &[]cfg.DatabaseInfo{}
+		}
+		*c.Databases = append(*c.Databases, db)
+	}
+}
+
+// WithEndpoint adds an API endpoint
+func WithEndpoint(ep cfg.EndpointInfo) Option {
+	return func(c *cfg.Configuration) {
+		if c.APIEndpoints == nil {
+			c.APIEndpoints = &[]cfg.EndpointInfo{}
+		}
+		*c.APIEndpoints = append(*c.APIEndpoints, ep)
+	}
+}
+
+// WithFlag adds a flag
+func WithFlag(key, value string) Option {
+	return func(c *cfg.Configuration) {
+		if c.Flags == nil {
+			c.Flags = &[]cfg.Flag{}
+		}
+		*c.Flags = append(*c.Flags, cfg.Flag{Key: key, Value: &value})
+	}
+}
@@ -0,0 +1,43 @@
+package cfgtest
+
+import (
+	"testing"
+
+	cfg "github.com/eaglebush/config"
+)
+
+func TestNewConfig(t *testing.T) {
+	config := NewConfig(t,
+		WithApplicationID("app"),
+		WithDatabase(cfg.DatabaseInfo{ID: "DEFAULT", ConnectionString: "sqlserver://localhost", DriverName: "sqlserver"}),
+		WithEndpoint(cfg.EndpointInfo{ID: "DEFAULT", Address: "https://api.example.com"}),
+		WithFlag("Beta", "on"),
+	)
+	if *config.ApplicationID != "app" {
+		t.Fatalf(`Unexpected application id %q`, *config.ApplicationID)
+	}
+	db := config.GetDatabaseInfo("DEFAULT")
+	if db == nil || db.ConnectionString != "sqlserver://localhost" {
+		t.Fatalf(`Unexpected default database %+v`, db)
+	}
+	if db.StorageType != "SERVER" || db.ParameterPlaceholder != "?" {
+		t.Fatalf(`Expected database defaults to be applied, got %+v`, db)
+	}
+	if ep := config.GetEndpointInfo(""); ep == nil || ep.Address != "https://api.example.com" {
+		t.Fatalf(`Unexpected default endpoint %+v`, ep)
+	}
+	if f := config.Flag("Beta").Bool(); f == nil || !*f {
+		t.Fatal(`Expected flag Beta to be on`)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	fn := WriteFile(t, WithFlag("Beta", "1"))
+	config, err := cfg.Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(*config.Flags) != 1 {
+		t.Fatalf(`Unexpected flags %v`, *config.Flags)
+	}
+}
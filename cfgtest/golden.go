@@ -0,0 +1,109 @@
+package cfgtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// UpdateEnv is the environment variable that, when set, makes the golden assertions write the golden file
+const UpdateEnv = "CFGTEST_UPDATE"
+
+// AssertGolden loads the configuration from a copy of the source, saves it and asserts that the saved
+// file is byte-for-byte the golden file, with the saved FileName as the name of the source.
+// When CFGTEST_UPDATE is set, the golden file is written instead.
+func AssertGolden(t testing.TB, source, golden string, opts ...cfg.Option) {
+	t.Helper()
+	_, saved := roundTrip(t, source, opts...)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.WriteFile(golden, saved, 0644); err != nil {
+			t.Fatalf("cfgtest: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	if bytes.Equal(saved, want) {
+		return
+	}
+	sl, wl := strings.Split(string(saved), "\n"), strings.Split(string(want), "\n")
+	for i := 0; i < len(sl) || i < len(wl); i++ {
+		var s, w string
+		if i < len(sl) {
+			s = sl[i]
+		}
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if s != w {
+			t.Fatalf("cfgtest: saved configuration differs from %s at line %d\nwant: %s\ngot:  %s", golden, i+1, w, s)
+		}
+	}
+}
+
+// AssertGoldenSemantic loads the configuration from a copy of the source, saves it and asserts that the
+// saved configuration is equivalent to the golden file, ignoring formatting and the order of the keys.
+// When CFGTEST_UPDATE is set, the golden file is written instead.
+func AssertGoldenSemantic(t testing.TB, source, golden string, opts ...cfg.Option) {
+	t.Helper()
+	fn, saved := roundTrip(t, source, opts...)
+	if os.Getenv(UpdateEnv) != "" {
+		if err := os.WriteFile(golden, saved, 0644); err != nil {
+			t.Fatalf("cfgtest: %v", err)
+		}
+		return
+	}
+	got, err := cfg.Load(fn, opts...)
+	if err != nil {
+		t.Fatalf("cfgtest: saved configuration does not load: %v", err)
+	}
+	want, err := cfg.Load(golden, opts...)
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	chs, err := cfg.Diff(want, got)
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	if len(chs) > 0 {
+		msgs := make([]string, 0, len(chs))
+		for _, ch := range chs {
+			msgs = append(msgs, ch.String())
+		}
+		t.Fatalf("cfgtest: saved configuration differs from %s\n%s", golden, strings.Join(msgs, "\n"))
+	}
+}
+
+// roundTrip loads a temporary copy of the source, saves it and returns the name and contents of the saved file
+func roundTrip(t testing.TB, source string, opts ...cfg.Option) (string, []byte) {
+	t.Helper()
+	b, err := os.ReadFile(source)
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	fn := filepath.Join(t.TempDir(), filepath.Base(source))
+	if err = os.WriteFile(fn, b, 0644); err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	config, err := cfg.Load(fn, opts...)
+	if err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	if err = config.Save(); err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	if b, err = os.ReadFile(fn); err != nil {
+		t.Fatalf("cfgtest: %v", err)
+	}
+	// the saved FileName is the path of the temporary copy, compared as the name of the source
+	tmp, _ := json.Marshal(fn)
+	name, _ := json.Marshal(filepath.Base(source))
+	return fn, bytes.ReplaceAll(b, tmp, name)
+}
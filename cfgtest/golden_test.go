@@ -0,0 +1,11 @@
+package cfgtest
+
+import "testing"
+
+func TestAssertGolden(t *testing.T) {
+	AssertGolden(t, "testdata/config.json", "testdata/config.golden.json")
+}
+
+func TestAssertGoldenSemantic(t *testing.T) {
+	AssertGoldenSemantic(t, "testdata/config.json", "testdata/config.semantic.json")
+}
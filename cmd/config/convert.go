@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runConvert translates a configuration file to another format
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	from := fs.String("from", "", "format of the input: json, yaml or toml. Inferred from the extension when not set")
+	to := fs.String("to", "", "format of the output: json, yaml or toml. Inferred from the output extension when not set")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fmt.Fprintln(stderr, "Usage: config convert [-from format] [-to format] <input> [output]")
+		return exitError
+	}
+	in, out := fs.Arg(0), fs.Arg(1)
+	ff, tf := cfg.Format(*from), cfg.Format(*to)
+	if ff == "" {
+		ff = cfg.FormatOf(in)
+	}
+	if tf == "" && out != "" {
+		tf = cfg.FormatOf(out)
+	}
+	if ff == "" || tf == "" {
+		fmt.Fprintln(stderr, "config convert: cannot determine the formats, set -from and -to")
+		return exitError
+	}
+	b, err := os.ReadFile(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "config convert: %v\n", err)
+		return exitError
+	}
+	cb, err := cfg.Convert(b, ff, tf)
+	if err != nil {
+		fmt.Fprintf(stderr, "config convert: %s: %v\n", in, err)
+		return exitError
+	}
+	if out == "" {
+		stdout.Write(cb)
+		return exitOK
+	}
+	if err = os.WriteFile(out, cb, 0644); err != nil {
+		fmt.Fprintf(stderr, "config convert: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runDiff prints the semantic differences between two configurations.
+// The exit code is 1 when there are differences, like diff(1).
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, "Usage: config diff <file|url> <file|url>")
+		return exitError
+	}
+	cs := make([]*cfg.Configuration, 2)
+	for i, src := range fs.Args() {
+		c, err := cfg.Load(src)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", src, err)
+			return exitError
+		}
+		cs[i] = c
+	}
+	changes, err := cfg.Diff(cs[0], cs[1])
+	if err != nil {
+		fmt.Fprintf(stderr, "config diff: %v\n", err)
+		return exitError
+	}
+	for _, c := range changes {
+		fmt.Fprintln(stdout, c)
+	}
+	if len(changes) > 0 {
+		return exitProblem
+	}
+	return exitOK
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runDocs writes the Markdown reference of the configuration fields
+func runDocs(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "Usage: config docs [output]")
+		return exitError
+	}
+	b := cfg.Reference()
+	if fs.NArg() == 0 {
+		stdout.Write(b)
+		return exitOK
+	}
+	if err := os.WriteFile(fs.Arg(0), b, 0644); err != nil {
+		fmt.Fprintf(stderr, "config docs: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
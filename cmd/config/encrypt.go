@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runEncrypt encrypts the sensitive fields of a configuration file
+func runEncrypt(args []string, stdout, stderr io.Writer) int {
+	return runCrypt("encrypt", cfg.EncryptDocument, args, stdout, stderr)
+}
+
+// runDecrypt decrypts the sensitive fields of a configuration file
+func runDecrypt(args []string, stdout, stderr io.Writer) int {
+	return runCrypt("decrypt", cfg.DecryptDocument, args, stdout, stderr)
+}
+
+// runCrypt rewrites the sensitive fields of a configuration file with the transform.
+// The key is read from an environment variable or a file. To use a key management service,
+// decrypt the data key with its tool and pass it through the environment.
+func runCrypt(name string, transform func([]byte, cfg.Format, []byte) ([]byte, error), args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	keyEnv := fs.String("key-env", "CONFIG_KEY", "environment variable that holds the key")
+	keyFile := fs.String("key-file", "", "file that holds the key. Overrides -key-env")
+	inPlace := fs.Bool("w", false, "write the result to the input file")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 || (*inPlace && fs.NArg() > 1) {
+		fmt.Fprintf(stderr, "Usage: config %s [-key-env name | -key-file file] [-w] <input> [output]\n", name)
+		return exitError
+	}
+	raw := os.Getenv(*keyEnv)
+	if *keyFile != "" {
+		kb, err := os.ReadFile(*keyFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "config %s: %v\n", name, err)
+			return exitError
+		}
+		raw = string(kb)
+	}
+	key, err := cfg.ParseKey(raw)
+	if err != nil {
+		fmt.Fprintf(stderr, "config %s: %v\n", name, err)
+		return exitError
+	}
+	in, out := fs.Arg(0), fs.Arg(1)
+	if *inPlace {
+		out = in
+	}
+	f := formatOf(in)
+	b, err := os.ReadFile(in)
+	if err != nil {
+		fmt.Fprintf(stderr, "config %s: %v\n", name, err)
+		return exitError
+	}
+	if b, err = transform(b, f, key); err != nil {
+		fmt.Fprintf(stderr, "config %s: %s: %v\n", name, in, err)
+		return exitError
+	}
+	if out == "" {
+		stdout.Write(b)
+		return exitOK
+	}
+	if err = os.WriteFile(out, b, 0600); err != nil {
+		fmt.Fprintf(stderr, "config %s: %v\n", name, err)
+		return exitError
+	}
+	return exitOK
+}
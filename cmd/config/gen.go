@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runGen writes typed accessors of the flags and directories of a configuration file, for go:generate like
+//
+//	//go:generate go run github.com/eaglebush/config/cmd/config gen -package settings config.json settings_gen.go
+func runGen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	pkg := fs.String("package", "main", "package of the generated code")
+	typ := fs.String("type", "Settings", "type of the accessors")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fmt.Fprintln(stderr, "Usage: config gen [-package name] [-type name] <file> [output]")
+		return exitError
+	}
+	c, err := cfg.Load(fs.Arg(0), cfg.WithoutDefaults())
+	if c == nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+	b, err := generateAccessors(c, fs.Arg(0), *pkg, *typ)
+	if err != nil {
+		fmt.Fprintf(stderr, "config gen: %v\n", err)
+		return exitError
+	}
+	if fs.NArg() == 1 {
+		stdout.Write(b)
+		return exitOK
+	}
+	if err = os.WriteFile(fs.Arg(1), b, 0644); err != nil {
+		fmt.Fprintf(stderr, "config gen: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// generateAccessors writes the Go source of the accessors: a method by flag returning its value parsed
+// to the type of the value in the file, and a method by directory returning the accessors of its items
+func generateAccessors(c *cfg.Configuration, source, pkg, typ string) ([]byte, error) {
+	// the values of the placeholders are typed as written, so the code does not depend on the environment
+	raw := make(map[string]string)
+	for _, ip := range c.Interpolations() {
+		raw[strings.ToLower(ip.Path)] = ip.Raw
+	}
+	var flags []cfg.Flag
+	if c.Flags != nil {
+		flags = asWritten(*c.Flags, "flags", raw)
+	}
+	var dirs []cfg.DirectoryInfo
+	if c.Directories != nil {
+		dirs = *c.Directories
+	}
+
+	body := &bytes.Buffer{}
+	fmt.Fprintf(body, "// %s - typed accessors of the flags and directories of the configuration\n", typ)
+	fmt.Fprintf(body, "type %s struct {\nc *cfg.Configuration\n}\n\n", typ)
+	fmt.Fprintf(body, "// New%s returns the typed accessors of the configuration\n", typ)
+	fmt.Fprintf(body, "func New%s(c *cfg.Configuration) %s {\nreturn %s{c: c}\n}\n\n", typ, typ, typ)
+	durations, err := writeItems(body, typ, "s", "s.flags()", "flag", flags)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(body, "// flags returns the flags of the configuration\n")
+	fmt.Fprintf(body, "func (s %s) flags() []cfg.Flag {\nif s.c == nil || s.c.Flags == nil {\nreturn nil\n}\nreturn *s.c.Flags\n}\n", typ)
+
+	seen := make(map[string]string)
+	for i, d := range dirs {
+		name := identifier(d.GroupID)
+		if prev, ok := seen[name]; ok {
+			return nil, fmt.Errorf("directories %s and %s have the same name %s", prev, d.GroupID, name)
+		}
+		seen[name] = d.GroupID
+		dt := typ + name
+		fmt.Fprintf(body, "\n// %s gets the accessors of the items of the %s directory\n", name, d.GroupID)
+		fmt.Fprintf(body, "func (s %s) %s() %s {\nif s.c == nil {\nreturn %s{}\n}\nreturn %s{d: s.c.GetDirectory(%q)}\n}\n\n", typ, name, dt, dt, dt, d.GroupID)
+		fmt.Fprintf(body, "// %s - typed accessors of the items of the %s directory\n", dt, d.GroupID)
+		fmt.Fprintf(body, "type %s struct {\nd *cfg.DirectoryInfo\n}\n\n", dt)
+		dd, err := writeItems(body, dt, "d", "d.items()", "item", asWritten(d.Items, fmt.Sprintf("directories[%d].items", i), raw))
+		if err != nil {
+			return nil, fmt.Errorf("directory %s: %w", d.GroupID, err)
+		}
+		durations = durations || dd
+		fmt.Fprintf(body, "// items returns the items of the directory\n")
+		fmt.Fprintf(body, "func (d %s) items() []cfg.Flag {\nif d.d == nil {\nreturn nil\n}\nreturn d.d.Items\n}\n", dt)
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "// Code generated by config gen from %s. DO NOT EDIT.\n\npackage %s\n\nimport (\n", source, pkg)
+	if durations {
+		buf.WriteString("\"time\"\n\n")
+	}
+	buf.WriteString("cfg \"github.com/eaglebush/config\"\n)\n\n")
+	buf.Write(body.Bytes())
+	return format.Source(buf.Bytes())
+}
+
+// writeItems writes a method by flag returning its value parsed with GetFlagErr, and reports if a value is a duration
+func writeItems(w io.Writer, typ, recv, list, kind string, flags []cfg.Flag) (bool, error) {
+	sorted := append([]cfg.Flag(nil), flags...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Key < sorted[j].Key
+	})
+	durations := false
+	seen := make(map[string]string)
+	for _, f := range sorted {
+		name := identifier(f.Key)
+		if prev, ok := seen[name]; ok {
+			return false, fmt.Errorf("%ss %s and %s have the same name %s", kind, prev, f.Key, name)
+		}
+		seen[name] = f.Key
+		vt := "string"
+		if f.Value != nil {
+			vt = valueType(*f.Value)
+		}
+		durations = durations || vt == "time.Duration"
+		fmt.Fprintf(w, "// %s gets the %s %s\n", name, f.Key, kind)
+		fmt.Fprintf(w, "func (%s %s) %s() (%s, error) {\nreturn cfg.GetFlagErr[%s](%s, %q)\n}\n\n", recv, typ, name, vt, vt, list, f.Key)
+	}
+	return durations, nil
+}
+
+// asWritten returns copies of the flags at the path with the values of the placeholders as written
+func asWritten(flags []cfg.Flag, path string, raw map[string]string) []cfg.Flag {
+	out := make([]cfg.Flag, len(flags))
+	for i, f := range flags {
+		if r, ok := raw[fmt.Sprintf("%s[%d].value", path, i)]; ok {
+			f.Value = &r
+		}
+		out[i] = f
+	}
+	return out
+}
+
+// valueType infers the Go type of a flag value like true, 8080, 0.5 or 30s
+func valueType(v string) string {
+	v = strings.TrimSpace(v)
+	switch strings.ToLower(v) {
+	case "true", "false", "yes", "no", "on", "off", "enabled", "disabled":
+		return "bool"
+	}
+	if _, err := strconv.Atoi(v); err == nil {
+		return "int"
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return "float64"
+	}
+	if _, err := time.ParseDuration(v); err == nil {
+		return "time.Duration"
+	}
+	return "string"
+}
+
+// identifier converts a key like max_limit, max-limit or maxLimit to an exported Go identifier like MaxLimit
+func identifier(key string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	id := sb.String()
+	if id == "" || unicode.IsDigit([]rune(id)[0]) {
+		id = "X" + id
+	}
+	return id
+}
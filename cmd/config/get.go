@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runGet prints the value at a dot path of a configuration file.
+// Strings are printed as is while other values are printed as JSON.
+func runGet(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("get", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 2 {
+		fmt.Fprintln(stderr, "Usage: config get <file> <path>")
+		return exitError
+	}
+	fn, path := fs.Arg(0), fs.Arg(1)
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		fmt.Fprintf(stderr, "config get: %v\n", err)
+		return exitError
+	}
+	v, err := cfg.GetDocumentValue(b, formatOf(fn), path)
+	if err != nil {
+		fmt.Fprintf(stderr, "config get: %v\n", err)
+		return exitProblem
+	}
+	if s, ok := v.(string); ok {
+		fmt.Fprintln(stdout, s)
+		return exitOK
+	}
+	vb, err := json.Marshal(v)
+	if err != nil {
+		fmt.Fprintf(stderr, "config get: %v\n", err)
+		return exitError
+	}
+	fmt.Fprintln(stdout, string(vb))
+	return exitOK
+}
+
+// runSet sets the value at a dot path of a configuration file in place
+func runSet(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("set", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 3 {
+		fmt.Fprintln(stderr, "Usage: config set <file> <path> <value>")
+		return exitError
+	}
+	fn, path, value := fs.Arg(0), fs.Arg(1), fs.Arg(2)
+	fi, err := os.Stat(fn)
+	if err != nil {
+		fmt.Fprintf(stderr, "config set: %v\n", err)
+		return exitError
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		fmt.Fprintf(stderr, "config set: %v\n", err)
+		return exitError
+	}
+	if b, err = cfg.SetDocumentValue(b, formatOf(fn), path, value); err != nil {
+		fmt.Fprintf(stderr, "config set: %v\n", err)
+		return exitProblem
+	}
+	if err = os.WriteFile(fn, b, fi.Mode().Perm()); err != nil {
+		fmt.Fprintf(stderr, "config set: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// formatOf gets the format of a file, defaulting to JSON
+func formatOf(name string) cfg.Format {
+	if f := cfg.FormatOf(name); f != "" {
+		return f
+	}
+	return cfg.FormatJSON
+}
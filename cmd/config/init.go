@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runInit writes a template configuration with every section
+func runInit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "", "format of the template: json, yaml or toml. Inferred from the output extension, default is json. Only yaml keeps the comments")
+	force := fs.Bool("f", false, "overwrite the output file if it exists")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintln(stderr, "Usage: config init [-format format] [-f] [output]")
+		return exitError
+	}
+	out := fs.Arg(0)
+	f := cfg.Format(*format)
+	if f == "" && out != "" {
+		f = cfg.FormatOf(out)
+	}
+	if f == "" {
+		f = cfg.FormatJSON
+	}
+	b, err := cfg.Skeleton(f)
+	if err != nil {
+		fmt.Fprintf(stderr, "config init: %v\n", err)
+		return exitError
+	}
+	if out == "" {
+		stdout.Write(b)
+		return exitOK
+	}
+	if _, err = os.Stat(out); err == nil && !*force {
+		fmt.Fprintf(stderr, "config init: %s already exists, use -f to overwrite\n", out)
+		return exitError
+	}
+	if err = os.WriteFile(out, b, 0644); err != nil {
+		fmt.Fprintf(stderr, "config init: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// severityFlags - repeatable rule=severity flag
+type severityFlags map[string]cfg.Severity
+
+func (s severityFlags) String() string {
+	return ""
+}
+
+func (s severityFlags) Set(v string) error {
+	rule, level, ok := strings.Cut(v, "=")
+	if !ok {
+		return fmt.Errorf("expected rule=severity, got %q", v)
+	}
+	if _, known := cfg.DefaultSeverities[rule]; !known {
+		return fmt.Errorf("unknown rule %q", rule)
+	}
+	sev, err := cfg.ParseSeverity(level)
+	if err != nil {
+		return err
+	}
+	s[rule] = sev
+	return nil
+}
+
+// runLint reports deprecated, duplicate and suspicious settings of configuration files
+func runLint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	sevs := severityFlags{}
+	fs.Var(sevs, "severity", "override the severity of a rule as rule=off|info|warning|error. Can be repeated")
+	failOn := fs.String("fail-on", "warning", "minimum severity that fails the lint: info, warning or error")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	threshold, err := cfg.ParseSeverity(*failOn)
+	if err != nil || threshold == cfg.SeverityOff {
+		fmt.Fprintf(stderr, "config lint: invalid -fail-on %q\n", *failOn)
+		return exitError
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "Usage: config lint [-severity rule=level]... [-fail-on level] <file>...")
+		return exitError
+	}
+	code := exitOK
+	for _, fn := range fs.Args() {
+		b, err := os.ReadFile(fn)
+		if err != nil {
+			fmt.Fprintf(stderr, "config lint: %v\n", err)
+			code = exitError
+			continue
+		}
+		issues, err := cfg.LintDocument(b, formatOf(fn), sevs)
+		if err != nil {
+			fmt.Fprintf(stderr, "config lint: %s: %v\n", fn, err)
+			code = exitError
+			continue
+		}
+		for _, i := range issues {
+			fmt.Fprintf(stdout, "%s: %s\n", fn, i)
+			if i.Severity >= threshold && code == exitOK {
+				code = exitProblem
+			}
+		}
+	}
+	return code
+}
@@ -0,0 +1,77 @@
+// Command config validates and manages configuration files of github.com/eaglebush/config.
+//
+// Usage:
+//
+//	config <command> [arguments]
+//
+// Exit codes are 0 on success, 1 when problems are found and 2 on usage or load errors.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+const (
+	exitOK      = 0
+	exitProblem = 1
+	exitError   = 2
+)
+
+// command - a subcommand of the tool
+type command struct {
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = map[string]command{
+	"convert":  {usage: "convert [-from format] [-to format] <input> [output]", run: runConvert},
+	"docs":     {usage: "docs [output]", run: runDocs},
+	"diff":     {usage: "diff <file|url> <file|url>", run: runDiff},
+	"decrypt":  {usage: "decrypt [-key-env name | -key-file file] [-w] <input> [output]", run: runDecrypt},
+	"encrypt":  {usage: "encrypt [-key-env name | -key-file file] [-w] <input> [output]", run: runEncrypt},
+	"explain":  {usage: "explain [-redact=false] <file|url>", run: runExplain},
+	"gen":      {usage: "gen [-package name] [-type name] <file> [output]", run: runGen},
+	"get":      {usage: "get <file> <path>", run: runGet},
+	"init":     {usage: "init [-format format] [-f] [output]", run: runInit},
+	"lint":     {usage: "lint [-severity rule=level]... [-fail-on level] <file>...", run: runLint},
+	"render":   {usage: "render [-format format] [-redact] <file|url>", run: runRender},
+	"serve":    {usage: "serve [-addr address] [-tokens-env name] [-full-tokens-env name] <file|url>", run: runServe},
+	"set":      {usage: "set <file> <path> <value>", run: runSet},
+	"validate": {usage: "validate <file|url>...", run: runValidate},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run dispatches the subcommand and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitError
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "config: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitError
+	}
+	return cmd.run(args[1:], stdout, stderr)
+}
+
+// usage prints the available commands
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "Usage: config <command> [arguments]")
+	fmt.Fprintln(w, "Commands:")
+	for _, n := range names {
+		fmt.Fprintf(w, "  %s\n", commands[n].usage)
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.json")
+	err := os.WriteFile(bad, []byte(`{
+		"Databases": [{"ID": "A"}, {"ID": "a"}],
+		"Sessions": [{"ID": "web", "StoreType": "CACHE", "CacheID": "missing"}]
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"validate", "../../samples/config.mssql.json"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s%s`, exitOK, code, stdout.String(), stderr.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"validate", bad}, &stdout, &stderr); code != exitProblem {
+		t.Fatalf(`Expected exit code %d, got %d`, exitProblem, code)
+	}
+	out := stdout.String()
+	for _, s := range []string{"Databases[1].ID", "Sessions[0].CacheID"} {
+		if !strings.Contains(out, s) {
+			t.Errorf(`Expected problem on %s in %s`, s, out)
+		}
+	}
+
+	if code := run([]string{"validate", filepath.Join(dir, "missing.json")}, &stdout, &stderr); code != exitError {
+		t.Fatalf(`Expected exit code %d, got %d`, exitError, code)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "config.yaml")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"convert", "../../samples/config.mssql.json", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s`, exitOK, code, stderr.String())
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "ApplicationID: Default") {
+		t.Fatalf(`Unexpected YAML output %s`, b)
+	}
+}
+
+func TestInit(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "config.json")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"init", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s`, exitOK, code, stderr.String())
+	}
+	if code := run([]string{"init", out}, &stdout, &stderr); code != exitError {
+		t.Fatalf(`Expected existing file to be kept, got exit code %d`, code)
+	}
+	if code := run([]string{"validate", out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected template to be valid, got exit code %d: %s`, code, stdout.String())
+	}
+}
+
+func TestGetSet(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000, "Flags": [{"key": "Token", "value": "${TOKEN}"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"set", fn, "HostPort", "9000"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s`, exitOK, code, stderr.String())
+	}
+	if code := run([]string{"get", fn, "HostPort"}, &stdout, &stderr); code != exitOK || stdout.String() != "9000\n" {
+		t.Fatalf(`Unexpected get result %d %q`, code, stdout.String())
+	}
+	stdout.Reset()
+	if code := run([]string{"get", fn, "Flags[Token].value"}, &stdout, &stderr); code != exitOK || stdout.String() != "${TOKEN}\n" {
+		t.Fatalf(`Unexpected get result %d %q`, code, stdout.String())
+	}
+}
+
+func TestLint(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"JWTSecret": "${JWT_SECRET}"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"lint", fn}, &stdout, &stderr); code != exitProblem {
+		t.Fatalf(`Expected exit code %d, got %d`, exitProblem, code)
+	}
+	if code := run([]string{"lint", "-fail-on", "error", fn}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d`, exitOK, code)
+	}
+	stdout.Reset()
+	if code := run([]string{"lint", "-severity", "deprecated=off", fn}, &stdout, &stderr); code != exitOK || stdout.Len() > 0 {
+		t.Fatalf(`Expected no issues, got %d %s`, code, stdout.String())
+	}
+}
+
+func TestServe(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_TOKENS", "reader, other")
+	var handler http.Handler
+	listenAndServe = func(addr string, h http.Handler) error {
+		handler = h
+		return nil
+	}
+	defer func() { listenAndServe = http.ListenAndServe }()
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"serve", "-addr", ":9090", "-tokens-env", "CONFIG_TOKENS", fn}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s`, exitOK, code, stderr.String())
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer other")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"HostPort": 8000`) {
+		t.Fatalf(`Unexpected response %d %s`, rec.Code, rec.Body.String())
+	}
+}
+
+func TestGen(t *testing.T) {
+	dir := t.TempDir()
+	fn, out := filepath.Join(dir, "config.json"), filepath.Join(dir, "settings_gen.go")
+	doc := `{
+		"Flags": [
+			{"key": "max_limit", "value": "100"},
+			{"key": "Beta", "value": "on"},
+			{"key": "Timeout", "value": "30s"},
+			{"key": "Banner", "value": "${BANNER}"},
+			{"key": "Port", "value": "${PORT}"}
+		],
+		"Directories": [{"GroupID": "paths", "Items": [{"key": "upload", "value": "/var/upload"}, {"key": "ratio", "value": "0.5"}, {"key": "workers", "value": "${WORKERS}"}]}]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// the types do not depend on the environment of the generation
+	t.Setenv("PORT", "8080")
+	t.Setenv("WORKERS", "4")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"gen", "-package", "settings", fn, out}, &stdout, &stderr); code != exitOK {
+		t.Fatalf(`Expected exit code %d, got %d: %s`, exitOK, code, stderr.String())
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := string(b)
+	for _, s := range []string{
+		"package settings",
+		`func (s Settings) MaxLimit() (int, error) {`,
+		`return cfg.GetFlagErr[int](s.flags(), "max_limit")`,
+		`func (s Settings) Beta() (bool, error) {`,
+		`func (s Settings) Timeout() (time.Duration, error) {`,
+		`func (s Settings) Banner() (string, error) {`,
+		`func (s Settings) Paths() SettingsPaths {`,
+		`func (d SettingsPaths) Upload() (string, error) {`,
+		`func (d SettingsPaths) Ratio() (float64, error) {`,
+		`func (s Settings) Port() (string, error) {`,
+		`func (d SettingsPaths) Workers() (string, error) {`,
+	} {
+		if !strings.Contains(src, s) {
+			t.Fatalf(`Expected %s in the generated code %s`, s, src)
+		}
+	}
+	compileGenerated(t, b)
+
+	if err = os.WriteFile(fn, []byte(`{"Flags": [{"key": "max_limit"}, {"key": "MaxLimit"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code := run([]string{"gen", fn}, &stdout, &stderr); code != exitError {
+		t.Fatalf(`Expected the duplicate names to fail, got exit code %d`, code)
+	}
+}
+
+// compileGenerated builds the generated code in a package of the module, so it compiles against the configuration
+func compileGenerated(t *testing.T, src []byte) {
+	t.Helper()
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not available")
+	}
+	dir, err := os.MkdirTemp(".", "gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err = os.WriteFile(filepath.Join(dir, "settings_gen.go"), src, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command(gobin, "build", "./"+filepath.Base(dir)).CombinedOutput(); err != nil {
+		t.Fatalf(`Generated code does not compile: %v: %s`, err, out)
+	}
+}
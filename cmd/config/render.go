@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runRender prints the effective configuration after interpolation and defaulting
+func runRender(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	format := fs.String("format", "json", "format of the output: json, yaml or toml")
+	redact := fs.Bool("redact", false, "replace secrets with *****")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: config render [-format format] [-redact] <file|url>")
+		return exitError
+	}
+	c, err := cfg.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+	b, err := c.Render(cfg.Format(*format), *redact)
+	if err != nil {
+		fmt.Fprintf(stderr, "config render: %v\n", err)
+		return exitError
+	}
+	stdout.Write(b)
+	return exitOK
+}
+
+// runExplain prints the effective configuration in YAML with the environment variables
+// consumed by each interpolated field
+func runExplain(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	redact := fs.Bool("redact", true, "replace secrets with *****")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: config explain [-redact=false] <file|url>")
+		return exitError
+	}
+	c, err := cfg.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+	b, err := c.Explain(*redact)
+	if err != nil {
+		fmt.Fprintf(stderr, "config explain: %v\n", err)
+		return exitError
+	}
+	stdout.Write(b)
+	return exitOK
+}
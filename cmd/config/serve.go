@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// listenAndServe starts the server, replaced in tests
+var listenAndServe = http.ListenAndServe
+
+// runServe serves the configuration over HTTP for other services to load
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	tokensEnv := fs.String("tokens-env", "", "environment variable with comma-separated bearer tokens for the redacted configuration")
+	fullEnv := fs.String("full-tokens-env", "", "environment variable with comma-separated bearer tokens for the full configuration")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(stderr, "Usage: config serve [-addr address] [-tokens-env name] [-full-tokens-env name] <file|url>")
+		return exitError
+	}
+	c, err := cfg.Load(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+	srv := &cfg.Server{
+		Config:     c,
+		Tokens:     envTokens(*tokensEnv),
+		FullTokens: envTokens(*fullEnv),
+	}
+	fmt.Fprintf(stdout, "serving %s on %s\n", fs.Arg(0), *addr)
+	if err = listenAndServe(*addr, srv); err != nil {
+		fmt.Fprintf(stderr, "config serve: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// envTokens reads the comma-separated tokens of the environment variable
+func envTokens(name string) []string {
+	if name == "" {
+		return nil
+	}
+	tokens := make([]string, 0)
+	for _, t := range strings.Split(os.Getenv(name), ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
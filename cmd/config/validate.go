@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	cfg "github.com/eaglebush/config"
+)
+
+// runValidate loads each source and prints all problems found
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	quiet := fs.Bool("q", false, "print problems only")
+	if err := fs.Parse(args); err != nil {
+		return exitError
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "Usage: config validate [-q] <file|url>...")
+		return exitError
+	}
+	code := exitOK
+	for _, src := range fs.Args() {
+		c, err := cfg.Load(src)
+		var ve *cfg.ValidationError
+		if err != nil && !(errors.As(err, &ve) && c != nil) {
+			fmt.Fprintf(stderr, "%s: %v\n", src, err)
+			code = exitError
+			continue
+		}
+		if err = c.Validate(); err != nil {
+			if !errors.As(err, &ve) {
+				fmt.Fprintf(stderr, "%s: %v\n", src, err)
+				code = exitError
+				continue
+			}
+			for _, p := range ve.Problems {
+				fmt.Fprintf(stdout, "%s: %s\n", src, p)
+			}
+			if code == exitOK {
+				code = exitProblem
+			}
+			continue
+		}
+		if !*quiet {
+			fmt.Fprintf(stdout, "%s: ok\n", src)
+		}
+	}
+	return code
+}
@@ -0,0 +1,127 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// secretsInfix is inserted before the extension of the configuration file to name its companion secrets file
+const secretsInfix = ".secrets"
+
+// companionName returns the name of the companion secrets file like config.secrets.json for config.json
+func companionName(name string) string {
+	name = trimGzip(name)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + secretsInfix + ext
+}
+
+// readCompanion reads the companion secrets file of a local configuration file. It returns nil when there is none.
+func readCompanion(name string, lim Limits) (map[string]any, error) {
+	return readOverlay(companionName(name), lim)
+}
+
+// readOverlay reads a local file merged over the configuration file, like the companion secrets file.
+// It returns nil when there is none.
+func readOverlay(cn string, lim Limits) (map[string]any, error) {
+	f, err := os.Open(cn)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := readLimited(f, lim)
+	if err != nil {
+		return nil, err
+	}
+	format := FormatOf(cn)
+	if format == "" || format == FormatJSON {
+		format = FormatJSON
+		if b, err = sanitizeJSON(b, lim); err != nil {
+			if errors.Is(err, ErrLimitExceeded) {
+				return nil, err
+			}
+			return nil, wrapError(ErrDecode, fmt.Errorf("%s: %w", cn, err))
+		}
+	}
+	doc, err := decodeDocument(b, format)
+	if err != nil {
+		return nil, wrapError(ErrDecode, fmt.Errorf("%s: %w", cn, err))
+	}
+	normalizeKeys(doc, reflect.TypeOf(Configuration{}), "", nil)
+	return doc, nil
+}
+
+// companionValue - a value of the configuration file replaced by the companion secrets file
+type companionValue struct {
+	path   string // Path of the field, with the entries of sections selected by their key like Databases[DEFAULT]
+	value  any    // The value as written in the configuration file
+	absent bool   // The field is not in the configuration file
+}
+
+// companionOriginals returns the values of the document replaced by the values of the secrets document,
+// with the entries of sections selected by their identifying key
+func companionOriginals(doc, secrets map[string]any) []companionValue {
+	origs := make([]companionValue, 0)
+	var walk func(d, s any, path string)
+	walk = func(d, s any, path string) {
+		switch st := s.(type) {
+		case map[string]any:
+			dm, _ := d.(map[string]any)
+			for k, sv := range st {
+				dk, found := k, false
+				var dv any
+				if dm != nil {
+					dk, found = lookupKey(dm, k)
+					dv = dm[dk]
+				}
+				p := joinPath(path, dk)
+				switch sv.(type) {
+				case map[string]any, []any:
+					if found {
+						walk(dv, sv, p)
+						continue
+					}
+				}
+				origs = append(origs, companionValue{path: p, value: dv, absent: !found})
+			}
+		case []any:
+			ds, _ := d.([]any)
+			key := entryKey(ds, st)
+			if key == "" {
+				// the whole slice is replaced, keep it as written
+				origs = append(origs, companionValue{path: path, value: d})
+				return
+			}
+			for _, e := range st {
+				id := fieldValue(e.(map[string]any), key).(string)
+				i, ok := lookupEntry(ds, id)
+				if !ok {
+					origs = append(origs, companionValue{path: path + "[" + id + "]", absent: true})
+					continue
+				}
+				walk(ds[i], e, path+"["+id+"]")
+			}
+		}
+	}
+	walk(doc, secrets, "")
+	return origs
+}
+
+// restoreCompanion writes the values as written in the configuration file back to a document
+// for the values read from the companion secrets file, so they are never saved
+func restoreCompanion(doc map[string]any, origs []companionValue) {
+	for _, o := range origs {
+		if o.absent {
+			deletePath(doc, o.path)
+			continue
+		}
+		setPath(doc, o.path, o.value)
+	}
+}
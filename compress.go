@@ -0,0 +1,88 @@
+package cfg
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// gzipExt is the extension of gzipped configuration files like config.json.gz
+const gzipExt = ".gz"
+
+// isGzipName checks if the file name or URL has the gzip extension
+func isGzipName(name string) bool {
+	if i := strings.IndexAny(name, "?#"); i >= 0 && strings.Contains(name, "://") {
+		name = name[:i]
+	}
+	return strings.HasSuffix(strings.ToLower(name), gzipExt)
+}
+
+// trimGzip removes the gzip extension of a file name or URL, so config.json.gz is named like config.json
+func trimGzip(name string) string {
+	if i := strings.IndexAny(name, "?#"); i >= 0 && strings.Contains(name, "://") {
+		if isGzipName(name) {
+			return name[:i-len(gzipExt)] + name[i:]
+		}
+		return name
+	}
+	if isGzipName(name) {
+		return name[:len(name)-len(gzipExt)]
+	}
+	return name
+}
+
+// decodeContent returns the reader of the content decoded by the Content-Encoding of a response.
+// Content still gzipped after, like a config.json.gz file, is decompressed too.
+func decodeContent(r io.Reader, encoding string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	case "deflate":
+		// deflate is zlib wrapped, though some servers send the raw stream
+		br := bufio.NewReader(r)
+		if h, err := br.Peek(2); err == nil && h[0]&0x0f == 8 && (uint16(h[0])<<8|uint16(h[1]))%31 == 0 {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			return gunzip(zr)
+		}
+		return gunzip(flate.NewReader(br))
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %s", encoding)
+	}
+	return gunzip(r)
+}
+
+// gunzip returns the reader of the decompressed content when the content starts with the gzip header,
+// or else of the content as is
+func gunzip(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if h, err := br.Peek(2); err != nil || h[0] != 0x1f || h[1] != 0x8b {
+		return br, nil
+	}
+	return gzip.NewReader(br)
+}
+
+// compress gzips the document
+func compress(b []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
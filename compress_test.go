@@ -0,0 +1,101 @@
+package cfg
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadGzipFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json.gz")
+	b, err := compress([]byte(`{"ApplicationID": "orders"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(fn, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" {
+		t.Fatalf(`Unexpected application id %s`, *config.ApplicationID)
+	}
+
+	config.ApplicationID = new_string("billing")
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = os.ReadFile(fn); err != nil || !bytes.HasPrefix(b, []byte{0x1f, 0x8b}) {
+		t.Fatalf(`Expected the file to be saved gzipped, got %q, %v`, b, err)
+	}
+	if err = config.Reload(); err != nil || *config.ApplicationID != "billing" {
+		t.Fatalf(`Unexpected reload %v`, err)
+	}
+	if _, err = Load(fn, WithLimits(Limits{MaxSize: 10})); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf(`Expected the limit on the decompressed document, got %v`, err)
+	}
+}
+
+func TestLoadCompressedResponse(t *testing.T) {
+	doc := []byte(`{"ApplicationID": "orders"}`)
+	gz, err := compress(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var zb bytes.Buffer
+	zw := zlib.NewWriter(&zb)
+	zw.Write(doc)
+	zw.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf(`Unexpected Accept-Encoding %s`, r.Header.Get("Accept-Encoding"))
+		}
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gz)
+		case "/deflate":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", "deflate")
+			w.Write(zb.Bytes())
+		case "/config.json.gz":
+			w.Header().Set("Content-Type", "application/gzip")
+			w.Write(gz)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, p := range []string{"/gzip", "/deflate", "/config.json.gz"} {
+		config, err := Load(srv.URL + p)
+		if err != nil {
+			t.Fatalf(`Loading %s: %v`, p, err)
+		}
+		if *config.ApplicationID != "orders" {
+			t.Fatalf(`Unexpected application id %s from %s`, *config.ApplicationID, p)
+		}
+	}
+}
+
+func TestFormatOfGzip(t *testing.T) {
+	if f := FormatOf("config.yaml.gz"); f != FormatYAML {
+		t.Fatalf(`Unexpected format %q`, f)
+	}
+	if f := FormatOf("https://example.com/config.toml.gz?v=2"); f != FormatTOML {
+		t.Fatalf(`Unexpected format %q`, f)
+	}
+	if n := companionName("config.json.gz"); n != "config.secrets.json" {
+		t.Fatalf(`Unexpected companion %s`, n)
+	}
+}
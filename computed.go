@@ -0,0 +1,128 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var ErrFlagCycle = errors.New("flag expressions refer to each other")
+
+// formulaPattern matches the dependencies of a formula like {host}
+var formulaPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// computeFlags sets the values of the flags with an expression or a formula. Identifiers of the expressions
+// are the keys of other flags, computed first when they are computed, or the paths of fields like HostPort or
+// Databases[DEFAULT].Schema. Formulas refer to the flags they depend on.
+func (c *Configuration) computeFlags() error {
+	if c.Flags == nil {
+		return nil
+	}
+	flags := *c.Flags
+	var doc map[string]any
+	state := make(map[int]int) // 1 while computing, 2 when computed
+	var compute func(i int) error
+	compute = func(i int) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("%w: %s", ErrFlagCycle, flags[i].Key)
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		resolve := func(name string) (any, error) {
+			for j, f := range flags {
+				if !c.sameFlag(f.Key, name) {
+					continue
+				}
+				if f.computed() {
+					if err := compute(j); err != nil {
+						return nil, err
+					}
+				}
+				if flags[j].Value == nil {
+					return "", nil
+				}
+				return *flags[j].Value, nil
+			}
+			if doc == nil {
+				var err error
+				if doc, err = configDocument(c); err != nil {
+					return nil, err
+				}
+			}
+			v, err := getPath(doc, name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unknown flag or field %s", ErrExpression, name)
+			}
+			return v, nil
+		}
+		var (
+			v   any
+			err error
+		)
+		if flags[i].Expr != "" {
+			v, err = evalExpr(flags[i].Expr, resolve)
+		} else {
+			v, err = c.expandFormula(flags[i], resolve)
+		}
+		if err != nil {
+			return err
+		}
+		s := toText(v)
+		flags[i].Value = &s
+		state[i] = 2
+		return nil
+	}
+	for i, f := range flags {
+		if !f.computed() {
+			continue
+		}
+		if err := compute(i); err != nil {
+			field := "expr"
+			if f.Expr == "" {
+				field = "formula"
+			}
+			return fmt.Errorf("Flags[%d].%s: %w", i, field, err)
+		}
+	}
+	return nil
+}
+
+// computed checks if the value of the flag is computed by an expression or a formula
+func (f Flag) computed() bool {
+	return f.Expr != "" || f.Formula != "" || len(f.DependsOn) > 0
+}
+
+// expandFormula replaces the dependencies in braces of the formula of the flag with their values.
+// Only the flags the flag depends on can be referred to.
+func (c *Configuration) expandFormula(f Flag, resolve func(name string) (any, error)) (string, error) {
+	if f.Formula == "" {
+		return "", fmt.Errorf("%w: dependsOn without a formula", ErrExpression)
+	}
+	var ferr error
+	s := formulaPattern.ReplaceAllStringFunc(f.Formula, func(m string) string {
+		name := m[1 : len(m)-1]
+		if ferr != nil {
+			return m
+		}
+		known := false
+		for _, d := range f.DependsOn {
+			if c.sameFlag(d, name) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			ferr = fmt.Errorf("%w: %s is not in dependsOn of %q", ErrExpression, name, f.Formula)
+			return m
+		}
+		v, err := resolve(name)
+		if err != nil {
+			ferr = err
+			return m
+		}
+		return toText(v)
+	})
+	return s, ferr
+}
@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputedFlags(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"HostPort": 8000,
+		"Databases": [{"ID": "DEFAULT", "ConnectionString": "sqlserver://localhost", "Schema": "app"}],
+		"Flags": [
+			{"key": "max_workers", "expr": "HostPort > 0 ? 8 : 2"},
+			{"key": "Queue", "expr": "MaxWorkers * 2 + 1"},
+			{"key": "Table", "expr": "Databases[DEFAULT].Schema + \".orders\""},
+			{"key": "Beta", "value": "on"},
+			{"key": "BetaOff", "expr": "!Beta"}
+		]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"MaxWorkers": "8", "Queue": "17", "Table": "app.orders", "BetaOff": "false"} {
+		if v := config.Flag(key).Value; v == nil || *v != want {
+			t.Fatalf(`Unexpected flag %s %v, expected %s`, key, v, want)
+		}
+	}
+
+	for _, flags := range []string{
+		`[{"key": "A", "expr": "B + 1"}, {"key": "B", "expr": "A + 1"}]`,
+		`[{"key": "A", "expr": "Missing + 1"}]`,
+	} {
+		if err = os.WriteFile(fn, []byte(`{"Flags": `+flags+`}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = Load(fn); !errors.Is(err, ErrDecode) {
+			t.Fatalf(`Expected ErrDecode for %s, got %v`, flags, err)
+		}
+	}
+	if !errors.Is(err, ErrExpression) {
+		t.Fatalf(`Expected ErrExpression for an unknown identifier, got %v`, err)
+	}
+}
+
+func TestFormulaFlags(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	write := func(port string) {
+		doc := `{
+			"Flags": [
+				{"key": "url", "dependsOn": ["host", "port"], "formula": "http://{host}:{port}/api"},
+				{"key": "host", "value": "localhost"},
+				{"key": "port", "expr": "` + port + ` + 1"},
+				{"key": "health", "dependsOn": ["url"], "formula": "{url}/healthz"}
+			]
+		}`
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("8000")
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := config.Flag("health").Value; v == nil || *v != "http://localhost:8001/api/healthz" {
+		t.Fatalf(`Unexpected derived flag %v`, v)
+	}
+
+	// derived again on reload
+	write("9000")
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if v := config.Flag("url").Value; v == nil || *v != "http://localhost:9001/api" {
+		t.Fatalf(`Unexpected derived flag after reload %v`, v)
+	}
+
+	for _, flags := range []string{
+		`[{"key": "url", "dependsOn": ["host"], "formula": "{host}:{port}"}, {"key": "host", "value": "h"}, {"key": "port", "value": "1"}]`,
+		`[{"key": "a", "dependsOn": ["b"], "formula": "{b}"}, {"key": "b", "dependsOn": ["a"], "formula": "{a}"}]`,
+		`[{"key": "a", "dependsOn": ["b"]}, {"key": "b", "value": "1"}]`,
+	} {
+		if err = os.WriteFile(fn, []byte(`{"Flags": `+flags+`}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = Load(fn); !errors.Is(err, ErrDecode) {
+			t.Fatalf(`Expected ErrDecode for %s, got %v`, flags, err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cfg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// removedEntry - an entry of a section removed on load because its When condition was false
+type removedEntry struct {
+	section string // Key of the section in the document
+	index   int    // Index of the entry as written
+	entry   any    // The entry as written
+}
+
+// WithVariables sets the variables of the When conditions of the entries like env == "prod" && region == "ap".
+// The env variable is the environment selected by WithEnvironment or APP_ENV unless set. Missing variables are empty.
+// On reload, the variables of the loaded configuration are used when this option is not set.
+func WithVariables(vars map[string]string) Option {
+	return func(o *options) {
+		o.vars = vars
+	}
+}
+
+// applyConditions removes the entries of the sections whose When condition is false and returns them.
+// Only the sections whose entries have a When field are checked.
+func applyConditions(doc map[string]any, vars map[string]string, env string) ([]removedEntry, error) {
+	resolve := func(name string) (any, error) {
+		if v, ok := vars[name]; ok {
+			return v, nil
+		}
+		if name == "env" {
+			return env, nil
+		}
+		return "", nil
+	}
+	removed := make([]removedEntry, 0)
+	ct := reflect.TypeOf(Configuration{})
+	for i := 0; i < ct.NumField(); i++ {
+		f := ct.Field(i)
+		et := f.Type
+		for et.Kind() == reflect.Pointer || et.Kind() == reflect.Slice {
+			et = et.Elem()
+		}
+		if !f.IsExported() || et.Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := et.FieldByName("When"); !ok {
+			continue
+		}
+		k, ok := lookupKey(doc, jsonName(f))
+		entries, isSlice := doc[k].([]any)
+		if !ok || !isSlice {
+			continue
+		}
+		kept := make([]any, 0, len(entries))
+		for j, e := range entries {
+			m, isMap := e.(map[string]any)
+			when, _ := fieldValue(m, "When").(string)
+			if !isMap || when == "" {
+				kept = append(kept, e)
+				continue
+			}
+			v, err := evalExpr(when, resolve)
+			if err != nil {
+				return nil, fmt.Errorf("%s[%d].When: %w", k, j, err)
+			}
+			if truthy(v) {
+				kept = append(kept, e)
+				continue
+			}
+			removed = append(removed, removedEntry{section: k, index: j, entry: e})
+		}
+		doc[k] = kept
+	}
+	return removed, nil
+}
+
+// restoreRemoved inserts the removed entries back at their index as written
+func restoreRemoved(doc map[string]any, removed []removedEntry) {
+	rs := append([]removedEntry(nil), removed...)
+	sort.SliceStable(rs, func(i, j int) bool {
+		return rs[i].section < rs[j].section || (rs[i].section == rs[j].section && rs[i].index < rs[j].index)
+	})
+	for _, r := range rs {
+		k, _ := lookupKey(doc, r.section)
+		entries, _ := doc[k].([]any)
+		i := r.index
+		if i > len(entries) {
+			i = len(entries)
+		}
+		entries = append(entries, nil)
+		copy(entries[i+1:], entries[i:])
+		entries[i] = r.entry
+		doc[k] = entries
+	}
+}
@@ -0,0 +1,67 @@
+package cfg
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWhenConditions(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"APIEndpoints": [
+			{"ID": "main", "Address": "https://api.example.com"},
+			{"ID": "ap", "Address": "https://ap.example.com", "When": "env == \"prod\" && region == \"ap\""},
+			{"ID": "eu", "Address": "https://eu.example.com", "When": "region == 'eu'"}
+		]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithEnvironment("prod"), WithVariables(map[string]string{"region": "ap"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eps := *config.APIEndpoints; len(eps) != 2 || eps[1].ID != "ap" || config.GetEndpointInfo("eu") != nil {
+		t.Fatalf(`Unexpected endpoints %v`, eps)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*config.APIEndpoints) != 2 {
+		t.Fatal(`Expected the variables to be kept on reload`)
+	}
+
+	// the removed entries are saved
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved struct{ APIEndpoints []EndpointInfo }
+	if err = json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if eps := saved.APIEndpoints; len(eps) != 3 || eps[2].ID != "eu" || eps[1].When == "" {
+		t.Fatalf(`Unexpected saved endpoints %+v`, eps)
+	}
+
+	// missing variables are empty
+	if config, err = Load(fn); err != nil {
+		t.Fatal(err)
+	}
+	if len(*config.APIEndpoints) != 1 {
+		t.Fatalf(`Unexpected endpoints without variables %v`, *config.APIEndpoints)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"Databases": [{"ID": "DEFAULT", "When": "region =="}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(fn); !errors.Is(err, ErrDecode) || !errors.Is(err, ErrExpression) {
+		t.Fatalf(`Expected ErrDecode wrapping ErrExpression, got %v`, err)
+	}
+}
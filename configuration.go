@@ -1,13 +1,19 @@
 package cfg
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
-	"io"
+	"fmt"
 	"net/http"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type (
@@ -29,6 +35,7 @@ type (
 		GroupID     string
 		Description string
 		Items       []Flag
+		Directories []DirectoryInfo `json:",omitempty"` // Child directories looked up by path with GetDirectoryPath, by their GroupID
 	}
 
 	// Endpoint contains an endpoint info configuration
@@ -38,6 +45,33 @@ type (
 		Address string  // The absolute URL to the resource
 		GroupID *string // A group id to get certain endpoint set
 		Token   *string
+
+		Addresses    []string             `json:",omitempty"` // Absolute URLs of the resource on a cluster, selected by NextAddress. Address is used when empty
+		Strategy     string               `json:",omitempty"` // ROUND-ROBIN, RANDOM or FAILOVER selection of the Addresses. Default is ROUND-ROBIN
+		Breaker      *CircuitBreakerInfo  `json:",omitempty"` // Circuit breaker of the calls to the endpoint
+		Environments EnvironmentOverrides `json:",omitempty"` // Fields overridden per environment like {"prod": {"Address": "..."}}, selected by WithEnvironment or APP_ENV
+		When         string               `json:",omitempty"` // Condition like region == "ap" keeping the entry on load. See WithVariables
+		OAuthID      string               `json:",omitempty"` // OAuth provider whose client credentials token authorizes the calls of the EndpointClient
+		Signing      *SigningInfo         `json:",omitempty"` // Signing of the requests of the EndpointClient
+		balance      *balancers           // Selection state of the Addresses kept by the configuration
+	}
+
+	// CircuitBreakerInfo - circuit breaker setting of an endpoint
+	CircuitBreakerInfo struct {
+		FailureThreshold int       // Consecutive failures that open the circuit. Default is 5
+		OpenDuration     *Duration // Time the circuit stays open before probing, like 30s or a number of seconds. Default is 30s
+		HalfOpenProbes   int       // Probe calls let through while half-open. The circuit closes when they succeed. Default is 1
+	}
+
+	// SigningInfo - signing of the requests to an endpoint
+	SigningInfo struct {
+		Type      string // HMAC signs the body with the Secret, or AWS-SIGV4 signs the request with AWS Signature Version 4
+		Header    string `json:",omitempty"` // Header of the HMAC signature. Default is X-Signature
+		Algorithm string `json:",omitempty"` // Hash of the HMAC: SHA256, SHA512 or SHA1. Default is SHA256
+		Secret    string // Secret of the HMAC, or the AWS secret access key. Supports ${ENV} placeholders
+		KeyID     string `json:",omitempty"` // AWS access key id. AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are used when it is not set
+		Region    string `json:",omitempty"` // AWS region like ap-southeast-1. Default is AWS_REGION, or else us-east-1
+		Service   string `json:",omitempty"` // AWS service like execute-api or es
 	}
 
 	// OAuthProviderInfo for OAuth configuration
@@ -52,6 +86,19 @@ type (
 		ProviderApiUri string // The API URI to get authorization and access keys
 		ResponseType   string // The type of response that the application needs from the OAuth provider
 		Scope          string // The scope of access to resources
+		// OpenID Connect
+		IssuerUri               string   // The OpenID issuer URI where the discovery document is located
+		JwksUri                 string   // The URI of the JSON Web Key Set used to verify tokens
+		ClientSecret            string   // The secret of the application registered in the provider. Supports ${ENV} placeholders
+		RedirectUris            []string // The URIs where the provider redirects after authorization
+		TokenEndpointAuthMethod string   // The client authentication method at the token endpoint, like client_secret_basic, client_secret_post or none
+		TokenPath               string   // The path of the token endpoint when the ProviderApiUri is the base URI of the API, like /oauth/token
+		UsePKCE                 bool     // Sends a S256 code challenge on authorization and its verifier on the token request
+
+		discovery *discoveryCache  // Discovery documents fetched by the copies of the provider until the configuration is reloaded
+		tokens    *tokenCache      // Client credentials token shared by the copies of the provider until the configuration is reloaded
+		client    *http.Client     // Client of the token requests, the DefaultHTTPClient of the configuration
+		clock     func() time.Time // Current time, the clock of the configuration
 	}
 
 	// NotificationInfo - notification information on connecting to Notify API
@@ -67,10 +114,13 @@ type (
 		SenderName    string
 		ReplyTo       string
 		Recipients    []NotificationRecipient
+		Options       map[string]string `json:",omitempty"` // Provider specific options like region or api-version. Options named like secret, password, token or key are sensitive
+		When          string            `json:",omitempty"` // Condition like region == "ap" keeping the entry on load. See WithVariables
 	}
 
 	// CacheInfo connection information
 	CacheInfo struct {
+		ID       string
 		Provider string
 		Address  string
 		Password string
@@ -100,22 +150,32 @@ type (
 		GroupID                *string                // GroupID allows us to get groups of connection
 		ID                     string                 // A unique ID that will identify the connection to a database
 		ConnectionString       string                 // ConnectionString specific to the database
+		Connection             *ConnectionInfo        `json:",omitempty"` // Components of the connection string assembled by DSN when the ConnectionString is empty. Supports ${ENV} placeholders
+		ConnStringTemplate     string                 `json:",omitempty"` // Template of the connection string like server={{.Host}};database={{.Database}} on the Connection, instead of the format of the DriverName
 		DriverName             string                 // DriverName needs to be specified depending on the driver id used by the Go database driver
-		StorageType            string                 // FILE for filebased database such as Access, SQlite or LocalDB. SERVER for SQL Server, MySQL etc
+		StorageType            string                 // FILE for filebased database such as Access, SQlite or LocalDB. SERVER for SQL Server, MySQL etc. Default is SERVER
 		HelperID               string                 // When using github.com/NarsilWorks-Inc/datahelperlite, this is needed in the configuration file
 		ParameterPlaceholder   string                 // Parameter place holder for prepared statements. Default is '?'
 		ParameterInSequence    bool                   // Parameter place holder is in sequence. Default is false
 		Schema                 string                 // Schema for any of the database operations
-		InterpolateTables      *bool                  // Enables the tables to be interpolated with schema
+		InterpolateTables      *bool                  // Enables the tables to be interpolated with schema. Default is true
 		SequenceGenerator      *SequenceGeneratorInfo // Sequence generator configuration
-		StringEnclosingChar    *string                // Gets or sets the character that encloses a string in the query
-		StringEscapeChar       *string                // Gets or Sets the character that escapes a reserved character such as the character that encloses a s string
+		StringEnclosingChar    *string                // Gets or sets the character that encloses a string in the query. Default is '
+		StringEscapeChar       *string                // Gets or Sets the character that escapes a reserved character such as the character that encloses a s string. Default is \
 		MaxOpenConnection      *int                   // Maximum open connection
 		MaxIdleConnection      *int                   // Maximum idle connection
 		MaxConnectionLifetime  *int                   // Max connection lifetime
 		MaxConnectionIdleTime  *int                   // Max idle connection lifetime
 		Ping                   *bool                  // Ping connection
-		ReservedWordEscapeChar *string                // Reserved word escape chars. For escaping with different opening and closing characters, just set to both. Example. `[]` for SQL server
+		MigrationsPath         string                 `json:",omitempty"` // Directory of the schema migrations like migrations/orders, or a URL like file:///app/migrations
+		MigrationsTable        string                 `json:",omitempty"` // Table recording the applied migrations. Default is schema_migrations
+		AutoMigrate            bool                   `json:",omitempty"` // Runs the pending migrations at startup
+		DefaultQueryTimeout    *Duration              `json:",omitempty"` // Time limit of the queries run with QueryContext, like 30s or a number of seconds. Default is no limit
+		DefaultTxIsolation     string                 `json:",omitempty"` // Isolation of the transactions begun with TxOptions: READ-UNCOMMITTED, READ-COMMITTED, REPEATABLE-READ, SNAPSHOT or SERIALIZABLE. Default is the one of the driver
+		ApplicationNameTag     string                 `json:",omitempty"` // Application name reported to the server by the connection string returned by DSN
+		When                   string                 `json:",omitempty"` // Condition like region == "ap" keeping the entry on load. See WithVariables
+		Environments           EnvironmentOverrides   `json:",omitempty"` // Fields overridden per environment like {"prod": {"ConnectionString": "..."}}, selected by WithEnvironment or APP_ENV
+		ReservedWordEscapeChar *string                // Reserved word escape chars. For escaping with different opening and closing characters, just set to both. Example. `[]` for SQL server. Default is "
 	}
 
 	// NotificationRecipient - notification standard recipients
@@ -136,13 +196,153 @@ type (
 
 	// SourceInfo - file sources for configuration
 	SourceInfo struct {
-		ID        string // ID of the source for quick reference
-		Type      string // Type of Inbound file. Supported types are ORDER and SNAPSHOT
-		Source    string // Source folder of the source
-		Relative  bool   // Indicates that the Error and Success folders are relative to Source
-		Error     string // Error folder of the source
-		Success   string // Success folder of the source
-		Extension string // Extension of the file to pickup
+		ID            string // ID of the source for quick reference
+		Type          string // Type of Inbound file. Supported types are ORDER and SNAPSHOT
+		Source        string // Source folder of the source, or a URI like s3://bucket/inbound?region=ap-southeast-1 or sftp://host/inbound?fingerprint=SHA256:.... Other schemes need RegisterFolder
+		Username      string // User or access key id of a remote Source
+		Password      string // Password or secret access key of a remote Source. Supports ${ENV} placeholders
+		Relative      bool   // Indicates that the Error, Success and Archive folders are relative to Source
+		Error         string // Error folder of the source
+		Success       string // Success folder of the source
+		Archive       string // Archive folder of the source
+		Extension     string // Extension of the file to pickup
+		RetentionDays int    // Days the files are kept in the Error, Success and Archive folders. Zero keeps them
+		MaxFileSize   int64  // Largest file in bytes that is picked up. Larger files are moved to the Error folder. Zero is unlimited
+	}
+
+	// RateLimitInfo - rate limiting policy
+	RateLimitInfo struct {
+		ID       string // ID of the policy for quick reference
+		Requests int    // Number of requests allowed within the window
+		Window   int    // Window in seconds
+		Burst    int    // Number of requests allowed to exceed the limit momentarily
+		KeyBy    string // The key where the limit is counted against. Supported keys are IP, USER and API-KEY. Default is IP
+	}
+
+	// SessionInfo - session management setting
+	SessionInfo struct {
+		ID          string // ID of the session setting for quick reference
+		StoreType   string // Session store type. Supported types are MEMORY and CACHE. Default is MEMORY
+		CacheID     string // The cache id where sessions are stored when StoreType is CACHE
+		TTL         int    // Session time to live in seconds
+		IdleTimeout int    // Session idle timeout in seconds
+		CookieName  string // The name of the session cookie
+	}
+
+	// JobInfo - scheduled job setting
+	JobInfo struct {
+		ID       string  // ID of the job for quick reference
+		GroupID  *string // A group id to get certain job set
+		Schedule string  // Schedule in cron syntax. Descriptors such as @daily and @every 1h are also accepted
+		Enabled  bool    // Indicates that the job should run
+		Flags    []Flag  // Payload of the job
+		When     string  `json:",omitempty"` // Condition like region == "ap" keeping the entry on load. See WithVariables
+	}
+
+	// RetryInfo - retry policy
+	RetryInfo struct {
+		MaxAttempts int     // Maximum number of attempts including the first
+		Interval    int     // Interval between attempts in seconds
+		Multiplier  float64 // Multiplier applied to the interval after each attempt. Default is 1
+	}
+
+	// WebhookInfo - outbound webhook setting
+	WebhookInfo struct {
+		ID     string     // ID of the webhook for quick reference
+		URL    string     // The absolute URL where the events are posted
+		Secret string     // Secret for HMAC signing. Supports ${ENV} placeholders
+		Events []string   // Event names that trigger the webhook. An asterisk (*) matches all events
+		Retry  *RetryInfo // Retry policy when delivery fails
+		When   string     `json:",omitempty"` // Condition like region == "ap" keeping the entry on load. See WithVariables
+
+		memo *envMemo // Secret interpolated on access until the configuration is reloaded
+	}
+
+	// JWTKeyInfo - JSON Web Token key
+	JWTKeyInfo struct {
+		ID      string // Key id (kid) written on the token header
+		Key     string // The secret or PEM encoded key. Supports ${ENV} placeholders
+		KeyFile string // The file where the key is read when Key is not set
+	}
+
+	// JWTInfo - JSON Web Token setting
+	JWTInfo struct {
+		Algorithm  string       // Signing algorithm like HS256, RS256 or ES256. Default is HS256
+		Key        string       // The secret or PEM encoded private key. Supports ${ENV} placeholders
+		KeyFile    string       // The file where the key is read when Key is not set
+		KeyID      string       // Key id (kid) of the current key
+		Keys       []JWTKeyInfo // Previous keys that are still accepted on verification during key rotation
+		Issuer     string       // Issuer (iss) of the token
+		Audience   []string     // Audience (aud) of the token
+		AccessTTL  int          // Access token time to live in seconds
+		RefreshTTL int          // Refresh token time to live in seconds
+	}
+
+	// PasswordPolicyInfo - password and account policy
+	PasswordPolicyInfo struct {
+		MinLength        int  // Minimum number of characters
+		RequireUpper     bool // Requires at least one upper case letter
+		RequireLower     bool // Requires at least one lower case letter
+		RequireDigit     bool // Requires at least one digit
+		RequireSymbol    bool // Requires at least one symbol or punctuation
+		MinClasses       int  // Minimum number of character classes (upper, lower, digit, symbol) present
+		MaxAge           int  // Number of days before a password expires. Zero never expires
+		LockoutThreshold int  // Number of failed attempts before the account is locked. Zero disables lockout
+		LockoutDuration  int  // Lockout duration in seconds
+		HistorySize      int  // Number of previous passwords that cannot be reused
+	}
+
+	// ProxyInfo - outbound proxy setting
+	ProxyInfo struct {
+		HTTP     string // Proxy for HTTP requests
+		HTTPS    string // Proxy for HTTPS requests. Falls back to HTTP when not set
+		NoProxy  string // Comma separated hosts, domains and CIDRs that are not proxied
+		Username string // Proxy user. Supports ${ENV} placeholders
+		Password string // Proxy password. Supports ${ENV} placeholders
+
+		memo *envMemo // Values interpolated on access until the configuration is reloaded
+	}
+
+	// HTTPClientInfo - outbound HTTP client setting
+	HTTPClientInfo struct {
+		Timeout           *Duration // Time limit of a request including reading the response, like 30s or a number of seconds. Default is no limit
+		MaxIdleConns      int       // Maximum idle connections across all hosts. Default is 100
+		TLSMinVersion     string    // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3. Default is 1.2
+		DisableKeepAlives bool      // Closes the connections after each request
+	}
+
+	// MaintenanceWindowInfo - scheduled maintenance window
+	MaintenanceWindowInfo struct {
+		Start    string // Start of the window in 2006-01-02 15:04 format
+		End      string // End of the window in 2006-01-02 15:04 format
+		TimeZone string // IANA time zone of Start and End like Asia/Manila. Default is UTC
+	}
+
+	// MaintenanceInfo - maintenance setting
+	MaintenanceInfo struct {
+		Enabled    bool                    // Puts the application in maintenance regardless of the windows
+		Windows    []MaintenanceWindowInfo // Scheduled maintenance windows
+		AllowedIPs []string                // IP addresses or CIDRs that are allowed to pass during maintenance
+		Message    string                  // Message shown to clients during maintenance
+	}
+
+	// TenancyInfo - selection of the database of a tenant on sharded storage
+	TenancyInfo struct {
+		GroupID  string            // Database group of the shards of the tenants not mapped otherwise
+		Tenants  map[string]string // Database id by tenant id
+		Prefixes map[string]string // Database group id by tenant id prefix like eu- or us-. The longest prefix wins
+		Shards   int               // Number of shards the tenant ids are hashed to, the first databases of the group. Required with GroupID or Prefixes
+	}
+
+	// HealthInfo - health and readiness setting
+	HealthInfo struct {
+		LivenessPath  string   // Path of the liveness endpoint. Default is /healthz
+		ReadinessPath string   // Path of the readiness endpoint. Default is /readyz
+		DatabaseIDs   []string // Database ids checked on readiness
+		CacheID       string   // Cache id checked on readiness
+		EndpointIDs   []string // API endpoint ids checked on readiness
+		Queue         bool     // Indicates that the queue is checked on readiness
+		Timeout       int      // Timeout of each check in seconds. Default is 5
 	}
 
 	// Configuration
@@ -155,77 +355,469 @@ type (
 		Cache                 *CacheInfo           // Cache info of this application
 		CertificateFile       *string              // Certificate file
 		CertificateKey        *string              // Certificate private key
-		CookieDomain          *string              // The domain of the cookie that this application will send
+		CookieDomain          *string              // The domain of the cookie that this application will send. Default is localhost
 		CrossOriginDomains    *[]string            // Domains or endpoints that this application will allow
 		Databases             *[]DatabaseInfo      // Configured databases for this application use
 		Directories           *[]DirectoryInfo     // Configured directory for this application use
-		DefaultDatabaseID     *string              // The default database id that this application will find on the database configuration
-		DefaultEndpointID     *string              // The default endpoint that this application will find on the API endpoints configuration
-		DefaultNotificationID *string              // The default notification id that this application will find on the notification configuration
+		DefaultDatabaseID     *string              // The default database id that this application will find on the database configuration. Default is DEFAULT
+		DefaultEndpointID     *string              // The default endpoint that this application will find on the API endpoints configuration. Default is DEFAULT
+		DefaultNotificationID *string              // The default notification id that this application will find on the notification configuration. Default is DEFAULT
 		Domains               *[]DomainInfo        // Configured domains for this application use
 		FileName              string               // Filename of the current configuration
 		Flags                 *[]Flag              // Miscellaneous flags for this application use
+		Health                *HealthInfo          // Health and readiness setting
+		Inherits              *string              `json:",omitempty"` // File or URL of the base configuration whose values this configuration overrides, relative to this configuration
 		HostInternalURL       *string              // The internal host URL that this application will use to set returned resources and assets
 		HostExternalURL       *string              // The external host URL that this application will use to set returned resources and assets
+		HostListenAddress     *string              // The address the application binds like 0.0.0.0:8080 or unix:/tmp/app.sock. The HostPort is used when it has no port
 		HostPort              *int                 // The network port for the application
-		JWTSecret             *string              // Application wide JSON Web Token (JT) secret
+		HTTPClient            *HTTPClientInfo      // Outbound HTTP client of DefaultHTTPClient and of the remote configuration on reload
+		Jobs                  *[]JobInfo           // Scheduled jobs
+		JWT                   *JWTInfo             // JSON Web Token setting
+		JWTSecret             *string              // Deprecated: use JWT. Application wide JSON Web Token (JT) secret. Default is defaultsecretkey
 		LicenseSerial         *string              // License serial of this application
+		Maintenance           *MaintenanceInfo     // Maintenance setting
 		Notifications         *[]NotificationInfo  // Configured notifications for this application use
 		OAuths                *[]OAuthProviderInfo // OAuth definitions
+		PasswordPolicy        *PasswordPolicyInfo  // Password and account policy
+		Proxy                 *ProxyInfo           // Outbound proxy
 		Queue                 *QueueInfo           // Queue or message queue
-		ReadTimeout           *int                 // Default network timeout setting for reading data uploaded to this application
+		RateLimits            *[]RateLimitInfo     // Rate limiting policies
+		ReadTimeout           *Duration            // Default network timeout setting for reading data uploaded to this application, in seconds or like 30s or 2m
 		Secure                *bool                // Flags if secure
+		Sessions              *[]SessionInfo       // Session management settings
 		Sources               *[]SourceInfo        // Folder sources
-		WriteTimeout          *int                 // Default network timeout setting for writing data downloaded from this application
+		Tenancy               *TenancyInfo         // Database selection of the tenants
+		Webhooks              *[]WebhookInfo       // Outbound webhooks
+		WriteTimeout          *Duration            // Default network timeout setting for writing data downloaded from this application, in seconds or like 30s or 2m
 		local                 bool                 // Local file
+		interpolations        []Interpolation      // Fields interpolated on load
+		unknownKeys           []string             // Keys of the document that did not map to a field
+		warnings              []Warning            // Non-fatal problems found on load
+		defaulted             []string             // Fields defaulted on load
+		logger                Logger               // Logger of the configuration
+		instrumentation       Instrumentation      // Instrumentation of the configuration
+		stats                 *stats               // Counters shared across reloads
+		audit                 *accessAudit         // Access audit shared across reloads
+
+		lookupEnv func(string) (string, bool) // Looks up the environment variables of the placeholders
+		clock     func() time.Time            // Current time
+		limits    *Limits                     // Limits enforced on loading the document
+		verifier  signatureVerifier           // Verifies the detached signature of the document
+		bundle    *BundleMetadata             // Metadata of the bundle the configuration was loaded from
+		checkLive func(*Configuration) error  // Checks the configuration after a reload, rolling back on failure
+		previous  *Configuration              // Last known good configuration before the reload
+		history   *history                    // Versions shared across reloads
+		loader    *Loader                     // Layers the configuration was loaded from
+		bearer    string                      // Bearer token sent on fetching remote configuration
+		key       []byte                      // Key decrypting the encrypted values
+		source    Source                      // Custom source the configuration was loaded from
+		keyPolicy KeyPolicy                   // Policy comparing the keys and ids of the lookups
+		templates bool                        // Evaluates the templates of the values on load
+		originals []original                  // Values as written, replaced on load
+		appEnv    string                      // Environment whose overrides were applied on load
+		vars      map[string]string           // Variables of the When conditions
+		removed   []removedEntry              // Entries removed on load by their When condition
+		companion map[string]any              // Document of the companion secrets file merged on parsing
+		secrets   []companionValue            // Values replaced by the companion secrets file, never saved
+		chain     []string                    // Files of the inheritance chain, from the base to this configuration
+		inherited []companionValue            // Values inherited from the base configurations, never saved
+		origins   map[string]string           // File of the inheritance chain that set each value, by lower case path
+		memo      *envMemo                    // Values interpolated on access until reloaded or RefreshEnv is called
+		raw       bool                        // Skips the implicit defaults on load
+		phrase    string                      // Passphrase of the encrypted configuration file, encrypting it again on Save
+		hostname  string                      // Host name of the override file
+		noHost    bool                        // Skips the override file of the host name
+		platform  string                      // Operating system and architecture of the override files
+		exec      *execPolicy                 // Commands allowed to run for the ${exec:...} placeholders
+		files     *filePolicy                 // Files allowed to be read for the ${file:...} placeholders
+		suffixIDs bool                        // Looks up the ids suffixed with the environment first
+		layers    map[string]Provenance       // Layer that set each value, by lower case path
+		readOnly  bool                        // Rejects the changes and copies the entries found by the lookups
+		refreshed *refreshState               // Time of the last load or reload, shared across reloads
+		redirects *int                        // Redirects followed on fetching a remote configuration
+		client    *http.Client                // Client of the HTTPClient and Proxy sections, built on load
+		digest    string                      // SHA-256 of the loaded document in hex
+		balance   *balancers                  // Selection state of the addresses of the endpoints until reloaded
+		format    Format                      // Format of the document as written, kept by Save for YAML and TOML
+		aliases   map[string]string           // Keys as written like host_port by the path of their field, saved as written
+		overlays  []overlay                   // Override files merged on parsing
+		overlaid  []string                    // Override files merged on load
+		overrides []overrideValue             // Values replaced by the override files, saved when changed
 	}
 )
 
 var (
 	ErrNoDataFromSource = errors.New(`no data from source for configuration`)
 	ErrSaveNotLocalFile = errors.New("configuration file is not local")
+	ErrSessionNoCache   = errors.New("session cache id does not refer to a configured cache")
+	ErrInvalidSchedule  = errors.New("invalid job schedule")
+	ErrInvalidWindow    = errors.New("invalid maintenance window")
+	ErrHealthReference  = errors.New("health check refers to an unknown dependency")
+	ErrTenancyReference = errors.New("tenancy refers to an unknown database")
+	ErrRemoteFetch      = errors.New("failed to fetch remote configuration")
+	ErrHTTPStatus       = errors.New("unexpected http status of remote configuration")
+	ErrContentType      = errors.New("unexpected content type of remote configuration")
+	ErrDecode           = errors.New("failed to decode configuration")
+	ErrValidation       = errors.New("configuration is not valid")
+	ErrSecretResolution = errors.New("failed to resolve secret")
+	ErrRolledBack       = errors.New("configuration reload was rolled back")
+	ErrNoRollback       = errors.New("no previous configuration to roll back to")
+	ErrCanaryAborted    = errors.New("canary reload was aborted")
+	ErrListenAddress    = errors.New("invalid listen address")
+	ErrOAuthReference   = errors.New("endpoint refers to an unknown oauth provider")
 )
 
-func load(source string) (*Configuration, error) {
-	config := &Configuration{}
-	if !(strings.HasPrefix(source, `http://`) || strings.HasPrefix(source, `https://`)) {
-		config.local = true
+// readSource reads a local file or fetches a remote source within the size limit
+func readSource(ctx context.Context, source string, local bool, o *options, lim Limits) ([]byte, error) {
+	b, _, err := fetchSource(ctx, source, local, o, lim, false)
+	return b, err
+}
+
+// readDocument reads a local file or fetches a remote configuration document within the size limit.
+// Remote documents are requested with an Accept header of the known formats, and the format of their
+// Content-Type is returned. Content types of no known format, like an HTML error page, are rejected.
+// Gzipped files like config.json.gz and responses compressed with gzip or deflate are decompressed,
+// with the size limit applied to the decompressed document.
+func readDocument(ctx context.Context, source string, local bool, o *options, lim Limits) ([]byte, Format, error) {
+	return fetchSource(ctx, source, local, o, lim, true)
+}
+
+// fetchSource reads a local file or fetches a remote source, negotiating the format of a document
+func fetchSource(ctx context.Context, source string, local bool, o *options, lim Limits, document bool) ([]byte, Format, error) {
+	if local {
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, "", err
+		}
+		defer f.Close()
+		r, err := gunzip(f)
+		if err != nil {
+			return nil, "", wrapError(ErrDecode, err)
+		}
+		b, err := readLimited(r, lim)
+		return b, "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, "", wrapError(ErrRemoteFetch, err)
+	}
+	if o.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+o.bearer)
+	}
+	if document {
+		req.Header.Set("Accept", acceptHeader())
+	}
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	nr, err := o.fetchClient().Do(req)
+	if err != nil {
+		return nil, "", wrapError(ErrRemoteFetch, err)
+	}
+	defer nr.Body.Close()
+	if err = checkStatus(nr); err != nil {
+		return nil, "", wrapError(ErrRemoteFetch, err)
 	}
 
-	var (
-		err error
-		b   []byte
-	)
-	if config.local {
-		b, err = os.ReadFile(source)
-	} else {
-		b, err =
-			func() ([]byte, error) {
-				var ob []byte
-				nr, err := http.Get(source)
-				if err != nil {
-					return ob, err
-				}
-				defer nr.Body.Close()
+	var format Format
+	if ct := nr.Header.Get("Content-Type"); document && ct != "" {
+		format = FormatOfContentType(ct)
+		if mt := mediaType(ct); format == "" && !genericType(mt) {
+			return nil, "", wrapError(ErrRemoteFetch, fmt.Errorf("%w: %s", ErrContentType, mt))
+		}
+	}
+	body, err := decodeContent(nr.Body, nr.Header.Get("Content-Encoding"))
+	if err != nil {
+		return nil, "", wrapError(ErrRemoteFetch, err)
+	}
+	b, err := readLimited(body, lim)
+	if errors.Is(err, ErrLimitExceeded) {
+		return b, "", err
+	}
+	if err != nil {
+		return b, "", wrapError(ErrRemoteFetch, err)
+	}
+	return b, format, nil
+}
 
-				ob, err = io.ReadAll(nr.Body)
-				if err != nil {
-					return ob, err
-				}
-				return ob, nil
-			}()
+// newConfiguration returns an empty configuration with the options carried on reload
+func newConfiguration(o *options) *Configuration {
+	c := &Configuration{
+		logger:          o.logger,
+		instrumentation: o.instrumentation,
+		lookupEnv:       o.lookupEnv,
+		clock:           o.clock,
+		limits:          o.limits,
+		verifier:        o.verifier,
+		checkLive:       o.checkLive,
+		bearer:          o.bearer,
+		key:             o.key,
+		templates:       o.templates,
+		raw:             o.raw,
+		hostname:        o.hostname,
+		noHost:          o.noHost,
+		platform:        o.platform,
+		exec:            o.exec,
+		files:           o.files,
+		suffixIDs:       o.suffixIDs,
+		readOnly:        o.readOnly,
+		redirects:       o.redirects,
+		memo:            newEnvMemo(o.lookupEnv),
 	}
+	if o.keyPolicy != nil {
+		c.keyPolicy = *o.keyPolicy
+	}
+	return c
+}
+
+func load(source string, o *options) (*Configuration, error) {
+	config := newConfiguration(o)
+	if strings.HasPrefix(source, envSource) {
+		b, err := envDocument(strings.TrimPrefix(source, envSource), os.Environ())
+		if err != nil {
+			return nil, err
+		}
+		return parse(config, source, b, o)
+	}
+	if !(strings.HasPrefix(source, `http://`) || strings.HasPrefix(source, `https://`)) {
+		config.local = true
+	}
+
+	lim := o.limits.withDefaults()
+	b, format, err := readDocument(context.Background(), source, config.local, o, lim)
 	if err != nil {
 		return config, err
 	}
 	if len(b) == 0 {
 		return config, ErrNoDataFromSource
 	}
+	if format == "" {
+		// local files and responses without a content type are decoded by their extension
+		format = FormatOf(source)
+	}
+	config.format = format
+	if o.verifier != nil {
+		if err = verifySource(context.Background(), source, config.local, b, o); err != nil {
+			return nil, err
+		}
+	}
+	if b, config.phrase, err = unsealDocument(b, o); err != nil {
+		return nil, err
+	}
+	if config.local {
+		if config.overlays, err = readOverlays(source, o, lim); err != nil {
+			return nil, err
+		}
+		if config.companion, err = readCompanion(source, lim); err != nil {
+			return nil, err
+		}
+	}
+	if format != "" && format != FormatJSON {
+		// the document is parsed as JSON
+		doc, err := decodeDocument(b, format)
+		if err != nil {
+			return nil, wrapError(ErrDecode, err)
+		}
+		if b, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+	return parse(config, source, b, o)
+}
+
+// parse decodes, interpolates, decrypts, defaults and validates the document into the configuration
+func parse(config *Configuration, source string, b []byte, o *options) (*Configuration, error) {
+	if err := o.exec.check(); err != nil {
+		return nil, err
+	}
+	if err := o.files.check(); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	config.digest = hex.EncodeToString(sum[:])
+	b, err := sanitizeJSON(b, o.limits.withDefaults())
+	if err != nil {
+		if errors.Is(err, ErrLimitExceeded) {
+			return nil, err
+		}
+		return nil, wrapError(ErrDecode, err)
+	}
+	doc, err := decodeDocument(b, FormatJSON)
+	if err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+	config.aliases = make(map[string]string)
+	normalizeKeys(doc, reflect.TypeOf(config), "", config.aliases)
+	if config.layers == nil {
+		// the layers of the loader are recorded on merging them
+		config.layers = make(map[string]Provenance)
+		layer := LayerFile
+		if strings.HasPrefix(source, envSource) {
+			layer = LayerEnv
+		}
+		recordLayers(config.layers, doc, Provenance{Layer: layer, Source: source})
+	}
+	if config.loader == nil && !strings.HasPrefix(source, envSource) {
+		if doc, config.chain, config.inherited, config.origins, err = resolveInherits(doc, source, o, o.limits.withDefaults()); err != nil {
+			return nil, err
+		}
+		for p, f := range config.origins {
+			if f != source {
+				config.layers[p] = Provenance{Layer: LayerBase, Source: f}
+			}
+		}
+	}
+	if len(config.overlays) > 0 {
+		config.overrides = applyOverlays(doc, config.overlays)
+		for _, ov := range config.overlays {
+			config.overlaid = append(config.overlaid, ov.name)
+			recordLayers(config.layers, ov.doc, Provenance{Layer: LayerOverride, Source: ov.name})
+			config.log().Debug("override file merged", "file", ov.name)
+		}
+		config.overlays = nil
+	}
+	if config.companion != nil {
+		config.secrets = companionOriginals(doc, config.companion)
+		mergeDocuments(doc, config.companion)
+		recordLayers(config.layers, config.companion, Provenance{Layer: LayerSecrets, Source: companionName(source)})
+		config.companion = nil
+	}
+	config.unknownKeys = unknownKeys(doc, reflect.TypeOf(config), "")
+	for _, k := range config.unknownKeys {
+		config.log().Info("key does not map to a field", "key", k)
+	}
+	if _, ok := lookupKey(doc, "JWTSecret"); ok {
+		config.warnings = append(config.warnings, Warning{Path: "JWTSecret", Message: "JWTSecret is deprecated, use the JWT section"})
+		config.log().Warn("field is deprecated", "field", "JWTSecret")
+	}
+	config.appEnv, config.vars = o.environment(), o.vars
+	if config.removed, err = applyConditions(doc, o.vars, config.appEnv); err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+	config.originals = applyEnvironments(doc, config.appEnv)
+	for _, og := range config.originals {
+		recordLayer(config.layers, canonicalPath(doc, og.path), Provenance{Layer: LayerEnvironment, Source: config.appEnv})
+	}
+	if config.interpolations, err = interpolateDocument(doc, o.env(), config.resolvers()); err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+	for _, ip := range config.interpolations {
+		recordLayer(config.layers, canonicalPath(doc, ip.Path), Provenance{Layer: LayerEnv, Source: placeholderSource(ip.Raw)})
+		// the effective values are only in the document, the values as written are saved
+		if v, err := getPath(doc, ip.Path); err == nil {
+			if s, ok := v.(string); ok {
+				config.originals = append(config.originals, original{path: ip.Path, raw: ip.Raw, value: s})
+			}
+		}
+		for _, v := range ip.Vars {
+			if !v.Set {
+				config.warnings = append(config.warnings, Warning{Path: ip.Path, Message: "environment variable " + v.Name + " is not set"})
+				config.log().Warn("environment variable is not set", "field", ip.Path, "variable", v.Name)
+			}
+		}
+	}
+	if o.templates {
+		origs, err := templateDocument(doc, o.env())
+		if err != nil {
+			return nil, wrapError(ErrDecode, err)
+		}
+		config.originals = append(config.originals, origs...)
+	}
+	if o.key != nil {
+		origs, err := decryptSecrets(doc, o.key)
+		if err != nil {
+			return nil, wrapError(ErrSecretResolution, err)
+		}
+		config.originals = append(config.originals, origs...)
+	}
+	config.memo.keep(config.originals)
+	if b, err = json.Marshal(doc); err != nil {
+		return nil, err
+	}
 	err = json.Unmarshal(b, config)
+	if err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+
+	before, err := configDocument(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if !o.raw {
+		setDefaults(config)
+	}
+
+	attachState(config)
+
+	after, err := configDocument(config)
 	if err != nil {
 		return nil, err
 	}
+	loadedInherited(after, config.inherited)
+	loadedOverrides(after, config.overrides)
+	config.reportDefaulted(before, after)
+	if err = config.computeFlags(); err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+
+	if err = config.validate(); err != nil {
+		config.log().Warn("configuration is not valid", "source", source, "error", err)
+		return config, err
+	}
+
+	config.FileName = source
+	config.log().Debug("configuration loaded", "source", source)
+	return config, nil
+}
+
+// attachState sets the state of the sections shared by the copies of the entries until reloaded,
+// keeping the state of the entries that have one
+func attachState(config *Configuration) {
+	config.client = httpClient(config.Proxy, config.HTTPClient)
+	if config.APIEndpoints != nil {
+		if config.balance == nil {
+			config.balance = &balancers{byID: make(map[string]*balancer)}
+		}
+		eps := *config.APIEndpoints
+		for i := range eps {
+			eps[i].balance = config.balance
+		}
+	}
+	if config.OAuths != nil {
+		oas := *config.OAuths
+		var dc *discoveryCache
+		for i := range oas {
+			if oas[i].discovery != nil {
+				dc = oas[i].discovery
+				break
+			}
+		}
+		if dc == nil {
+			dc = &discoveryCache{docs: make(map[string]*oidcDiscovery)}
+		}
+		for i := range oas {
+			oas[i].discovery = dc
+			if oas[i].tokens == nil {
+				oas[i].tokens = &tokenCache{}
+			}
+			oas[i].client = config.client
+			oas[i].clock = config.clock
+		}
+	}
+	if config.Webhooks != nil {
+		whs := *config.Webhooks
+		for i := range whs {
+			whs[i].memo = config.memo
+		}
+	}
+	if config.Proxy != nil {
+		config.Proxy.memo = config.memo
+	}
+}
 
+// setDefaults sets the implicit defaults of the fields not set, like the DEFAULT ids, the localhost
+// cookie domain and the characters of the databases
+func setDefaults(config *Configuration) {
 	const def string = `DEFAULT`
 	if config.DefaultDatabaseID == nil || *config.DefaultDatabaseID == "" {
 		config.DefaultDatabaseID = new_string(def)
@@ -242,6 +834,13 @@ func load(source string) (*Configuration, error) {
 	if config.JWTSecret == nil {
 		config.JWTSecret = new_string(`defaultsecretkey`)
 	}
+	if config.JWT != nil {
+		if config.JWT.Algorithm == "" {
+			config.JWT.Algorithm = `HS256`
+		} else {
+			config.JWT.Algorithm = strings.ToUpper(config.JWT.Algorithm)
+		}
+	}
 	// Default setting for database
 	if config.Databases != nil {
 		dbs := *config.Databases
@@ -272,6 +871,98 @@ func load(source string) (*Configuration, error) {
 		config.Databases = &dbs
 	}
 
+	// Default setting for rate limits
+	if config.RateLimits != nil {
+		rls := *config.RateLimits
+		for i, rl := range rls {
+			if rl.KeyBy == "" {
+				rl.KeyBy = `IP`
+			} else {
+				rl.KeyBy = strings.ToUpper(rl.KeyBy)
+			}
+			rls[i] = rl
+		}
+		config.RateLimits = &rls
+	}
+
+	// Default setting for cache
+	if config.Cache != nil && config.Cache.ID == "" {
+		config.Cache.ID = def
+	}
+
+	// Default setting for sessions
+	if config.Sessions != nil {
+		sss := *config.Sessions
+		for i, ss := range sss {
+			if ss.StoreType == "" {
+				ss.StoreType = `MEMORY`
+			} else {
+				ss.StoreType = strings.ToUpper(ss.StoreType)
+			}
+			if ss.StoreType == `CACHE` && ss.CacheID == "" {
+				ss.CacheID = def
+			}
+			if ss.CookieName == "" {
+				ss.CookieName = `session`
+			}
+			sss[i] = ss
+		}
+		config.Sessions = &sss
+	}
+
+	// Default setting for endpoints
+	if config.APIEndpoints != nil {
+		eps := *config.APIEndpoints
+		for i, ep := range eps {
+			if ep.Breaker != nil {
+				if ep.Breaker.FailureThreshold == 0 {
+					ep.Breaker.FailureThreshold = 5
+				}
+				if ep.Breaker.OpenDuration == nil {
+					d := Duration(30 * time.Second)
+					ep.Breaker.OpenDuration = &d
+				}
+				if ep.Breaker.HalfOpenProbes == 0 {
+					ep.Breaker.HalfOpenProbes = 1
+				}
+			}
+			if len(ep.Addresses) > 0 {
+				if ep.Strategy == "" {
+					ep.Strategy = StrategyRoundRobin
+				} else {
+					ep.Strategy = strings.ToUpper(ep.Strategy)
+				}
+			}
+			eps[i] = ep
+		}
+		config.APIEndpoints = &eps
+	}
+
+	// Default setting for webhooks
+	if config.Webhooks != nil {
+		whs := *config.Webhooks
+		for i, wh := range whs {
+			if wh.Retry != nil && wh.Retry.Multiplier <= 0 {
+				wh.Retry.Multiplier = 1
+			}
+			whs[i] = wh
+		}
+		config.Webhooks = &whs
+	}
+
+	// Default setting for health
+	if config.Health != nil {
+		if config.Health.LivenessPath == "" {
+			config.Health.LivenessPath = `/healthz`
+		}
+		if config.Health.ReadinessPath == "" {
+			config.Health.ReadinessPath = `/readyz`
+		}
+		if config.Health.Timeout <= 0 {
+			config.Health.Timeout = 5
+		}
+	}
+
 	// check if there is a notification
 	defnum := ""
 	if config.Notifications != nil {
@@ -286,19 +977,20 @@ func load(source string) (*Configuration, error) {
 		}
 		config.Notifications = &nfs
 	}
-
-	config.FileName = source
-	return config, nil
 }
 
 // GetDatabaseInfo get a database info by its ID
 func (c *Configuration) GetDatabaseInfo(id string) *DatabaseInfo {
+	c = c.view()
 	if c.Databases == nil {
 		return nil
 	}
-	for _, v := range *c.Databases {
-		if v.ID == id {
-			return &v
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.Databases {
+			if c.sameKey(v.ID, k) {
+				c.record(`Databases`, v.ID)
+				return guard(c, &v)
+			}
 		}
 	}
 	return nil
@@ -306,6 +998,7 @@ func (c *Configuration) GetDatabaseInfo(id string) *DatabaseInfo {
 
 // GetDatabaseInfoGroup gets database infos based on the group id
 func (c *Configuration) GetDatabaseInfoGroup(groupId string) []DatabaseInfo {
+	c = c.view()
 	dbgi := make([]DatabaseInfo, 0)
 	if c.Databases == nil || groupId == "" {
 		return dbgi
@@ -314,8 +1007,9 @@ func (c *Configuration) GetDatabaseInfoGroup(groupId string) []DatabaseInfo {
 		if v.GroupID == nil {
 			continue
 		}
-		if strings.EqualFold(*v.GroupID, groupId) {
-			dbgi = append(dbgi, v)
+		if c.sameKey(*v.GroupID, groupId) {
+			c.record(`Databases`, v.ID)
+			dbgi = append(dbgi, *guard(c, &v))
 		}
 	}
 	return dbgi
@@ -323,12 +1017,14 @@ func (c *Configuration) GetDatabaseInfoGroup(groupId string) []DatabaseInfo {
 
 // GetDirectory retrieves a directory under a group
 func (c *Configuration) GetDirectory(groupId string) *DirectoryInfo {
+	c = c.view()
 	if c.Directories == nil || len(*c.Directories) == 0 {
 		return nil
 	}
 	for _, dir := range *c.Directories {
-		if strings.EqualFold(dir.GroupID, groupId) {
-			return &dir
+		if c.sameKey(dir.GroupID, groupId) {
+			c.record(`Directories`, dir.GroupID)
+			return guard(c, &dir)
 		}
 	}
 	return nil
@@ -336,13 +1032,56 @@ func (c *Configuration) GetDirectory(groupId string) *DirectoryInfo {
 
 // GetDirectoryItem retrieves a directory item under a group
 func (c *Configuration) GetDirectoryItem(groupId, key string) *Flag {
+	c = c.view()
 	dir := c.GetDirectory(groupId)
 	if dir == nil {
 		return nil
 	}
 	for _, item := range dir.Items {
-		if strings.EqualFold(item.Key, key) {
-			return &item
+		if c.sameKey(item.Key, key) {
+			return guard(c, &item)
+		}
+	}
+	return nil
+}
+
+// GetDirectoryPath retrieves a directory by the path of group ids from a top directory through its
+// child directories, like billing/exports
+func (c *Configuration) GetDirectoryPath(path string) *DirectoryInfo {
+	c = c.view()
+	names := strings.Split(strings.Trim(path, "/"), "/")
+	dir := c.GetDirectory(names[0])
+	for _, name := range names[1:] {
+		if dir == nil {
+			return nil
+		}
+		var child *DirectoryInfo
+		for i := range dir.Directories {
+			if c.sameKey(dir.Directories[i].GroupID, name) {
+				child = &dir.Directories[i]
+				break
+			}
+		}
+		dir = child
+	}
+	return dir
+}
+
+// GetDirectoryItemPath retrieves a directory item by the path of its directory and its key, like billing/exports/format
+func (c *Configuration) GetDirectoryItemPath(path string) *Flag {
+	c = c.view()
+	i := strings.LastIndex(strings.Trim(path, "/"), "/")
+	if i < 0 {
+		return nil
+	}
+	path = strings.Trim(path, "/")
+	dir := c.GetDirectoryPath(path[:i])
+	if dir == nil {
+		return nil
+	}
+	for _, item := range dir.Items {
+		if c.sameKey(item.Key, path[i+1:]) {
+			return guard(c, &item)
 		}
 	}
 	return nil
@@ -350,37 +1089,80 @@ func (c *Configuration) GetDirectoryItem(groupId, key string) *Flag {
 
 // GetDomainInfo gets a domain info by name
 func (c *Configuration) GetDomainInfo(domainName string) *DomainInfo {
+	c = c.view()
 	if c.Domains == nil || domainName == "" {
 		return nil
 	}
 	for _, v := range *c.Domains {
-		if strings.EqualFold(v.Name, domainName) {
-			return &v
+		if c.sameKey(v.Name, domainName) {
+			c.record(`Domains`, v.Name)
+			return guard(c, &v)
 		}
 	}
 	return nil
 }
 
-// GetEndpointInfo - get an endpoint by id
+// GetEndpointInfo - get an endpoint by id. An empty id gets the DefaultEndpointID endpoint.
+// When no endpoint has the id but it is a group id, the default of the group is returned:
+// the endpoint of the group with the DefaultEndpointID, or else the first endpoint of the group.
 func (c *Configuration) GetEndpointInfo(id string) *EndpointInfo {
-	if c.APIEndpoints == nil || (len(id) == 0 && (c.DefaultEndpointID == nil || *c.DefaultEndpointID == "")) {
+	return c.view().endpoint(id)
+}
+
+// GetEndpointInfoOrDefault gets an endpoint like GetEndpointInfo, falling back to the DefaultEndpointID endpoint
+// when the id is neither an endpoint nor a group
+func (c *Configuration) GetEndpointInfoOrDefault(id string) *EndpointInfo {
+	c = c.view()
+	if ep := c.endpoint(id); ep != nil {
+		return ep
+	}
+	return c.endpoint("")
+}
+
+// endpoint gets an endpoint by id or the default of the group with the id.
+// An empty id gets the DefaultEndpointID endpoint.
+func (c *Configuration) endpoint(id string) *EndpointInfo {
+	if c.APIEndpoints == nil {
+		return nil
+	}
+	def := ""
+	if c.DefaultEndpointID != nil {
+		def = *c.DefaultEndpointID
+	}
+	if len(id) == 0 {
+		id = def
+	}
+	if len(id) == 0 {
 		return nil
 	}
-	k := strings.ToLower(*c.DefaultEndpointID)
-	if len(id) > 0 {
-		k = strings.ToLower(id)
+	for _, k := range c.envIDs(id) {
+		for _, ep := range *c.APIEndpoints {
+			if c.sameKey(k, ep.ID) {
+				c.record(`APIEndpoints`, ep.ID)
+				return guard(c, &ep)
+			}
+		}
 	}
-	eps := *c.APIEndpoints
-	for _, ep := range eps {
-		if strings.EqualFold(k, ep.ID) {
-			return &ep
+	var group *EndpointInfo
+	for i, ep := range *c.APIEndpoints {
+		if ep.GroupID == nil || !c.sameKey(id, *ep.GroupID) {
+			continue
+		}
+		if group == nil || (def != "" && c.sameKey(def, ep.ID)) {
+			group = &(*c.APIEndpoints)[i]
 		}
 	}
-	return nil
+	if group == nil {
+		return nil
+	}
+	ep := *group
+	c.record(`APIEndpoints`, ep.ID)
+	return guard(c, &ep)
 }
 
 // GetDatabaseInfoGroup gets database infos based on the group id
 func (c *Configuration) GetEndpointInfoGroup(groupId string) []EndpointInfo {
+	c = c.view()
 	eps := make([]EndpointInfo, 0)
 	if c.APIEndpoints == nil {
 		return eps
@@ -389,26 +1171,66 @@ func (c *Configuration) GetEndpointInfoGroup(groupId string) []EndpointInfo {
 		if ep.GroupID == nil {
 			continue
 		}
-		if strings.EqualFold(*ep.GroupID, groupId) {
-			eps = append(eps, ep)
+		if c.sameKey(*ep.GroupID, groupId) {
+			c.record(`APIEndpoints`, ep.ID)
+			eps = append(eps, *guard(c, &ep))
 		}
 	}
 	return eps
 }
 
+// GetJobInfo gets a job by id
+func (c *Configuration) GetJobInfo(id string) *JobInfo {
+	c = c.view()
+	if c.Jobs == nil || id == "" {
+		return nil
+	}
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.Jobs {
+			if c.sameKey(v.ID, k) {
+				c.record(`Jobs`, v.ID)
+				return guard(c, &v)
+			}
+		}
+	}
+	return nil
+}
+
+// GetJobsByGroup gets jobs based on the group id
+func (c *Configuration) GetJobsByGroup(groupId string) []JobInfo {
+	c = c.view()
+	jbs := make([]JobInfo, 0)
+	if c.Jobs == nil || groupId == "" {
+		return jbs
+	}
+	for _, v := range *c.Jobs {
+		if v.GroupID == nil {
+			continue
+		}
+		if c.sameKey(*v.GroupID, groupId) {
+			c.record(`Jobs`, v.ID)
+			jbs = append(jbs, *guard(c, &v))
+		}
+	}
+	return jbs
+}
+
 // GetNotificationInfo gets notification info
 func (c *Configuration) GetNotificationInfo(id string) *NotificationInfo {
+	c = c.view()
 	if c.Notifications == nil || (len(id) == 0 && (c.DefaultNotificationID == nil || *c.DefaultNotificationID == "")) {
 		return nil
 	}
-	k := strings.ToLower(*c.DefaultNotificationID)
-	if len(id) > 0 {
-		k = strings.ToLower(id)
+	if len(id) == 0 {
+		id = *c.DefaultNotificationID
 	}
 	nfs := *c.Notifications
-	for _, nf := range nfs {
-		if strings.EqualFold(k, nf.ID) {
-			return &nf
+	for _, k := range c.envIDs(id) {
+		for _, nf := range nfs {
+			if c.sameKey(k, nf.ID) {
+				c.record(`Notifications`, nf.ID)
+				return guard(c, &nf)
+			}
 		}
 	}
 	return nil
@@ -416,12 +1238,16 @@ func (c *Configuration) GetNotificationInfo(id string) *NotificationInfo {
 
 // GetSourceInfo gets source by id
 func (c *Configuration) GetSourceInfo(id string) *SourceInfo {
+	c = c.view()
 	if c.Sources == nil || id == "" {
 		return nil
 	}
-	for _, v := range *c.Sources {
-		if strings.EqualFold(v.ID, id) {
-			return &v
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.Sources {
+			if c.sameKey(v.ID, k) {
+				c.record(`Sources`, v.ID)
+				return guard(c, &v)
+			}
 		}
 	}
 	return nil
@@ -429,45 +1255,375 @@ func (c *Configuration) GetSourceInfo(id string) *SourceInfo {
 
 // GetOAuthInfo gets an OAuth info by id
 func (c *Configuration) GetOAuthInfo(id string) *OAuthProviderInfo {
+	c = c.view()
 	if c.OAuths == nil || len(*c.OAuths) == 0 || len(id) == 0 {
 		return nil
 	}
-	for _, oa := range *c.OAuths {
-		if strings.EqualFold(id, oa.ID) {
-			return &oa
+	for _, k := range c.envIDs(id) {
+		for _, oa := range *c.OAuths {
+			if c.sameKey(k, oa.ID) {
+				c.record(`OAuths`, oa.ID)
+				return guard(c, &oa)
+			}
+		}
+	}
+	return nil
+}
+
+// GetRateLimit gets a rate limit policy by id
+func (c *Configuration) GetRateLimit(id string) *RateLimitInfo {
+	c = c.view()
+	if c.RateLimits == nil || id == "" {
+		return nil
+	}
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.RateLimits {
+			if c.sameKey(v.ID, k) {
+				c.record(`RateLimits`, v.ID)
+				return guard(c, &v)
+			}
+		}
+	}
+	return nil
+}
+
+// GetSessionInfo gets a session setting by id
+func (c *Configuration) GetSessionInfo(id string) *SessionInfo {
+	c = c.view()
+	if c.Sessions == nil || id == "" {
+		return nil
+	}
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.Sessions {
+			if c.sameKey(v.ID, k) {
+				c.record(`Sessions`, v.ID)
+				return guard(c, &v)
+			}
+		}
+	}
+	return nil
+}
+
+// GetWebhookInfo gets a webhook by id
+func (c *Configuration) GetWebhookInfo(id string) *WebhookInfo {
+	c = c.view()
+	if c.Webhooks == nil || id == "" {
+		return nil
+	}
+	for _, k := range c.envIDs(id) {
+		for _, v := range *c.Webhooks {
+			if c.sameKey(v.ID, k) {
+				c.record(`Webhooks`, v.ID)
+				return guard(c, &v)
+			}
 		}
 	}
 	return nil
 }
 
+// GetWebhooksByEvent gets webhooks subscribed to an event
+func (c *Configuration) GetWebhooksByEvent(event string) []WebhookInfo {
+	c = c.view()
+	whs := make([]WebhookInfo, 0)
+	if c.Webhooks == nil || event == "" {
+		return whs
+	}
+	for _, v := range *c.Webhooks {
+		for _, e := range v.Events {
+			if e == "*" || strings.EqualFold(e, event) {
+				c.record(`Webhooks`, v.ID)
+				whs = append(whs, *guard(c, &v))
+				break
+			}
+		}
+	}
+	return whs
+}
+
 // Save saves configuration file
 func (c *Configuration) Save() error {
-	if c.local {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	if !c.local {
 		return ErrSaveNotLocalFile
 	}
+	if c.bundle != nil {
+		return ErrSaveBundle
+	}
 	b, err := json.MarshalIndent(c, "", "\t")
 	if err != nil {
 		return err
 	}
+	// YAML and TOML files are written back in their format, other formats as JSON
+	format := FormatJSON
+	if c.format == FormatYAML || c.format == FormatTOML {
+		format = c.format
+	}
+	if len(c.originals) > 0 || len(c.removed) > 0 || len(c.secrets) > 0 || len(c.inherited) > 0 || len(c.overrides) > 0 || len(c.aliases) > 0 || format != FormatJSON {
+		doc, err := configDocument(c)
+		if err != nil {
+			return err
+		}
+		restoreInherited(doc, c.inherited)
+		restoreOriginals(doc, c.originals)
+		restoreRemoved(doc, c.removed)
+		restoreCompanion(doc, c.secrets)
+		restoreOverrides(doc, c.overrides)
+		restoreAliases(doc, c.aliases)
+		if b, err = encodeDocument(doc, format); err != nil {
+			return err
+		}
+	}
+	switch {
+	case c.phrase != "":
+		if b, err = EncryptWithPassphrase(c.phrase, b); err != nil {
+			return err
+		}
+	case isGzipName(c.FileName):
+		if b, err = compress(b); err != nil {
+			return err
+		}
+	}
 	if err = os.WriteFile(c.FileName, b, os.ModePerm); err != nil {
 		return err
 	}
+	c.log().Debug("configuration saved", "file", c.FileName)
 	return nil
 }
 
 // Load loads configuration file and return a configuration
-func Load(source string) (*Configuration, error) {
-	return load(source)
+func Load(source string, opts ...Option) (*Configuration, error) {
+	o := newOptions(opts...)
+	start := o.now()
+	config, err := load(source, o)
+	if config != nil && o.audit {
+		config.audit = &accessAudit{counts: make(map[string]uint64)}
+	}
+	if config != nil && err == nil && o.history != nil {
+		config.history = o.history
+		config.snapshot(EventLoad)
+	}
+	if config != nil && err == nil {
+		config.touch(o.now())
+	}
+	if config != nil {
+		config.observe(EventLoad, source, start, err)
+	} else {
+		(&Configuration{instrumentation: o.instrumentation, clock: o.clock}).observe(EventLoad, source, start, err)
+	}
+	if config != nil && err == nil {
+		// started last, since the refreshes swap the configuration
+		config.autoRefresh(o)
+	}
+	return config, err
+}
+
+// Reload configuration. The lookups like GetDatabaseInfo, Flag and Render can run meanwhile from other
+// goroutines, as with the watchers and WithAutoRefresh, but reading or setting the fields directly cannot.
+func (c *Configuration) Reload(opts ...Option) error {
+	return c.reload(newOptions(opts...), nil)
+}
+
+// ReloadWith reloads the configuration into a candidate and runs the validators on it.
+// The configuration is only replaced when the candidate loads and all validators pass.
+func (c *Configuration) ReloadWith(validators ...func(*Configuration) error) error {
+	return c.reload(newOptions(), validators)
+}
+
+// Rollback restores the configuration before the last successful reload
+func (c *Configuration) Rollback() error {
+	cur := c.view()
+	if err := cur.writable(); err != nil {
+		return err
+	}
+	if cur.previous == nil {
+		return ErrNoRollback
+	}
+	start := cur.now()
+	prev := cur.previous
+	c.swap(prev)
+	prev.snapshot(EventRollback)
+	prev.log().Info("configuration rolled back", "source", prev.FileName)
+	prev.observe(EventRollback, prev.FileName, start, nil)
+	return nil
+}
+
+// ReloadCanary reloads the configuration into a candidate and hands it to the verify callback,
+// which can exercise it like opening a test database connection. The configuration is only replaced
+// when the callback succeeds within the timeout. On timeout or cancellation of the context, the reload
+// is aborted without waiting for the callback, which should stop on the done context.
+func (c *Configuration) ReloadCanary(ctx context.Context, timeout time.Duration, verify func(context.Context, *Configuration) error) error {
+	return c.reload(newOptions(), []func(*Configuration) error{func(nc *Configuration) error {
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		done := make(chan error, 1)
+		go func() {
+			done <- verify(ctx, nc)
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				return wrapError(ErrCanaryAborted, err)
+			}
+			return nil
+		case <-ctx.Done():
+			return wrapError(ErrCanaryAborted, ctx.Err())
+		}
+	}})
 }
 
-// Reload configuration
-func (c *Configuration) Reload() error {
-	_, err := load(c.FileName)
-	return err
+// swapMu guards the configurations replaced by the reloads, the rollbacks and RefreshEnv, including from
+// the watchers, against the lookups reading them
+var swapMu sync.RWMutex
+
+// view returns a copy of the configuration taken under the lock of the swaps, sharing its sections.
+// Lookups read the copy so a watcher can swap the configuration meanwhile.
+func (c *Configuration) view() *Configuration {
+	swapMu.RLock()
+	defer swapMu.RUnlock()
+	v := *c
+	return &v
+}
+
+// swap replaces the configuration under the lock of the swaps
+func (c *Configuration) swap(nc *Configuration) {
+	swapMu.Lock()
+	defer swapMu.Unlock()
+	*c = *nc
+}
+
+// reload loads a candidate configuration, validates it and swaps it in
+func (c *Configuration) reload(o *options, validators []func(*Configuration) error) error {
+	cur := c.view()
+	if err := cur.writable(); err != nil {
+		return err
+	}
+	if o.proxy == nil {
+		o.proxy = cur.Proxy
+	}
+	if o.logger == nil {
+		o.logger = cur.logger
+	}
+	if o.instrumentation == nil {
+		o.instrumentation = cur.instrumentation
+	}
+	if o.lookupEnv == nil {
+		o.lookupEnv = cur.lookupEnv
+	}
+	if o.clock == nil {
+		o.clock = cur.clock
+	}
+	if o.limits == nil {
+		o.limits = cur.limits
+	}
+	if o.verifier == nil {
+		o.verifier = cur.verifier
+	}
+	if o.checkLive == nil {
+		o.checkLive = cur.checkLive
+	}
+	if o.bearer == "" {
+		o.bearer = cur.bearer
+	}
+	if o.key == nil {
+		o.key = cur.key
+	}
+	if o.redirects == nil {
+		o.redirects = cur.redirects
+	}
+	if o.transport == nil {
+		o.transport = cur.HTTPClient
+	}
+	if !o.templates {
+		o.templates = cur.templates
+	}
+	if !o.raw {
+		o.raw = cur.raw
+	}
+	if o.passphrase == "" && o.passEnv == "" {
+		o.passphrase = cur.phrase
+	}
+	if o.hostname == "" {
+		o.hostname = cur.hostname
+	}
+	if !o.noHost {
+		o.noHost = cur.noHost
+	}
+	if o.platform == "" {
+		o.platform = cur.platform
+	}
+	if o.exec == nil {
+		o.exec = cur.exec
+	}
+	if o.files == nil {
+		o.files = cur.files
+	}
+	if !o.suffixIDs {
+		o.suffixIDs = cur.suffixIDs
+	}
+	if o.appEnv == "" {
+		o.appEnv = cur.appEnv
+	}
+	if o.vars == nil {
+		o.vars = cur.vars
+	}
+	if o.keyPolicy == nil {
+		kp := cur.keyPolicy
+		o.keyPolicy = &kp
+	}
+	start := o.now()
+	var (
+		nc  *Configuration
+		err error
+	)
+	switch {
+	case cur.loader != nil:
+		nc, err = cur.loader.load(context.Background(), o)
+	case cur.source != nil:
+		nc, err = loadSource(context.Background(), cur.source, o)
+	case cur.bundle != nil:
+		nc, err = loadBundle(cur.FileName, o)
+	default:
+		nc, err = load(cur.FileName, o)
+	}
+	for i := 0; err == nil && i < len(validators); i++ {
+		if verr := validators[i](nc); verr != nil {
+			err = wrapError(ErrValidation, verr)
+		}
+	}
+	if err != nil {
+		cur.log().Warn("configuration reload failed", "source", cur.FileName, "error", err)
+		cur.observe(EventReload, cur.FileName, start, err)
+		return err
+	}
+	nc.stats, nc.audit, nc.history, nc.refreshed = cur.stats, cur.audit, cur.history, cur.refreshed
+	prev := *cur
+	prev.previous = nil
+	nc.previous = &prev
+	c.swap(nc)
+	if o.checkLive != nil {
+		if err = o.checkLive(c); err != nil {
+			c.swap(cur)
+			err = wrapError(ErrRolledBack, err)
+			cur.log().Warn("configuration reload rolled back", "source", cur.FileName, "error", err)
+			cur.observe(EventReload, cur.FileName, start, err)
+			return err
+		}
+	}
+	nc.snapshot(EventReload)
+	nc.touch(nc.now())
+	nc.log().Debug("configuration reloaded", "source", nc.FileName)
+	nc.observe(EventReload, nc.FileName, start, nil)
+	return nil
 }
 
 // Flag gets a flag value
 func (c *Configuration) Flag(key string) Flag {
+	c = c.view()
 	key = strings.TrimSpace(key)
 	ret := Flag{
 		Key:   key,
@@ -476,16 +1632,12 @@ func (c *Configuration) Flag(key string) Flag {
 	if c.Flags == nil {
 		return ret
 	}
-	// get flags to loop from
-	// also loop from variations
-	// of convention, like underscore
-	// and dash
+	// variations of convention like underscores
+	// and dashes match under the default key policy
 	for _, f := range *c.Flags {
-		for _, v := range []string{"_", "-"} {
-			ki := strings.ReplaceAll(f.Key, v, "")
-			if strings.EqualFold(key, ki) {
-				return f
-			}
+		if c.sameFlag(key, f.Key) {
+			c.record(`Flags`, f.Key)
+			return *guard(c, &f)
 		}
 	}
 
@@ -1,9 +1,14 @@
 package cfg
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 type EmbeddedConfiguration struct {
@@ -60,3 +65,374 @@ func TestLoadURLConfig(t *testing.T) {
 	// 	fmt.Printf("%s", config.LastErrorText())
 	// }
 }
+
+func TestGetRateLimit(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	rl := config.GetRateLimit("PUBLIC")
+	if rl == nil {
+		t.Fatal(`Rate limit public not found`)
+	}
+	if rl.Requests != 100 || rl.Window != 60 || rl.Burst != 20 {
+		t.Fatalf(`Unexpected rate limit %+v`, rl)
+	}
+	if rl.KeyBy != `API-KEY` {
+		t.Fatalf(`Expected KeyBy API-KEY, got %s`, rl.KeyBy)
+	}
+	if config.GetRateLimit("none") != nil {
+		t.Fatal(`Expected nil for unknown rate limit`)
+	}
+}
+
+func TestGetEndpointInfoFallback(t *testing.T) {
+	config := Configuration{
+		DefaultEndpointID: new_string("main"),
+		APIEndpoints: &[]EndpointInfo{
+			{ID: "main", Address: "https://api.example.com"},
+			{ID: "pay-eu", Address: "https://eu.pay.example.com", GroupID: new_string("payments")},
+			{ID: "pay-us", Address: "https://us.pay.example.com", GroupID: new_string("payments")},
+		},
+	}
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"pay-us", "pay-us"},
+		{"PAYMENTS", "pay-eu"},
+		{"", "main"},
+		{"unknown", ""},
+	}
+	for _, tt := range tests {
+		ep := config.GetEndpointInfo(tt.id)
+		if (ep == nil && tt.want != "") || (ep != nil && ep.ID != tt.want) {
+			t.Fatalf(`Unexpected endpoint %+v for %q, expected %q`, ep, tt.id, tt.want)
+		}
+	}
+	if ep := config.GetEndpointInfoOrDefault("unknown"); ep == nil || ep.ID != "main" {
+		t.Fatalf(`Expected the default endpoint, got %+v`, ep)
+	}
+	config.DefaultEndpointID = nil
+	if ep := config.GetEndpointInfoOrDefault("unknown"); ep != nil {
+		t.Fatalf(`Expected no endpoint without a default, got %+v`, ep)
+	}
+	config.DefaultEndpointID = new_string("main")
+
+	// the default endpoint is the default of its group
+	(*config.APIEndpoints)[0].GroupID = new_string("payments")
+	if ep := config.GetEndpointInfo("payments"); ep == nil || ep.ID != "main" {
+		t.Fatalf(`Expected the default endpoint for the group, got %+v`, ep)
+	}
+}
+
+func TestGetDirectoryItemPath(t *testing.T) {
+	config := Configuration{
+		Directories: &[]DirectoryInfo{{
+			GroupID: "billing",
+			Items:   []Flag{{Key: "currency", Value: new_string("PHP")}},
+			Directories: []DirectoryInfo{{
+				GroupID: "exports",
+				Items:   []Flag{{Key: "format", Value: new_string("csv")}},
+			}},
+		}},
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"billing/exports/format", "csv"},
+		{"/Billing/Exports/FORMAT", "csv"},
+		{"billing/currency", "PHP"},
+		{"billing/imports/format", ""},
+		{"billing/exports/missing", ""},
+		{"billing", ""},
+	}
+	for _, tt := range tests {
+		item := config.GetDirectoryItemPath(tt.path)
+		if (item == nil && tt.want != "") || (item != nil && *item.Value != tt.want) {
+			t.Fatalf(`Unexpected item %+v for %q, expected %q`, item, tt.path, tt.want)
+		}
+	}
+	if dir := config.GetDirectoryPath("billing/exports"); dir == nil || dir.GroupID != "exports" {
+		t.Fatalf(`Unexpected directory %+v`, dir)
+	}
+}
+
+func TestGetSessionInfo(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	ss := config.GetSessionInfo("web")
+	if ss == nil {
+		t.Fatal(`Session web not found`)
+	}
+	if ss.StoreType != `CACHE` || ss.CacheID != `DEFAULT` {
+		t.Fatalf(`Unexpected session %+v`, ss)
+	}
+}
+
+func TestSessionInvalidCache(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(fn, []byte(`{"Sessions":[{"ID":"web","StoreType":"CACHE","CacheID":"missing"}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(fn); !errors.Is(err, ErrSessionNoCache) {
+		t.Fatalf(`Expected ErrSessionNoCache, got %v`, err)
+	}
+}
+
+func TestGetJobs(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	jb := config.GetJobInfo("cleanup")
+	if jb == nil || !jb.Enabled || len(jb.Flags) != 1 {
+		t.Fatalf(`Unexpected job %+v`, jb)
+	}
+	if jbs := config.GetJobsByGroup("maintenance"); len(jbs) != 2 {
+		t.Fatalf(`Expected 2 jobs, got %d`, len(jbs))
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	t.Setenv("WEBHOOK_SECRET", "s3cr3t")
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	whs := config.GetWebhooksByEvent("ORDER.CREATED")
+	if len(whs) != 1 {
+		t.Fatalf(`Expected 1 webhook, got %d`, len(whs))
+	}
+	wh := whs[0]
+	if wh.SigningSecret() != "s3cr3t" || wh.Secret != "s3cr3t" {
+		t.Fatalf(`Unexpected secret %s`, wh.SigningSecret())
+	}
+	if wh.Retry == nil || wh.Retry.Multiplier != 1 {
+		t.Fatalf(`Unexpected retry %+v`, wh.Retry)
+	}
+	if len(config.GetWebhooksByEvent("order.shipped")) != 0 {
+		t.Fatal(`Expected no webhook for order.shipped`)
+	}
+}
+
+func TestLoadWithLookupEnvAndClock(t *testing.T) {
+	t.Parallel()
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"ApplicationName": "${APP_NAME}", "JWT": {"Key": "${APP_JWT_KEY}"}}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"APP_NAME": "orders", "APP_JWT_KEY": "k3y"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := func() time.Time { return fixed }
+
+	events := make([]Event, 0)
+	config, err := Load(fn, WithLookupEnv(lookup), WithClock(clock), WithInstrumentation(InstrumentationFunc(func(e Event) {
+		events = append(events, e)
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationName != "orders" {
+		t.Fatalf(`Unexpected application name %q`, *config.ApplicationName)
+	}
+	sc, err := config.JWTSigner()
+	if err != nil || string(sc.Key) != "k3y" {
+		t.Fatalf(`Unexpected signer %+v, %v`, sc, err)
+	}
+
+	env["APP_NAME"] = "billing"
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationName != "billing" {
+		t.Fatalf(`Expected the lookup to be kept on reload, got %q`, *config.ApplicationName)
+	}
+	if st := config.Stats(); !st.LastReload.Equal(fixed) || st.LastDuration != 0 {
+		t.Fatalf(`Unexpected stats %+v`, st)
+	}
+	if len(events) != 2 || !events[1].Time.Equal(fixed) {
+		t.Fatalf(`Unexpected events %+v`, events)
+	}
+}
+
+func TestReloadWith(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	errPort := errors.New("port is reserved")
+	noReserved := func(c *Configuration) error {
+		if c.HostPort != nil && *c.HostPort < 1024 {
+			return errPort
+		}
+		return nil
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 80}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = config.ReloadWith(noReserved)
+	if !errors.Is(err, errPort) || !errors.Is(err, ErrValidation) {
+		t.Fatalf(`Expected the validator error, got %v`, err)
+	}
+	if *config.HostPort != 8000 {
+		t.Fatalf(`Expected the configuration to be kept, got port %d`, *config.HostPort)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.ReloadWith(noReserved); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8080 {
+		t.Fatalf(`Expected the candidate to be swapped in, got port %d`, *config.HostPort)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	errUnreachable := errors.New("database unreachable")
+	config, err := Load(fn, WithReloadCheck(func(c *Configuration) error {
+		if *c.HostPort == 9999 {
+			return errUnreachable
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Rollback(); !errors.Is(err, ErrNoRollback) {
+		t.Fatalf(`Expected ErrNoRollback, got %v`, err)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8000 {
+		t.Fatalf(`Expected the previous port, got %d`, *config.HostPort)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 9999}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err = config.Reload()
+	if !errors.Is(err, ErrRolledBack) || !errors.Is(err, errUnreachable) {
+		t.Fatalf(`Expected ErrRolledBack, got %v`, err)
+	}
+	if *config.HostPort != 8000 {
+		t.Fatalf(`Expected the reload to be rolled back, got port %d`, *config.HostPort)
+	}
+	if st := config.Stats(); st.ReloadFailures != 1 || st.ReloadSuccesses != 1 {
+		t.Fatalf(`Unexpected stats %+v`, st)
+	}
+}
+
+func TestReloadCanary(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.ReloadCanary(context.Background(), 10*time.Millisecond, func(ctx context.Context, nc *Configuration) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if !errors.Is(err, ErrCanaryAborted) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf(`Expected the canary to time out, got %v`, err)
+	}
+	errPing := errors.New("ping failed")
+	err = config.ReloadCanary(context.Background(), time.Second, func(ctx context.Context, nc *Configuration) error {
+		return errPing
+	})
+	if !errors.Is(err, ErrCanaryAborted) || !errors.Is(err, errPing) {
+		t.Fatalf(`Expected the canary to fail, got %v`, err)
+	}
+	if *config.HostPort != 8000 {
+		t.Fatalf(`Expected the configuration to be kept, got port %d`, *config.HostPort)
+	}
+
+	var seen int
+	err = config.ReloadCanary(context.Background(), time.Second, func(ctx context.Context, nc *Configuration) error {
+		seen = *nc.HostPort
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != 8080 || *config.HostPort != 8080 {
+		t.Fatalf(`Expected the candidate to be committed, saw %d and got %d`, seen, *config.HostPort)
+	}
+}
+
+func TestLoadWithoutDefaults(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"ApplicationName": "${APP_NAME}", "Databases": [{"ID": "MAIN", "ConnectionString": "sqlserver://localhost"}], "APIEndpoints": [{"ID": "api", "Address": "https://api.example.com"}]}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(string) (string, bool) { return "orders", true }
+	config, err := Load(fn, WithoutDefaults(), WithLookupEnv(lookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationName != "orders" {
+		t.Fatalf(`Expected the placeholders to be replaced, got %q`, *config.ApplicationName)
+	}
+	if config.DefaultDatabaseID != nil || config.CookieDomain != nil || config.JWTSecret != nil {
+		t.Fatalf(`Unexpected defaults %v %v %v`, config.DefaultDatabaseID, config.CookieDomain, config.JWTSecret)
+	}
+	db := config.GetDatabaseInfo("MAIN")
+	if db.StringEnclosingChar != nil || db.InterpolateTables != nil || db.StorageType != "" || db.ParameterPlaceholder != "" {
+		t.Fatalf(`Unexpected database defaults %+v`, db)
+	}
+	if ep := config.GetEndpointInfo("api"); ep.NextAddress() != "https://api.example.com" {
+		t.Fatalf(`Unexpected endpoint %+v`, ep)
+	}
+	if len(config.defaulted) != 0 || len(config.warnings) != 0 {
+		t.Fatalf(`Unexpected defaulted fields %v, warnings %v`, config.defaulted, config.warnings)
+	}
+
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if config.DefaultDatabaseID != nil {
+		t.Fatalf(`Expected the defaults to be skipped on reload, got %s`, *config.DefaultDatabaseID)
+	}
+	if config, err = Load(fn, WithLookupEnv(lookup)); err != nil {
+		t.Fatal(err)
+	}
+	if *config.DefaultDatabaseID != "DEFAULT" || *config.GetDatabaseInfo("MAIN").StringEnclosingChar != "'" {
+		t.Fatalf(`Expected the defaults without the option`)
+	}
+}
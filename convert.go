@@ -0,0 +1,181 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Format - configuration document format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+var ErrUnknownFormat = errors.New("unknown configuration format")
+
+// FormatOf gets the format from the extension of a file name or URL, including the extensions of RegisterDecoder.
+// The gzip extension is skipped, so config.json.gz is JSON.
+func FormatOf(name string) Format {
+	if i := strings.IndexAny(name, "?#"); i >= 0 && strings.Contains(name, "://") {
+		name = name[:i]
+	}
+	ext := strings.ToLower(filepath.Ext(trimGzip(name)))
+	if f, ok := registeredFormat(ext); ok {
+		return f
+	}
+	switch ext {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".json":
+		return FormatJSON
+	}
+	return ""
+}
+
+// Convert translates a configuration document from one format to another.
+// Values are carried as is so ${ENV} placeholders are preserved.
+// Null values are dropped when converting to TOML as it has no null.
+func Convert(b []byte, from, to Format) ([]byte, error) {
+	doc, err := decodeDocument(b, from)
+	if err != nil {
+		return nil, err
+	}
+	return encodeDocument(doc, to)
+}
+
+// decodeDocument decodes a document into generic maps and slices with the registered or built-in decoder of the format
+func decodeDocument(b []byte, f Format) (map[string]any, error) {
+	if d, ok := registeredDecoder(f); ok {
+		doc, err := d.Decode(b)
+		if err != nil {
+			return nil, err
+		}
+		if doc == nil {
+			doc = make(map[string]any)
+		}
+		return normalizeValue(doc).(map[string]any), nil
+	}
+	doc := make(map[string]any)
+	switch f {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, err
+		}
+	case FormatYAML:
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+	case FormatTOML:
+		if err := toml.Unmarshal(b, &doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+	}
+	return normalizeValue(doc).(map[string]any), nil
+}
+
+// encodeDocument encodes generic maps and slices in the format
+func encodeDocument(doc map[string]any, f Format) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		return json.MarshalIndent(doc, "", "\t")
+	case FormatYAML:
+		buf := &bytes.Buffer{}
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatTOML:
+		buf := &bytes.Buffer{}
+		if err := toml.NewEncoder(buf).Encode(dropNulls(doc)); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, f)
+}
+
+// normalizeValue converts decoded values to the same types regardless of the format
+func normalizeValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, mv := range t {
+			t[k] = normalizeValue(mv)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, mv := range t {
+			m[fmt.Sprint(k)] = normalizeValue(mv)
+		}
+		return m
+	case []any:
+		for i, sv := range t {
+			t[i] = normalizeValue(sv)
+		}
+		return t
+	case []map[string]any:
+		s := make([]any, len(t))
+		for i, sv := range t {
+			s[i] = normalizeValue(sv)
+		}
+		return s
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case int:
+		return int64(t)
+	}
+	return v
+}
+
+// dropNulls removes null values from maps and slices
+func dropNulls(v map[string]any) map[string]any {
+	m := make(map[string]any, len(v))
+	for k, mv := range v {
+		if mv == nil {
+			continue
+		}
+		m[k] = dropNullValue(mv)
+	}
+	return m
+}
+
+func dropNullValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return dropNulls(t)
+	case []any:
+		s := make([]any, 0, len(t))
+		for _, sv := range t {
+			if sv != nil {
+				s = append(s, dropNullValue(sv))
+			}
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,103 @@
+package cfg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronField - bounds and names of a cron schedule field
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+}
+
+// validateSchedule checks if a schedule follows the standard cron syntax.
+// Descriptors like @daily and @every <duration> are also accepted.
+func validateSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return fmt.Errorf("%w: schedule is empty", ErrInvalidSchedule)
+	}
+	if strings.HasPrefix(schedule, "@") {
+		switch strings.ToLower(schedule) {
+		case "@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly":
+			return nil
+		}
+		if strings.HasPrefix(strings.ToLower(schedule), "@every ") {
+			dur, err := time.ParseDuration(strings.TrimSpace(schedule[len("@every "):]))
+			if err != nil || dur <= 0 {
+				return fmt.Errorf("%w: invalid duration in %q", ErrInvalidSchedule, schedule)
+			}
+			return nil
+		}
+		return fmt.Errorf("%w: unknown descriptor %q", ErrInvalidSchedule, schedule)
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("%w: expected %d fields, got %d", ErrInvalidSchedule, len(cronFields), len(fields))
+	}
+	for i, f := range fields {
+		if err := cronFields[i].validate(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks a single field of a cron schedule
+func (cf cronField) validate(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		rng, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			s, err := strconv.Atoi(step)
+			if err != nil || s <= 0 {
+				return fmt.Errorf("%w: invalid step %q in %s field", ErrInvalidSchedule, step, cf.name)
+			}
+		}
+		if rng == "*" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(rng, "-")
+		l, err := cf.value(lo)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			continue
+		}
+		h, err := cf.value(hi)
+		if err != nil {
+			return err
+		}
+		if l > h {
+			return fmt.Errorf("%w: invalid range %q in %s field", ErrInvalidSchedule, rng, cf.name)
+		}
+	}
+	return nil
+}
+
+// value parses a single value of a cron field, accepting names when available
+func (cf cronField) value(v string) (int, error) {
+	for i, n := range cf.names {
+		if strings.EqualFold(n, v) {
+			return i + cf.min, nil
+		}
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < cf.min || n > cf.max {
+		return 0, fmt.Errorf("%w: value %q out of range in %s field", ErrInvalidSchedule, v, cf.name)
+	}
+	return n, nil
+}
@@ -0,0 +1,36 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"*/5 0-6 1,15 JAN-JUN SUN",
+		"30 2 * * 7",
+		"@daily",
+		"@every 1h30m",
+	}
+	for _, s := range valid {
+		if err := validateSchedule(s); err != nil {
+			t.Errorf(`Expected %q to be valid, got %v`, s, err)
+		}
+	}
+	invalid := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* * 0 * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"@sometimes",
+		"@every never",
+	}
+	for _, s := range invalid {
+		if err := validateSchedule(s); !errors.Is(err, ErrInvalidSchedule) {
+			t.Errorf(`Expected %q to be invalid, got %v`, s, err)
+		}
+	}
+}
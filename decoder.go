@@ -0,0 +1,129 @@
+package cfg
+
+import (
+	"mime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// Decoder decodes a configuration document into maps, slices and values like a JSON document,
+	// so formats like properties files or protobuf text can be loaded without changing this package
+	Decoder interface {
+		Decode(b []byte) (map[string]any, error) // Decodes the document
+	}
+
+	// DecoderFunc - a function decoding a configuration document
+	DecoderFunc func(b []byte) (map[string]any, error)
+)
+
+var (
+	decodersMu   sync.RWMutex
+	decoders     = map[Format]Decoder{}
+	extensions   = map[string]Format{}
+	contentTypes = map[string]Format{}
+)
+
+// Decode calls the function
+func (f DecoderFunc) Decode(b []byte) (map[string]any, error) {
+	return f(b)
+}
+
+// RegisterDecoder registers the decoder of the files with the extension, like .properties.
+// The format is the extension without the dot. Registering a built-in extension like .yaml
+// replaces the built-in decoder.
+func RegisterDecoder(ext string, d Decoder) {
+	ext = strings.ToLower(ext)
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	extensions[ext] = Format(ext[1:])
+	decoders[Format(ext[1:])] = d
+}
+
+// RegisterContentType registers the decoder of the remote documents with the media type,
+// like application/x-yaml. The format is the media type. Registering a built-in media type
+// like application/json replaces the built-in decoder.
+func RegisterContentType(contentType string, d Decoder) {
+	ct := mediaType(contentType)
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	contentTypes[ct] = Format(ct)
+	decoders[Format(ct)] = d
+}
+
+// FormatOfContentType gets the format from the Content-Type of a remote document.
+// It is empty for unknown media types.
+func FormatOfContentType(contentType string) Format {
+	ct := mediaType(contentType)
+	decodersMu.RLock()
+	f, ok := contentTypes[ct]
+	decodersMu.RUnlock()
+	if ok {
+		return f
+	}
+	switch {
+	case ct == "application/json" || strings.HasSuffix(ct, "+json"):
+		return FormatJSON
+	case ct == "application/yaml" || ct == "application/x-yaml" || ct == "text/yaml" || ct == "text/x-yaml":
+		return FormatYAML
+	case ct == "application/toml":
+		return FormatTOML
+	}
+	return ""
+}
+
+// genericType checks if the media type tells nothing of the format, like text/plain, so the format
+// is taken from the extension
+func genericType(mt string) bool {
+	switch mt {
+	case "text/plain", "application/octet-stream", "application/gzip", "application/x-gzip":
+		return true
+	}
+	return false
+}
+
+// acceptHeader returns the Accept header of the remote documents, preferring JSON over
+// the other built-in formats and the registered media types
+func acceptHeader() string {
+	decodersMu.RLock()
+	cts := make([]string, 0, len(contentTypes))
+	for ct := range contentTypes {
+		cts = append(cts, ct)
+	}
+	decodersMu.RUnlock()
+	sort.Strings(cts)
+	accept := "application/json, application/yaml;q=0.9, application/x-yaml;q=0.9, application/toml;q=0.9"
+	for _, ct := range cts {
+		accept += ", " + ct + ";q=0.8"
+	}
+	return accept + ", text/plain;q=0.5"
+}
+
+// registeredFormat gets the registered format of the extension
+func registeredFormat(ext string) (Format, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	f, ok := extensions[strings.ToLower(ext)]
+	return f, ok
+}
+
+// registeredDecoder gets the registered decoder of the format
+func registeredDecoder(f Format) (Decoder, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	d, ok := decoders[f]
+	return d, ok
+}
+
+// mediaType returns the lower case media type of a Content-Type without its parameters
+func mediaType(contentType string) string {
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mt
+	}
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
@@ -0,0 +1,108 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// decodeProperties decodes key=value lines into top-level fields
+func decodeProperties(b []byte) (map[string]any, error) {
+	doc := make(map[string]any)
+	for _, ln := range strings.Split(string(b), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(ln), "="); ok && !strings.HasPrefix(k, "#") {
+			doc[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return doc, nil
+}
+
+func TestRegisterDecoder(t *testing.T) {
+	RegisterDecoder(".props", DecoderFunc(decodeProperties))
+	if f := FormatOf("app.PROPS"); f != "props" {
+		t.Fatalf(`Unexpected format %q`, f)
+	}
+	fn := filepath.Join(t.TempDir(), "app.props")
+	if err := os.WriteFile(fn, []byte("# application\nApplicationID = orders\nCookieDomain=example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := NewLoader().File(fn).Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" || *config.CookieDomain != "example.com" {
+		t.Fatalf(`Unexpected fields %s %s`, *config.ApplicationID, *config.CookieDomain)
+	}
+	b, err := Convert([]byte("ApplicationID=orders"), "props", FormatJSON)
+	if err != nil || !strings.Contains(string(b), `"ApplicationID": "orders"`) {
+		t.Fatalf(`Unexpected conversion %s, %v`, b, err)
+	}
+}
+
+func TestFormatOfContentType(t *testing.T) {
+	RegisterContentType("text/x-java-properties", DecoderFunc(decodeProperties))
+	tests := []struct {
+		contentType string
+		want        Format
+	}{
+		{"application/json; charset=utf-8", FormatJSON},
+		{"application/vnd.config+json", FormatJSON},
+		{"application/x-yaml", FormatYAML},
+		{"application/toml", FormatTOML},
+		{"Text/X-Java-Properties; charset=utf-8", "text/x-java-properties"},
+		{"text/html", ""},
+	}
+	for _, tt := range tests {
+		if f := FormatOfContentType(tt.contentType); f != tt.want {
+			t.Fatalf(`Format of %s = %q, expected %q`, tt.contentType, f, tt.want)
+		}
+	}
+	doc, err := decodeDocument([]byte("HostPort=8080"), FormatOfContentType("text/x-java-properties"))
+	if err != nil || doc["HostPort"] != "8080" {
+		t.Fatalf(`Unexpected document %v, %v`, doc, err)
+	}
+}
+
+func TestLoadContentType(t *testing.T) {
+	var accept string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		switch r.URL.Path {
+		case "/config":
+			w.Header().Set("Content-Type", "application/x-yaml")
+			w.Write([]byte("ApplicationID: orders\nHostPort: 8080\n"))
+		case "/error":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><body>Bad Gateway</body></html>"))
+		default:
+			w.Write([]byte(`{"ApplicationID": "billing"}`))
+		}
+	}))
+	defer srv.Close()
+
+	config, err := Load(srv.URL + "/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" || *config.HostPort != 8080 {
+		t.Fatalf(`Unexpected fields %s %d`, *config.ApplicationID, *config.HostPort)
+	}
+	if !strings.HasPrefix(accept, "application/json") || !strings.Contains(accept, "application/x-yaml") {
+		t.Fatalf(`Unexpected Accept header %s`, accept)
+	}
+	if config, err = Load(srv.URL + "/config.json"); err != nil || *config.ApplicationID != "billing" {
+		t.Fatalf(`Expected a document without a content type to load, got %v`, err)
+	}
+	_, err = Load(srv.URL + "/error")
+	if !errors.Is(err, ErrContentType) || !errors.Is(err, ErrRemoteFetch) {
+		t.Fatalf(`Expected ErrContentType, got %v`, err)
+	}
+	if _, err = NewLoader().File(srv.URL + "/error").Load(context.Background()); !errors.Is(err, ErrContentType) {
+		t.Fatalf(`Expected ErrContentType from the loader, got %v`, err)
+	}
+}
@@ -0,0 +1,279 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind - kind of a configuration change
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "+"
+	ChangeRemoved  ChangeKind = "-"
+	ChangeModified ChangeKind = "~"
+)
+
+// redacted replaces secret values in diffs and reports
+const redacted = "*****"
+
+// Change - a difference between two configurations
+type Change struct {
+	Kind ChangeKind // Kind of the change
+	Path string     // Path of the field. Entries of sections are identified by their ID like Databases[DEFAULT].Schema
+	Old  any        // Old value. Nil when added
+	New  any        // New value. Nil when removed
+}
+
+// String formats the change
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, formatValue(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s: %s", c.Kind, c.Path, formatValue(c.Old))
+	}
+	return fmt.Sprintf("%s %s: %s -> %s", c.Kind, c.Path, formatValue(c.Old), formatValue(c.New))
+}
+
+// formatValue formats a value of a change as JSON
+func formatValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// Diff returns the semantic differences between two configurations.
+// Entries of sections are matched by their ID instead of their position, and secrets are redacted.
+func Diff(a, b *Configuration) ([]Change, error) {
+	da, err := configDocument(a)
+	if err != nil {
+		return nil, err
+	}
+	db, err := configDocument(b)
+	if err != nil {
+		return nil, err
+	}
+	return diffDocuments(da, db), nil
+}
+
+// diffDocuments returns the differences between two generic documents
+func diffDocuments(a, b map[string]any) []Change {
+	changes := make([]Change, 0)
+	diffValue(&changes, "", "", a, b)
+	return changes
+}
+
+// configDocument converts the configuration to a generic document without the file name
+func configDocument(c *Configuration) (map[string]any, error) {
+	doc := make(map[string]any)
+	if c == nil {
+		return doc, nil
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	if doc, err = decodeDocument(b, FormatJSON); err != nil {
+		return nil, err
+	}
+	delete(doc, "FileName")
+	return doc, nil
+}
+
+// diffValue compares two values. The path is shown on changes while the pattern matches sensitive paths.
+func diffValue(changes *[]Change, path, pattern string, a, b any) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+	ma, aok := a.(map[string]any)
+	mb, bok := b.(map[string]any)
+	if aok && bok {
+		keys := make([]string, 0, len(ma)+len(mb))
+		for k := range ma {
+			keys = append(keys, k)
+		}
+		for k := range mb {
+			if _, ok := ma[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			diffValue(changes, joinPath(path, k), joinPath(pattern, k), ma[k], mb[k])
+		}
+		return
+	}
+	sa, aok := a.([]any)
+	sb, bok := b.([]any)
+	if aok && bok {
+		diffSlice(changes, path, pattern, sa, sb)
+		return
+	}
+	if isSensitivePath(pattern) {
+		a, b = redactValue(a), redactValue(b)
+	}
+	switch {
+	case a == nil:
+		*changes = append(*changes, Change{Kind: ChangeAdded, Path: path, New: b})
+	case b == nil:
+		*changes = append(*changes, Change{Kind: ChangeRemoved, Path: path, Old: a})
+	default:
+		*changes = append(*changes, Change{Kind: ChangeModified, Path: path, Old: a, New: b})
+	}
+}
+
+// diffSlice compares entries of a section by their identifying key, or by position when there is none
+func diffSlice(changes *[]Change, path, pattern string, a, b []any) {
+	key := entryKey(a, b)
+	if key == "" {
+		n := len(a)
+		if len(b) > n {
+			n = len(b)
+		}
+		for i := 0; i < n; i++ {
+			var va, vb any
+			if i < len(a) {
+				va = a[i]
+			}
+			if i < len(b) {
+				vb = b[i]
+			}
+			diffValue(changes, path+"["+strconv.Itoa(i)+"]", pattern+"[]", va, vb)
+		}
+		return
+	}
+	ia, ib := indexEntries(a, key), indexEntries(b, key)
+	ids := make([]string, 0, len(ia)+len(ib))
+	for id := range ia {
+		ids = append(ids, id)
+	}
+	for id := range ib {
+		if _, ok := ia[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		va, vb := ia[id], ib[id]
+		if va == nil || vb == nil {
+			// Redact the secrets of a whole added or removed entry
+			if va != nil {
+				va = redactEntry(va, pattern+"[]")
+			}
+			if vb != nil {
+				vb = redactEntry(vb, pattern+"[]")
+			}
+		}
+		diffValue(changes, path+"["+id+"]", pattern+"[]", va, vb)
+	}
+}
+
+// entryKey finds the field that identifies all entries of both slices
+func entryKey(a, b []any) string {
+	if len(a) == 0 && len(b) == 0 {
+		return ""
+	}
+	for _, k := range []string{"ID", "Name", "GroupID", "Key"} {
+		ok := true
+		for _, s := range [][]any{a, b} {
+			local := make(map[string]bool)
+			for _, e := range s {
+				m, isMap := e.(map[string]any)
+				if !isMap {
+					return ""
+				}
+				id, isStr := fieldValue(m, k).(string)
+				if !isStr || id == "" || local[id] {
+					ok = false
+					break
+				}
+				local[id] = true
+			}
+			if !ok {
+				break
+			}
+		}
+		if ok {
+			return k
+		}
+	}
+	return ""
+}
+
+// indexEntries maps the entries by their identifying key
+func indexEntries(s []any, key string) map[string]any {
+	m := make(map[string]any, len(s))
+	for _, e := range s {
+		m[fieldValue(e.(map[string]any), key).(string)] = e
+	}
+	return m
+}
+
+// fieldValue gets the value of a field by case insensitive name
+func fieldValue(m map[string]any, name string) any {
+	if v, ok := m[name]; ok {
+		return v
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// redactEntry copies an entry with its secrets redacted. The whole entry is redacted when it can not be copied.
+func redactEntry(v any, pattern string) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return redacted
+	}
+	var cp any
+	if err = json.Unmarshal(b, &cp); err != nil {
+		return redacted
+	}
+	redactPattern(cp, pattern)
+	return cp
+}
+
+// redactPattern redacts the secrets of a value located at the pattern
+func redactPattern(v any, pattern string) {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, mv := range t {
+			p := joinPath(pattern, k)
+			if s, ok := mv.(string); ok && s != "" && isSensitivePath(p) {
+				t[k] = redacted
+				continue
+			}
+			redactPattern(mv, p)
+		}
+	case []any:
+		for _, sv := range t {
+			redactPattern(sv, pattern+"[]")
+		}
+	}
+}
+
+// redactValue replaces a non-empty secret
+func redactValue(v any) any {
+	if s, ok := v.(string); ok && s != "" {
+		return redacted
+	}
+	return v
+}
+
+// joinPath joins a field name to a path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
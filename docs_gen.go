@@ -0,0 +1,371 @@
+// Code generated by internal/docgen. DO NOT EDIT.
+
+package cfg
+
+// fieldDocs are the comments of the struct fields by type and field name
+var fieldDocs = map[string]map[string]string{
+	"AccessReport": {
+		"Accessed":   "Access counts by section or entry like Databases[DEFAULT] or Flags[MaxLimit]",
+		"Unaccessed": "Sections and entries present in the configuration that were never accessed",
+	},
+	"BundleMetadata": {
+		"Author":    "Author of the configuration",
+		"CreatedAt": "Time the bundle was written. Default is the time of WriteBundle",
+		"Digest":    "SHA-256 of the configuration in hex, set by WriteBundle",
+		"Version":   "Version of the configuration like 2024.03.1",
+	},
+	"Change": {
+		"Kind": "Kind of the change",
+		"New":  "New value. Nil when removed",
+		"Old":  "Old value. Nil when added",
+		"Path": "Path of the field. Entries of sections are identified by their ID like Databases[DEFAULT].Schema",
+	},
+	"CircuitBreakerInfo": {
+		"FailureThreshold": "Consecutive failures that open the circuit. Default is 5",
+		"HalfOpenProbes":   "Probe calls let through while half-open. The circuit closes when they succeed. Default is 1",
+		"OpenDuration":     "Time the circuit stays open before probing, like 30s or a number of seconds. Default is 30s",
+	},
+	"Configuration": {
+		"APIEndpoints":          "External API endpoints that this application can communicate",
+		"APIKeys":               "API Keys",
+		"ApplicationID":         "ID of this application",
+		"ApplicationName":       "Name of this application",
+		"ApplicationTheme":      "Theme of this application",
+		"Cache":                 "Cache info of this application",
+		"CertificateFile":       "Certificate file",
+		"CertificateKey":        "Certificate private key",
+		"CookieDomain":          "The domain of the cookie that this application will send. Default is localhost",
+		"CrossOriginDomains":    "Domains or endpoints that this application will allow",
+		"Databases":             "Configured databases for this application use",
+		"DefaultDatabaseID":     "The default database id that this application will find on the database configuration. Default is DEFAULT",
+		"DefaultEndpointID":     "The default endpoint that this application will find on the API endpoints configuration. Default is DEFAULT",
+		"DefaultNotificationID": "The default notification id that this application will find on the notification configuration. Default is DEFAULT",
+		"Directories":           "Configured directory for this application use",
+		"Domains":               "Configured domains for this application use",
+		"FileName":              "Filename of the current configuration",
+		"Flags":                 "Miscellaneous flags for this application use",
+		"HTTPClient":            "Outbound HTTP client of DefaultHTTPClient and of the remote configuration on reload",
+		"Health":                "Health and readiness setting",
+		"HostExternalURL":       "The external host URL that this application will use to set returned resources and assets",
+		"HostInternalURL":       "The internal host URL that this application will use to set returned resources and assets",
+		"HostListenAddress":     "The address the application binds like 0.0.0.0:8080 or unix:/tmp/app.sock. The HostPort is used when it has no port",
+		"HostPort":              "The network port for the application",
+		"Inherits":              "File or URL of the base configuration whose values this configuration overrides, relative to this configuration",
+		"JWT":                   "JSON Web Token setting",
+		"JWTSecret":             "Deprecated: use JWT. Application wide JSON Web Token (JT) secret. Default is defaultsecretkey",
+		"Jobs":                  "Scheduled jobs",
+		"LicenseSerial":         "License serial of this application",
+		"Maintenance":           "Maintenance setting",
+		"Notifications":         "Configured notifications for this application use",
+		"OAuths":                "OAuth definitions",
+		"PasswordPolicy":        "Password and account policy",
+		"Proxy":                 "Outbound proxy",
+		"Queue":                 "Queue or message queue",
+		"RateLimits":            "Rate limiting policies",
+		"ReadTimeout":           "Default network timeout setting for reading data uploaded to this application, in seconds or like 30s or 2m",
+		"Secure":                "Flags if secure",
+		"Sessions":              "Session management settings",
+		"Sources":               "Folder sources",
+		"Tenancy":               "Database selection of the tenants",
+		"Webhooks":              "Outbound webhooks",
+		"WriteTimeout":          "Default network timeout setting for writing data downloaded from this application, in seconds or like 30s or 2m",
+	},
+	"ConnectionInfo": {
+		"Database": "Database name",
+		"Driver":   "Driver like sqlserver, postgres or mysql, from the scheme or the DriverName",
+		"Host":     "Host name or address, or the socket path",
+		"Options":  "Other parameters with lower case keys",
+		"Password": "Password",
+		"Port":     "Port. Zero when not set",
+		"User":     "User name",
+	},
+	"DatabaseInfo": {
+		"ApplicationNameTag":     "Application name reported to the server by the connection string returned by DSN",
+		"AutoMigrate":            "Runs the pending migrations at startup",
+		"ConnStringTemplate":     "Template of the connection string like server={{.Host}};database={{.Database}} on the Connection, instead of the format of the DriverName",
+		"Connection":             "Components of the connection string assembled by DSN when the ConnectionString is empty. Supports ${ENV} placeholders",
+		"ConnectionString":       "ConnectionString specific to the database",
+		"DefaultQueryTimeout":    "Time limit of the queries run with QueryContext, like 30s or a number of seconds. Default is no limit",
+		"DefaultTxIsolation":     "Isolation of the transactions begun with TxOptions: READ-UNCOMMITTED, READ-COMMITTED, REPEATABLE-READ, SNAPSHOT or SERIALIZABLE. Default is the one of the driver",
+		"DriverName":             "DriverName needs to be specified depending on the driver id used by the Go database driver",
+		"Environments":           "Fields overridden per environment like {\"prod\": {\"ConnectionString\": \"...\"}}, selected by WithEnvironment or APP_ENV",
+		"GroupID":                "GroupID allows us to get groups of connection",
+		"HelperID":               "When using github.com/NarsilWorks-Inc/datahelperlite, this is needed in the configuration file",
+		"ID":                     "A unique ID that will identify the connection to a database",
+		"InterpolateTables":      "Enables the tables to be interpolated with schema. Default is true",
+		"MaxConnectionIdleTime":  "Max idle connection lifetime",
+		"MaxConnectionLifetime":  "Max connection lifetime",
+		"MaxIdleConnection":      "Maximum idle connection",
+		"MaxOpenConnection":      "Maximum open connection",
+		"MigrationsPath":         "Directory of the schema migrations like migrations/orders, or a URL like file:///app/migrations",
+		"MigrationsTable":        "Table recording the applied migrations. Default is schema_migrations",
+		"ParameterInSequence":    "Parameter place holder is in sequence. Default is false",
+		"ParameterPlaceholder":   "Parameter place holder for prepared statements. Default is '?'",
+		"Ping":                   "Ping connection",
+		"ReservedWordEscapeChar": "Reserved word escape chars. For escaping with different opening and closing characters, just set to both. Example. `[]` for SQL server. Default is \"",
+		"Schema":                 "Schema for any of the database operations",
+		"SequenceGenerator":      "Sequence generator configuration",
+		"StorageType":            "FILE for filebased database such as Access, SQlite or LocalDB. SERVER for SQL Server, MySQL etc. Default is SERVER",
+		"StringEnclosingChar":    "Gets or sets the character that encloses a string in the query. Default is '",
+		"StringEscapeChar":       "Gets or Sets the character that escapes a reserved character such as the character that encloses a s string. Default is \\",
+		"When":                   "Condition like region == \"ap\" keeping the entry on load. See WithVariables",
+	},
+	"DirectoryInfo": {
+		"Directories": "Child directories looked up by path with GetDirectoryPath, by their GroupID",
+	},
+	"EndpointInfo": {
+		"Address":      "The absolute URL to the resource",
+		"Addresses":    "Absolute URLs of the resource on a cluster, selected by NextAddress. Address is used when empty",
+		"Breaker":      "Circuit breaker of the calls to the endpoint",
+		"Environments": "Fields overridden per environment like {\"prod\": {\"Address\": \"...\"}}, selected by WithEnvironment or APP_ENV",
+		"GroupID":      "A group id to get certain endpoint set",
+		"ID":           "Endpoint ID for quick access",
+		"Name":         "Endpoint Name to show",
+		"OAuthID":      "OAuth provider whose client credentials token authorizes the calls of the EndpointClient",
+		"Signing":      "Signing of the requests of the EndpointClient",
+		"Strategy":     "ROUND-ROBIN, RANDOM or FAILOVER selection of the Addresses. Default is ROUND-ROBIN",
+		"When":         "Condition like region == \"ap\" keeping the entry on load. See WithVariables",
+	},
+	"Event": {
+		"Duration": "Time spent on the operation",
+		"Err":      "Error of the operation. Nil on success",
+		"Kind":     "Kind of the event",
+		"Source":   "Source of the configuration",
+		"Time":     "Time when the event completed",
+	},
+	"Flag": {
+		"DependsOn": "Keys of the flags the Formula derives the value from",
+		"Expr":      "Expression computing the value on load like HostPort > 0 ? 8 : 2, over other flags and fields",
+		"Formula":   "Value derived on load from the DependsOn flags in braces like http://{host}:{port}",
+		"Public":    "Exposed to clients by PublicView",
+	},
+	"HTTPClientInfo": {
+		"DisableKeepAlives": "Closes the connections after each request",
+		"MaxIdleConns":      "Maximum idle connections across all hosts. Default is 100",
+		"TLSMinVersion":     "Minimum TLS version: 1.0, 1.1, 1.2 or 1.3. Default is 1.2",
+		"Timeout":           "Time limit of a request including reading the response, like 30s or a number of seconds. Default is no limit",
+	},
+	"HealthInfo": {
+		"CacheID":       "Cache id checked on readiness",
+		"DatabaseIDs":   "Database ids checked on readiness",
+		"EndpointIDs":   "API endpoint ids checked on readiness",
+		"LivenessPath":  "Path of the liveness endpoint. Default is /healthz",
+		"Queue":         "Indicates that the queue is checked on readiness",
+		"ReadinessPath": "Path of the readiness endpoint. Default is /readyz",
+		"Timeout":       "Timeout of each check in seconds. Default is 5",
+	},
+	"HealthResult": {
+		"Duration": "Time spent on the check",
+		"Err":      "Error of the check. Nil when healthy",
+		"Name":     "Name of the dependency like database:DEFAULT",
+	},
+	"Interpolation": {
+		"Path": "Path of the field like Databases[0].ConnectionString",
+		"Raw":  "The value as written",
+		"Vars": "Environment variables consumed",
+	},
+	"JWTInfo": {
+		"AccessTTL":  "Access token time to live in seconds",
+		"Algorithm":  "Signing algorithm like HS256, RS256 or ES256. Default is HS256",
+		"Audience":   "Audience (aud) of the token",
+		"Issuer":     "Issuer (iss) of the token",
+		"Key":        "The secret or PEM encoded private key. Supports ${ENV} placeholders",
+		"KeyFile":    "The file where the key is read when Key is not set",
+		"KeyID":      "Key id (kid) of the current key",
+		"Keys":       "Previous keys that are still accepted on verification during key rotation",
+		"RefreshTTL": "Refresh token time to live in seconds",
+	},
+	"JWTKeyInfo": {
+		"ID":      "Key id (kid) written on the token header",
+		"Key":     "The secret or PEM encoded key. Supports ${ENV} placeholders",
+		"KeyFile": "The file where the key is read when Key is not set",
+	},
+	"JWTVerifierConfig": {
+		"Keys": "Keys by key id. The current key is also stored with an empty id",
+	},
+	"JobInfo": {
+		"Enabled":  "Indicates that the job should run",
+		"Flags":    "Payload of the job",
+		"GroupID":  "A group id to get certain job set",
+		"ID":       "ID of the job for quick reference",
+		"Schedule": "Schedule in cron syntax. Descriptors such as @daily and @every 1h are also accepted",
+		"When":     "Condition like region == \"ap\" keeping the entry on load. See WithVariables",
+	},
+	"Limits": {
+		"MaxDepth":        "Maximum nesting of objects and arrays. Default is 32",
+		"MaxSize":         "Maximum size of the document in bytes. Default is 4 MiB",
+		"MaxStringLength": "Maximum length of a key or a string value in bytes. Default is 64 KiB",
+	},
+	"LoadReport": {
+		"Defaulted":   "Fields set to their implicit defaults on load, like CookieDomain",
+		"Unaccessed":  "Sections and entries never accessed, set only when loaded with WithAccessAudit",
+		"UnknownKeys": "Keys of the document that did not map to any field, like Databases[0].Shema",
+		"Warnings":    "Non-fatal problems found on load",
+	},
+	"MaintenanceInfo": {
+		"AllowedIPs": "IP addresses or CIDRs that are allowed to pass during maintenance",
+		"Enabled":    "Puts the application in maintenance regardless of the windows",
+		"Message":    "Message shown to clients during maintenance",
+		"Windows":    "Scheduled maintenance windows",
+	},
+	"MaintenanceWindowInfo": {
+		"End":      "End of the window in 2006-01-02 15:04 format",
+		"Start":    "Start of the window in 2006-01-02 15:04 format",
+		"TimeZone": "IANA time zone of Start and End like Asia/Manila. Default is UTC",
+	},
+	"NotificationInfo": {
+		"Options": "Provider specific options like region or api-version. Options named like secret, password, token or key are sensitive",
+		"When":    "Condition like region == \"ap\" keeping the entry on load. See WithVariables",
+	},
+	"OAuthProviderInfo": {
+		"ClientID":                "Represents the application id registered in an OAuth provider",
+		"ClientSecret":            "The secret of the application registered in the provider. Supports ${ENV} placeholders",
+		"EmbedText":               "OAuth embed options",
+		"ID":                      "OAuth provider info id for quick access",
+		"IconUrl":                 "OAuth icon image for miscellaneous purposes",
+		"IssuerUri":               "The OpenID issuer URI where the discovery document is located",
+		"JwksUri":                 "The URI of the JSON Web Key Set used to verify tokens",
+		"Label":                   "OAuth label for visual controls",
+		"Name":                    "OAuth name for miscellaneous purposes",
+		"ProviderApiUri":          "The API URI to get authorization and access keys",
+		"ProviderWebUri":          "The web URI to get authorization and access keys",
+		"RedirectUris":            "The URIs where the provider redirects after authorization",
+		"ResponseType":            "The type of response that the application needs from the OAuth provider",
+		"Scope":                   "The scope of access to resources",
+		"TokenEndpointAuthMethod": "The client authentication method at the token endpoint, like client_secret_basic, client_secret_post or none",
+		"TokenPath":               "The path of the token endpoint when the ProviderApiUri is the base URI of the API, like /oauth/token",
+		"UsePKCE":                 "Sends a S256 code challenge on authorization and its verifier on the token request",
+	},
+	"PasswordPolicyInfo": {
+		"HistorySize":      "Number of previous passwords that cannot be reused",
+		"LockoutDuration":  "Lockout duration in seconds",
+		"LockoutThreshold": "Number of failed attempts before the account is locked. Zero disables lockout",
+		"MaxAge":           "Number of days before a password expires. Zero never expires",
+		"MinClasses":       "Minimum number of character classes (upper, lower, digit, symbol) present",
+		"MinLength":        "Minimum number of characters",
+		"RequireDigit":     "Requires at least one digit",
+		"RequireLower":     "Requires at least one lower case letter",
+		"RequireSymbol":    "Requires at least one symbol or punctuation",
+		"RequireUpper":     "Requires at least one upper case letter",
+	},
+	"Pickup": {
+		"ModTime":  "Modification time of the file",
+		"Name":     "Name of the file",
+		"Path":     "Path of the file in the store of the folder, like a local path or the key of an object",
+		"Size":     "Size of the file in bytes",
+		"SourceID": "ID of the source",
+	},
+	"Problem": {
+		"Err":  "The problem",
+		"Path": "Path of the field like Databases[0].ID",
+	},
+	"Provenance": {
+		"Layer":  "Layer like file, override or default. Empty when no layer set the value",
+		"Source": "File, environment variables or function of the layer, like config.web01.json or DB_PASSWORD",
+	},
+	"ProxyInfo": {
+		"HTTP":     "Proxy for HTTP requests",
+		"HTTPS":    "Proxy for HTTPS requests. Falls back to HTTP when not set",
+		"NoProxy":  "Comma separated hosts, domains and CIDRs that are not proxied",
+		"Password": "Proxy password. Supports ${ENV} placeholders",
+		"Username": "Proxy user. Supports ${ENV} placeholders",
+	},
+	"PublicInfo": {
+		"Flags": "Flags marked Public",
+	},
+	"QueueInfo": {
+		"ClientID":           "ClientID of the service",
+		"Cluster":            "Cluster name",
+		"ID":                 "ID of the setting",
+		"ServerAddressGroup": "Queue server address group",
+		"StreamName":         "Stream name",
+	},
+	"RateLimitInfo": {
+		"Burst":    "Number of requests allowed to exceed the limit momentarily",
+		"ID":       "ID of the policy for quick reference",
+		"KeyBy":    "The key where the limit is counted against. Supported keys are IP, USER and API-KEY. Default is IP",
+		"Requests": "Number of requests allowed within the window",
+		"Window":   "Window in seconds",
+	},
+	"RetryInfo": {
+		"Interval":    "Interval between attempts in seconds",
+		"MaxAttempts": "Maximum number of attempts including the first",
+		"Multiplier":  "Multiplier applied to the interval after each attempt. Default is 1",
+	},
+	"Server": {
+		"Config":       "Configuration to serve",
+		"FullTokens":   "Bearer tokens allowed to read the full configuration with secrets",
+		"PollInterval": "Interval of checking the configuration for change events. Default is 1 second",
+		"Tokens":       "Bearer tokens allowed to read the redacted configuration. Empty allows anonymous access",
+	},
+	"SessionInfo": {
+		"CacheID":     "The cache id where sessions are stored when StoreType is CACHE",
+		"CookieName":  "The name of the session cookie",
+		"ID":          "ID of the session setting for quick reference",
+		"IdleTimeout": "Session idle timeout in seconds",
+		"StoreType":   "Session store type. Supported types are MEMORY and CACHE. Default is MEMORY",
+		"TTL":         "Session time to live in seconds",
+	},
+	"SigningInfo": {
+		"Algorithm": "Hash of the HMAC: SHA256, SHA512 or SHA1. Default is SHA256",
+		"Header":    "Header of the HMAC signature. Default is X-Signature",
+		"KeyID":     "AWS access key id. AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are used when it is not set",
+		"Region":    "AWS region like ap-southeast-1. Default is AWS_REGION, or else us-east-1",
+		"Secret":    "Secret of the HMAC, or the AWS secret access key. Supports ${ENV} placeholders",
+		"Service":   "AWS service like execute-api or es",
+		"Type":      "HMAC signs the body with the Secret, or AWS-SIGV4 signs the request with AWS Signature Version 4",
+	},
+	"Snapshot": {
+		"Changes": "Changes from the previous version with secrets redacted. Nil for the first version",
+		"File":    "File of the redacted version when the history is kept on disk",
+		"Kind":    "Load, reload or rollback that produced the version",
+		"Source":  "Source of the configuration",
+		"Time":    "Time the version became live",
+	},
+	"SourceInfo": {
+		"Archive":       "Archive folder of the source",
+		"Error":         "Error folder of the source",
+		"Extension":     "Extension of the file to pickup",
+		"ID":            "ID of the source for quick reference",
+		"MaxFileSize":   "Largest file in bytes that is picked up. Larger files are moved to the Error folder. Zero is unlimited",
+		"Password":      "Password or secret access key of a remote Source. Supports ${ENV} placeholders",
+		"Relative":      "Indicates that the Error, Success and Archive folders are relative to Source",
+		"RetentionDays": "Days the files are kept in the Error, Success and Archive folders. Zero keeps them",
+		"Source":        "Source folder of the source, or a URI like s3://bucket/inbound?region=ap-southeast-1 or sftp://host/inbound?fingerprint=SHA256:.... Other schemes need RegisterFolder",
+		"Success":       "Success folder of the source",
+		"Type":          "Type of Inbound file. Supported types are ORDER and SNAPSHOT",
+		"Username":      "User or access key id of a remote Source",
+	},
+	"SourceMetadata": {
+		"ContentHash": "SHA-256 of the loaded document in hex, after decryption and conversion to JSON",
+		"Kind":        "Kind of the source",
+		"LoadedAt":    "Time of the last successful load or reload",
+		"Source":      "File name, URL or description of the source, like memory or base.json+env:APP_",
+	},
+	"Stats": {
+		"LastDuration":    "Time spent on the last load or reload",
+		"LastReload":      "Time of the last successful reload",
+		"Loads":           "Number of loads including reloads",
+		"ReloadFailures":  "Number of failed reloads",
+		"ReloadSuccesses": "Number of successful reloads",
+		"WatchEvents":     "Number of change notifications received by watchers",
+	},
+	"TenancyInfo": {
+		"GroupID":  "Database group of the shards of the tenants not mapped otherwise",
+		"Prefixes": "Database group id by tenant id prefix like eu- or us-. The longest prefix wins",
+		"Shards":   "Number of shards the tenant ids are hashed to, the first databases of the group. Required with GroupID or Prefixes",
+		"Tenants":  "Database id by tenant id",
+	},
+	"Warning": {
+		"Message": "Description of the problem",
+		"Path":    "Path of the field",
+	},
+	"WebhookInfo": {
+		"Events": "Event names that trigger the webhook. An asterisk (*) matches all events",
+		"ID":     "ID of the webhook for quick reference",
+		"Retry":  "Retry policy when delivery fails",
+		"Secret": "Secret for HMAC signing. Supports ${ENV} placeholders",
+		"URL":    "The absolute URL where the events are posted",
+		"When":   "Condition like region == \"ap\" keeping the entry on load. See WithVariables",
+	},
+}
@@ -0,0 +1,81 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration - a duration written as a string like 30s or 2m, or as an integer number of seconds
+type Duration time.Duration
+
+// UnmarshalJSON decodes a duration from a string like 1m30s or from a number of seconds
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case float64:
+		*d = Duration(t * float64(time.Second))
+		return nil
+	case string:
+		pd, err := parseDuration(t)
+		if err != nil {
+			return err
+		}
+		*d = pd
+		return nil
+	}
+	return fmt.Errorf("invalid duration %s", b)
+}
+
+// MarshalJSON encodes the duration as a string like 1m30s
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Duration returns the duration as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration like 1m30s
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// parseDuration parses a duration like 30s, or a number of seconds
+func parseDuration(s string) (Duration, error) {
+	s = strings.TrimSpace(s)
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return Duration(f * float64(time.Second)), nil
+	}
+	pd, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return Duration(pd), nil
+}
+
+// ReadTimeoutDuration returns the ReadTimeout, or zero when it is not set
+func (c *Configuration) ReadTimeoutDuration() time.Duration {
+	return timeoutDuration(c.ReadTimeout)
+}
+
+// WriteTimeoutDuration returns the WriteTimeout, or zero when it is not set
+func (c *Configuration) WriteTimeoutDuration() time.Duration {
+	return timeoutDuration(c.WriteTimeout)
+}
+
+// timeoutDuration returns the duration, or zero when it is not set
+func timeoutDuration(d *Duration) time.Duration {
+	if d == nil {
+		return 0
+	}
+	return d.Duration()
+}
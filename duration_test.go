@@ -0,0 +1,47 @@
+package cfg
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		doc  string
+		want time.Duration
+		err  bool
+	}{
+		{`30`, 30 * time.Second, false},
+		{`1.5`, 1500 * time.Millisecond, false},
+		{`"2m"`, 2 * time.Minute, false},
+		{`"1m30s"`, 90 * time.Second, false},
+		{`"45"`, 45 * time.Second, false},
+		{`"soon"`, 0, true},
+		{`true`, 0, true},
+	}
+	for _, tt := range tests {
+		var d Duration
+		err := json.Unmarshal([]byte(tt.doc), &d)
+		if (err != nil) != tt.err || d.Duration() != tt.want {
+			t.Fatalf(`Decoding %s = %v, %v, expected %v`, tt.doc, d, err, tt.want)
+		}
+	}
+	b, err := json.Marshal(Duration(90 * time.Second))
+	if err != nil || string(b) != `"1m30s"` {
+		t.Fatalf(`Unexpected encoding %s, %v`, b, err)
+	}
+}
+
+func TestTimeoutDurations(t *testing.T) {
+	config := Configuration{}
+	if err := json.Unmarshal([]byte(`{"ReadTimeout": 30, "WriteTimeout": "2m"}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.ReadTimeoutDuration() != 30*time.Second || config.WriteTimeoutDuration() != 2*time.Minute {
+		t.Fatalf(`Unexpected timeouts %v %v`, config.ReadTimeoutDuration(), config.WriteTimeoutDuration())
+	}
+	if (&Configuration{}).ReadTimeoutDuration() != 0 {
+		t.Fatal(`Expected no timeout when not set`)
+	}
+}
@@ -0,0 +1,166 @@
+package cfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envSource prefixes the source of a configuration loaded from the environment
+const envSource = "env:"
+
+// LoadFromEnv loads the configuration from the environment variables starting with the prefix,
+// for deployments without a configuration file. Each variable name after the prefix is a path of
+// fields separated by double underscores, matched case-insensitively with single underscores ignored,
+// and numeric parts index the entries of sections:
+//
+//	APP_HOSTPORT=8000
+//	APP_JWT__ALGORITHM=RS256
+//	APP_DATABASES__0__ID=DEFAULT
+//	APP_DATABASES__0__CONNECTION_STRING=sqlserver://localhost
+//	APP_CROSSORIGINDOMAINS=a.example.com,b.example.com
+//
+// Lists of values can also be separated by commas, and a section can be set at once as JSON
+// like APP_FLAGS=[{"key":"Beta","value":"on"}]. Defaults and validation apply like Load.
+// The configuration reloads from the environment and can not be saved.
+func LoadFromEnv(prefix string, opts ...Option) (*Configuration, error) {
+	return Load(envSource+prefix, opts...)
+}
+
+// envDocument builds a JSON document from the environment variables starting with the prefix
+func envDocument(prefix string, environ []string) ([]byte, error) {
+	sort.Strings(environ)
+	doc := make(map[string]any)
+	t := reflect.TypeOf(Configuration{})
+	for _, kv := range environ {
+		k, v, _ := strings.Cut(kv, "=")
+		if prefix == "" || !strings.HasPrefix(k, prefix) || len(k) == len(prefix) {
+			continue
+		}
+		if err := setEnvValue(doc, t, strings.Split(k[len(prefix):], "__"), v); err != nil {
+			return nil, wrapError(ErrDecode, fmt.Errorf("%s: %w", k, err))
+		}
+	}
+	return json.Marshal(envSlices(doc))
+}
+
+// setEnvValue sets the value at the path of fields in the container built for the type
+func setEnvValue(container any, t reflect.Type, path []string, value string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	key := path[0]
+	var ft reflect.Type
+	switch t.Kind() {
+	case reflect.Struct:
+		norm := strings.ReplaceAll(key, "_", "")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && strings.EqualFold(jsonName(f), norm) {
+				key, ft = jsonName(f), f.Type
+				break
+			}
+		}
+	case reflect.Slice:
+		if _, err := strconv.Atoi(key); err != nil {
+			return fmt.Errorf("%q is not an index", key)
+		}
+		ft = t.Elem()
+	case reflect.Map:
+		ft = t.Elem()
+	}
+	m := container.(map[string]any)
+	if ft == nil {
+		// unknown fields are kept so they are reported as unknown keys
+		m[strings.Join(path, "__")] = value
+		return nil
+	}
+	if len(path) == 1 {
+		v, err := envValue(value, ft)
+		if err != nil {
+			return err
+		}
+		m[key] = v
+		return nil
+	}
+	child, ok := m[key].(map[string]any)
+	if !ok {
+		child = make(map[string]any)
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Slice {
+			// slices are built as maps of indexes until envSlices
+			child[envSliceMark] = true
+		}
+		m[key] = child
+	}
+	return setEnvValue(child, ft, path[1:], value)
+}
+
+// envSliceMark marks the maps standing for slices while the document is built
+const envSliceMark = "\x00slice"
+
+// envSlices converts the maps of indexes to slices ordered by index
+func envSlices(v any) any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return v
+	}
+	isSlice, _ := m[envSliceMark].(bool)
+	delete(m, envSliceMark)
+	for k, mv := range m {
+		m[k] = envSlices(mv)
+	}
+	if !isSlice {
+		return m
+	}
+	idx := make([]int, 0, len(m))
+	for k := range m {
+		i, _ := strconv.Atoi(k)
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	s := make([]any, 0, len(idx))
+	for _, i := range idx {
+		s = append(s, m[strconv.Itoa(i)])
+	}
+	return s
+}
+
+// envValue converts the value of a variable to the kind of the field
+func envValue(value string, t reflect.Type) (any, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Slice:
+		if et := elemType(t); et.Kind() != reflect.Struct && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			parts := strings.Split(value, ",")
+			s := make([]any, 0, len(parts))
+			for _, p := range parts {
+				pv, err := envValue(strings.TrimSpace(p), t.Elem())
+				if err != nil {
+					return nil, err
+				}
+				s = append(s, pv)
+			}
+			return s, nil
+		}
+	}
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
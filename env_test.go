@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("APP_HOSTPORT", "8000")
+	t.Setenv("APP_APPLICATION_ID", "orders")
+	t.Setenv("APP_SECURE", "true")
+	t.Setenv("APP_CROSSORIGINDOMAINS", "a.example.com, b.example.com")
+	t.Setenv("APP_JWT__ALGORITHM", "rs256")
+	t.Setenv("APP_DATABASES__0__ID", "DEFAULT")
+	t.Setenv("APP_DATABASES__0__CONNECTION_STRING", "sqlserver://localhost")
+	t.Setenv("APP_DATABASES__1__ID", "REPORTS")
+	t.Setenv("APP_DATABASES__1__MAXOPENCONNECTION", "5")
+	t.Setenv("APP_FLAGS", `[{"key": "Beta", "value": "on"}]`)
+	t.Setenv("APP_COLOUR", "blue")
+	t.Setenv("OTHER_HOSTPORT", "9000")
+
+	config, err := LoadFromEnv("APP_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8000 || *config.ApplicationID != "orders" || !*config.Secure {
+		t.Fatalf(`Unexpected fields %d %s %v`, *config.HostPort, *config.ApplicationID, *config.Secure)
+	}
+	if want := []string{"a.example.com", "b.example.com"}; !reflect.DeepEqual(*config.CrossOriginDomains, want) {
+		t.Fatalf(`Unexpected domains %v`, *config.CrossOriginDomains)
+	}
+	if config.JWT.Algorithm != "RS256" {
+		t.Fatalf(`Expected defaults to apply, got %s`, config.JWT.Algorithm)
+	}
+	db := config.GetDatabaseInfo("DEFAULT")
+	if db == nil || db.ConnectionString != "sqlserver://localhost" || db.StorageType != "SERVER" {
+		t.Fatalf(`Unexpected database %+v`, db)
+	}
+	if db = config.GetDatabaseInfo("REPORTS"); db == nil || *db.MaxOpenConnection != 5 {
+		t.Fatalf(`Unexpected database %+v`, db)
+	}
+	if f := config.Flag("Beta").Bool(); f == nil || !*f {
+		t.Fatal(`Expected flag Beta to be on`)
+	}
+	if rpt := config.LoadReport(); !reflect.DeepEqual(rpt.UnknownKeys, []string{"COLOUR"}) {
+		t.Fatalf(`Unexpected unknown keys %v`, rpt.UnknownKeys)
+	}
+
+	t.Setenv("APP_HOSTPORT", "8080")
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8080 {
+		t.Fatalf(`Expected the reload to read the environment, got %d`, *config.HostPort)
+	}
+	if err = config.Save(); !errors.Is(err, ErrSaveNotLocalFile) {
+		t.Fatalf(`Expected ErrSaveNotLocalFile, got %v`, err)
+	}
+
+	t.Setenv("APP_HOSTPORT", "eighty")
+	if _, err = LoadFromEnv("APP_"); !errors.Is(err, ErrDecode) {
+		t.Fatalf(`Expected ErrDecode, got %v`, err)
+	}
+}
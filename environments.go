@@ -0,0 +1,95 @@
+package cfg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// EnvironmentOverrides - fields of an entry overridden per environment name
+type EnvironmentOverrides map[string]map[string]any
+
+// appEnvVar is the environment variable selecting the environment when the WithEnvironment option is not set
+const appEnvVar = "APP_ENV"
+
+// WithEnvironment selects the environment like prod whose overrides in the Environments map of the entries
+// are applied on load, instead of the APP_ENV environment variable.
+// On reload, the environment of the loaded configuration is used when this option is not set.
+func WithEnvironment(name string) Option {
+	return func(o *options) {
+		o.appEnv = name
+	}
+}
+
+// WithEnvironmentIDs looks up the entries by their id suffixed with the environment first, like DEFAULT_PROD
+// for GetDatabaseInfo("DEFAULT") in the prod environment, so one file carries the variants of the same
+// logical entry. The plain id is looked up when no entry has the suffixed id.
+// On reload, the ids are suffixed when the loaded configuration suffixed them.
+func WithEnvironmentIDs() Option {
+	return func(o *options) {
+		o.suffixIDs = true
+	}
+}
+
+// Environment returns the environment whose overrides were applied on load
+func (c *Configuration) Environment() string {
+	return c.appEnv
+}
+
+// applyEnvironments replaces the fields of the objects with an Environments map by the overrides of the environment
+// and returns the replaced values as written. The Environments maps are kept.
+func applyEnvironments(doc map[string]any, env string) []original {
+	origs := make([]original, 0)
+	if env == "" {
+		return origs
+	}
+	var walk func(v any, path string)
+	walk = func(v any, path string) {
+		switch t := v.(type) {
+		case map[string]any:
+			envKey, ok := lookupKey(t, "Environments")
+			if envs, isMap := t[envKey].(map[string]any); ok && isMap {
+				if name, ok := lookupKey(envs, env); ok {
+					if ov, isMap := envs[name].(map[string]any); isMap {
+						for k, nv := range ov {
+							ek, _ := lookupKey(t, k)
+							if raw, isStr := t[ek].(string); isStr {
+								if s, isStr := nv.(string); isStr {
+									origs = append(origs, original{path: joinPath(path, ek), raw: raw, value: s})
+								}
+							}
+							t[ek] = nv
+						}
+					}
+				}
+			}
+			for k, mv := range t {
+				if k != envKey {
+					walk(mv, joinPath(path, k))
+				}
+			}
+		case []any:
+			for i, sv := range t {
+				walk(sv, path+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	}
+	walk(doc, "")
+	return origs
+}
+
+// environment returns the environment of the options, or else of the APP_ENV environment variable
+func (o *options) environment() string {
+	if o.appEnv != "" {
+		return o.appEnv
+	}
+	v, _ := o.env()(appEnvVar)
+	return strings.TrimSpace(v)
+}
+
+// envIDs returns the ids looked up for an id, the id suffixed with the environment first when enabled
+func (c *Configuration) envIDs(id string) []string {
+	if !c.suffixIDs || c.appEnv == "" || id == "" {
+		return []string{id}
+	}
+	return []string{id + "_" + strings.ToUpper(c.appEnv), id}
+}
@@ -0,0 +1,26 @@
+package cfg
+
+// causeError - sentinel error wrapping its cause so both match with errors.Is and errors.As
+type causeError struct {
+	kind  error
+	cause error
+}
+
+// wrapError wraps the cause with the sentinel error
+func wrapError(kind, cause error) error {
+	return &causeError{kind: kind, cause: cause}
+}
+
+func (e *causeError) Error() string {
+	return e.kind.Error() + ": " + e.cause.Error()
+}
+
+// Is checks if the target is the sentinel error
+func (e *causeError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Unwrap returns the cause
+func (e *causeError) Unwrap() error {
+	return e.cause
+}
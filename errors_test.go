@@ -0,0 +1,57 @@
+package cfg
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, doc string) string {
+		fn := filepath.Join(dir, name)
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return fn
+	}
+
+	_, err := Load(write("syntax.json", `{"ApplicationID": x}`))
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf(`Expected ErrDecode, got %v`, err)
+	}
+	var se *json.SyntaxError
+	if !errors.As(err, &se) {
+		t.Fatalf(`Expected the cause to be a *json.SyntaxError, got %v`, err)
+	}
+
+	_, err = Load(write("type.json", `{"ApplicationID": 1}`))
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf(`Expected ErrDecode, got %v`, err)
+	}
+
+	key, other := make([]byte, 32), make([]byte, 32)
+	rand.Read(key)
+	rand.Read(other)
+	ev, err := EncryptValue(key, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Load(write("secret.json", `{"JWTSecret": "`+ev+`"}`), WithDecryptionKey(other))
+	if !errors.Is(err, ErrSecretResolution) || !errors.Is(err, ErrDecryptionFailed) {
+		t.Fatalf(`Expected ErrSecretResolution wrapping ErrDecryptionFailed, got %v`, err)
+	}
+
+	_, err = Load(write("invalid.json", `{"Sessions":[{"ID":"web","StoreType":"CACHE","CacheID":"missing"}]}`))
+	if !errors.Is(err, ErrValidation) || !errors.Is(err, ErrSessionNoCache) {
+		t.Fatalf(`Expected ErrValidation with ErrSessionNoCache, got %v`, err)
+	}
+
+	_, err = Load("http://127.0.0.1:1/config.json")
+	if !errors.Is(err, ErrRemoteFetch) {
+		t.Fatalf(`Expected ErrRemoteFetch, got %v`, err)
+	}
+}
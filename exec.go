@@ -0,0 +1,132 @@
+package cfg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// execTimeout is the time a command of an ${exec:...} placeholder may run
+const execTimeout = 30 * time.Second
+
+// execPolicy - commands allowed to run for the ${exec:...} placeholders
+type execPolicy struct {
+	allowed []string // Names of the programs allowed to run
+}
+
+var ErrExecNotAllowed = errors.New("command is not allowed")
+
+// WithExec enables the ${exec:command args} placeholders, replaced on load with the standard output of the
+// command like ${exec:op read op://app/db/password}, so secret managers with a command line tool are used
+// without their SDK. Only the programs named like op or aws are run, and the load fails when none is named.
+// The placeholders only run in configurations loaded from local files, with their override, companion and
+// base files, so a remote configuration, a Source, a Loader or a bundle can not run commands on the host.
+// The command runs without a shell, split on spaces, with the trailing line break of its output removed.
+// It can not contain | or }.
+// On reload, the commands are run when the loaded configuration enabled them.
+func WithExec(allowed ...string) Option {
+	return func(o *options) {
+		o.exec = &execPolicy{allowed: allowed}
+	}
+}
+
+// run runs the command and returns its standard output
+func (p *execPolicy) run(command string) (string, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return "", errors.New("command is empty")
+	}
+	if !p.allows(args[0]) {
+		return "", fmt.Errorf("%w: %s", ErrExecNotAllowed, args[0])
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if len(msg) > 200 {
+				msg = msg[:200]
+			}
+			return "", fmt.Errorf("%s: %w: %s", args[0], err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// allows checks if the program is allowed to run. A program with a directory must be allowed by its path,
+// and a program looked up in the PATH by its name.
+func (p *execPolicy) allows(program string) bool {
+	for _, a := range p.allowed {
+		if strings.ContainsAny(program, `/\`) {
+			if a == program {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(a, program) || strings.EqualFold(a, strings.TrimSuffix(program, ".exe")) {
+			return true
+		}
+	}
+	return false
+}
+
+// newResolvers returns the resolvers of the placeholder schemes enabled by the policies
+func newResolvers(exec *execPolicy, files *filePolicy) map[string]resolver {
+	rs := make(map[string]resolver)
+	if exec != nil {
+		rs["exec"] = exec.run
+	}
+	if files != nil {
+		rs["file"] = files.read
+	}
+	return rs
+}
+
+// check rejects a policy naming no program
+func (p *execPolicy) check() error {
+	if p != nil && len(p.allowed) == 0 {
+		return fmt.Errorf("%w: WithExec names no program", ErrExecNotAllowed)
+	}
+	return nil
+}
+
+// localDocument reports whether the document was read from local files only, so its ${exec:...}
+// and ${file:...} placeholders may be resolved
+func (c *Configuration) localDocument() bool {
+	if !c.local || c.bundle != nil || c.loader != nil || c.source != nil {
+		return false
+	}
+	for _, f := range c.chain {
+		if strings.HasPrefix(f, `http://`) || strings.HasPrefix(f, `https://`) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvers returns the resolvers of the placeholder schemes enabled for the configuration, with the
+// ${exec:...} and ${file:...} placeholders failing in the documents not read from local files
+func (c *Configuration) resolvers() map[string]resolver {
+	rs := newResolvers(c.exec, c.files)
+	if c.localDocument() {
+		return rs
+	}
+	if _, ok := rs["exec"]; ok {
+		rs["exec"] = func(command string) (string, error) {
+			return "", fmt.Errorf("%w: %s: only configurations loaded from local files run commands", ErrExecNotAllowed, command)
+		}
+	}
+	if _, ok := rs["file"]; ok {
+		rs["file"] = func(name string) (string, error) {
+			return "", fmt.Errorf("%w: %s: only configurations loaded from local files read files", ErrFileNotAllowed, name)
+		}
+	}
+	return rs
+}
@@ -0,0 +1,87 @@
+package cfg
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExecPlaceholders(t *testing.T) {
+	if _, err := exec.LookPath("echo"); err != nil {
+		t.Skip("echo is not available")
+	}
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"APIEndpoints": [{"ID": "DEFAULT", "Address": "https://api.example.com", "Token": "${exec:echo s3cret|upper}"}]}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(fn); !errors.Is(err, ErrPlaceholder) {
+		t.Fatalf(`Expected the exec placeholders to be disabled, got %v`, err)
+	}
+	if _, err := Load(fn, WithExec("op")); !errors.Is(err, ErrPlaceholder) || !errors.Is(err, ErrExecNotAllowed) {
+		t.Fatalf(`Expected ErrExecNotAllowed, got %v`, err)
+	}
+	if _, err := Load(fn, WithExec()); !errors.Is(err, ErrExecNotAllowed) {
+		t.Fatalf(`Expected WithExec without programs to be rejected, got %v`, err)
+	}
+	config, err := Load(fn, WithExec("echo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep := config.GetEndpointInfo("DEFAULT"); *ep.Token != "S3CRET" {
+		t.Fatalf(`Unexpected token %q`, *ep.Token)
+	}
+	if ips := config.Interpolations(); len(ips) != 1 || len(ips[0].Vars) != 0 {
+		t.Fatalf(`Unexpected interpolations %+v`, ips)
+	}
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile(fn); err != nil || !strings.Contains(string(b), "${exec:echo s3cret|upper}") {
+		t.Fatalf(`Expected the placeholder to be saved, got %s, %v`, b, err)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatalf(`Expected the commands to run on reload, got %v`, err)
+	}
+}
+
+func TestExecPolicy(t *testing.T) {
+	p := &execPolicy{allowed: []string{"op", "/usr/local/bin/vault"}}
+	tests := []struct {
+		program string
+		want    bool
+	}{
+		{"op", true},
+		{"OP.exe", true},
+		{"/tmp/op", false},
+		{"/usr/local/bin/vault", true},
+		{"vault", false},
+		{"aws", false},
+	}
+	for _, tt := range tests {
+		if got := p.allows(tt.program); got != tt.want {
+			t.Fatalf(`Allows %s = %v, expected %v`, tt.program, got, tt.want)
+		}
+	}
+	if _, err := (&execPolicy{allowed: []string{"false"}}).run("false"); err == nil {
+		t.Fatal(`Expected the failing command to fail`)
+	}
+}
+
+func TestExecPlaceholdersRemote(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"APIEndpoints": [{"ID": "DEFAULT", "Address": "https://api.example.com", "Token": "${exec:echo s3cret}"}]}`))
+	}))
+	defer srv.Close()
+	// a served configuration does not run commands on the host
+	if _, err := Load(srv.URL, WithExec("echo")); !errors.Is(err, ErrExecNotAllowed) {
+		t.Fatalf(`Expected ErrExecNotAllowed, got %v`, err)
+	}
+}
@@ -0,0 +1,411 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type (
+	// exprNode - a parsed expression evaluated with the resolver of its identifiers
+	exprNode func(resolve func(name string) (any, error)) (any, error)
+
+	// exprParser - recursive descent parser of expressions
+	exprParser struct {
+		src    string
+		tokens []string
+		pos    int
+	}
+)
+
+var ErrExpression = errors.New("invalid expression")
+
+// parseExpr parses an expression of string, number and boolean literals, identifiers like env or
+// Databases[DEFAULT].Schema, the operators ! - * / % + < <= > >= == != && || and ?:, and parentheses
+func parseExpr(src string) (exprNode, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &exprParser{src: src, tokens: tokens}
+	n, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return n, nil
+}
+
+// evalExpr parses and evaluates an expression
+func evalExpr(src string, resolve func(name string) (any, error)) (any, error) {
+	n, err := parseExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	return n(resolve)
+}
+
+// tokenizeExpr splits an expression into literals, identifiers and operators
+func tokenizeExpr(src string) ([]string, error) {
+	tokens := make([]string, 0)
+	rs := []rune(src)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			j := i + 1
+			for ; j < len(rs) && rs[j] != r; j++ {
+				if rs[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("%w: unterminated string in %q", ErrExpression, src)
+			}
+			tokens = append(tokens, string(rs[i:j+1]))
+			i = j + 1
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])):
+			j := i
+			for j < len(rs) && (unicode.IsDigit(rs[j]) || rs[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || unicode.IsDigit(rs[j]) || rs[j] == '_' || rs[j] == '.' || rs[j] == '[') {
+				if rs[j] == '[' {
+					for j < len(rs) && rs[j] != ']' {
+						j++
+					}
+					if j >= len(rs) {
+						return nil, fmt.Errorf("%w: unterminated [ in %q", ErrExpression, src)
+					}
+				}
+				j++
+			}
+			tokens = append(tokens, string(rs[i:j]))
+			i = j
+		default:
+			op := string(r)
+			if i+1 < len(rs) {
+				switch two := string(rs[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "&&", "||":
+					op = two
+				}
+			}
+			if !strings.Contains("!-*/%+<>=&|?:()", op[:1]) || op == "=" || op == "&" || op == "|" {
+				return nil, fmt.Errorf("%w: unexpected %q in %q", ErrExpression, op, src)
+			}
+			tokens = append(tokens, op)
+			i += len([]rune(op))
+		}
+	}
+	return tokens, nil
+}
+
+// errorf returns an ErrExpression with the source
+func (p *exprParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s in %q", ErrExpression, fmt.Sprintf(format, args...), p.src)
+}
+
+// accept consumes the token when it is one of the operators
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	if p.pos >= len(p.tokens) {
+		return "", false
+	}
+	for _, op := range ops {
+		if p.tokens[p.pos] == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// ternary parses cond ? a : b
+func (p *exprParser) ternary() (exprNode, error) {
+	cond, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+	a, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept(":"); !ok {
+		return nil, p.errorf("expected :")
+	}
+	b, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	return func(resolve func(string) (any, error)) (any, error) {
+		c, err := cond(resolve)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(c) {
+			return a(resolve)
+		}
+		return b(resolve)
+	}, nil
+}
+
+// binaryLevels are the binary operators by increasing precedence
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// binary parses the binary operators of a precedence level and above
+func (p *exprParser) binary(level int) (exprNode, error) {
+	if level == len(binaryLevels) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(binaryLevels[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode(op, left, right)
+	}
+}
+
+// binaryNode evaluates a binary operator. && and || short-circuit.
+func binaryNode(op string, left, right exprNode) exprNode {
+	return func(resolve func(string) (any, error)) (any, error) {
+		a, err := left(resolve)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "&&":
+			if !truthy(a) {
+				return false, nil
+			}
+		case "||":
+			if truthy(a) {
+				return true, nil
+			}
+		}
+		b, err := right(resolve)
+		if err != nil {
+			return nil, err
+		}
+		return applyBinary(op, a, b)
+	}
+}
+
+// applyBinary applies a binary operator to the values. Values are compared and added as numbers
+// when both are numbers, and as strings otherwise.
+func applyBinary(op string, a, b any) (any, error) {
+	na, aok := toNumber(a)
+	nb, bok := toNumber(b)
+	num := aok && bok
+	switch op {
+	case "&&", "||":
+		return truthy(b), nil
+	case "==", "!=":
+		eq := toText(a) == toText(b)
+		if num {
+			eq = na == nb
+		} else if ba, ok := a.(bool); ok {
+			eq = ba == truthy(b)
+		}
+		return eq == (op == "=="), nil
+	case "<", "<=", ">", ">=":
+		cmp := strings.Compare(toText(a), toText(b))
+		if num {
+			cmp = 0
+			if na < nb {
+				cmp = -1
+			} else if na > nb {
+				cmp = 1
+			}
+		}
+		switch op {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		}
+		return cmp >= 0, nil
+	case "+":
+		if num {
+			return na + nb, nil
+		}
+		return toText(a) + toText(b), nil
+	}
+	if !num {
+		return nil, fmt.Errorf("%w: %s needs numbers, got %q and %q", ErrExpression, op, toText(a), toText(b))
+	}
+	switch op {
+	case "-":
+		return na - nb, nil
+	case "*":
+		return na * nb, nil
+	case "/":
+		if nb == 0 {
+			return nil, fmt.Errorf("%w: division by zero", ErrExpression)
+		}
+		return na / nb, nil
+	}
+	if nb == 0 {
+		return nil, fmt.Errorf("%w: division by zero", ErrExpression)
+	}
+	return math.Mod(na, nb), nil
+}
+
+// unary parses ! and - prefixes
+func (p *exprParser) unary() (exprNode, error) {
+	op, ok := p.accept("!", "-")
+	if !ok {
+		return p.primary()
+	}
+	n, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return func(resolve func(string) (any, error)) (any, error) {
+		v, err := n(resolve)
+		if err != nil {
+			return nil, err
+		}
+		if op == "!" {
+			return !truthy(v), nil
+		}
+		f, ok := toNumber(v)
+		if !ok {
+			return nil, fmt.Errorf("%w: - needs a number, got %q", ErrExpression, toText(v))
+		}
+		return -f, nil
+	}, nil
+}
+
+// primary parses literals, identifiers and parentheses
+func (p *exprParser) primary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end")
+	}
+	if _, ok := p.accept("("); ok {
+		n, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := p.accept(")"); !ok {
+			return nil, p.errorf("expected )")
+		}
+		return n, nil
+	}
+	tok := p.tokens[p.pos]
+	p.pos++
+	literal := func(v any) exprNode {
+		return func(func(string) (any, error)) (any, error) { return v, nil }
+	}
+	switch r := rune(tok[0]); {
+	case r == '"' || r == '\'':
+		s := tok[1 : len(tok)-1]
+		if r == '"' {
+			us, err := strconv.Unquote(tok)
+			if err != nil {
+				return nil, p.errorf("invalid string %s", tok)
+			}
+			s = us
+		} else {
+			s = strings.ReplaceAll(strings.ReplaceAll(s, `\'`, `'`), `\\`, `\`)
+		}
+		return literal(s), nil
+	case unicode.IsDigit(r) || r == '.':
+		f, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %s", tok)
+		}
+		return literal(f), nil
+	case unicode.IsLetter(r) || r == '_':
+		switch tok {
+		case "true":
+			return literal(true), nil
+		case "false":
+			return literal(false), nil
+		}
+		return func(resolve func(string) (any, error)) (any, error) {
+			return resolve(tok)
+		}, nil
+	}
+	return nil, p.errorf("unexpected %q", tok)
+}
+
+// truthy reports whether a value is true: true, non-zero numbers and
+// strings other than empty, 0 and false
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case float64:
+		return t != 0
+	case string:
+		if b, err := strconv.ParseBool(strings.TrimSpace(t)); err == nil {
+			return b
+		}
+		return t != ""
+	}
+	if f, ok := toNumber(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+// toNumber converts numbers and numeric strings to float64
+func toNumber(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toText formats a value. Whole numbers have no decimals.
+func toText(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,44 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEvalExpr(t *testing.T) {
+	vars := map[string]any{"env": "prod", "region": "ap", "HostPort": int64(8000), "workers": "4"}
+	resolve := func(name string) (any, error) {
+		if v, ok := vars[name]; ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("unknown %s", name)
+	}
+	tests := []struct {
+		src  string
+		want any
+	}{
+		{`env == "prod" && region == 'ap'`, true},
+		{`env == "prod" && !(region == "ap")`, false},
+		{`env != "dev" || missing`, true},
+		{`HostPort > 0 ? 8 : 2`, float64(8)},
+		{`workers * 2 + 1`, float64(9)},
+		{`workers == 4`, true},
+		{`10 % 4 - -1`, float64(3)},
+		{`"v" + workers`, "v4"},
+		{`region < "eu"`, true},
+		{`2 + 3 * 4 == 14 ? "ok" : "no"`, "ok"},
+	}
+	for _, tt := range tests {
+		v, err := evalExpr(tt.src, resolve)
+		if err != nil || v != tt.want {
+			t.Fatalf(`Evaluating %s = %v, %v, expected %v`, tt.src, v, err, tt.want)
+		}
+	}
+	for _, src := range []string{`env ==`, `(1`, `1 ? 2`, `env = "prod"`, `"open`, `1 / 0`, `env - 1`, `Flags[Beta`, strings.Repeat("a", 63) + "["} {
+		if _, err := evalExpr(src, resolve); !errors.Is(err, ErrExpression) {
+			t.Fatalf(`Expected ErrExpression for %s, got %v`, src, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cfg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrUnknownSection = errors.New("unknown configuration section")
+
+// Extract returns a copy of the configuration with only the named sections like Databases or JWT,
+// for passing a minimal configuration to subprocesses or sidecars. Names are case insensitive.
+// The copy has no file name so it cannot be saved over the original.
+func (c *Configuration) Extract(sections ...string) (*Configuration, error) {
+	return c.selectSections(sections, true)
+}
+
+// Strip returns a copy of the configuration without the named sections, the complement of Extract
+func (c *Configuration) Strip(sections ...string) (*Configuration, error) {
+	return c.selectSections(sections, false)
+}
+
+// selectSections copies the configuration keeping the sections that are named or not named
+func (c *Configuration) selectSections(sections []string, named bool) (*Configuration, error) {
+	t := reflect.TypeOf(*c)
+	keep := make(map[string]bool, len(sections))
+	for _, s := range sections {
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && f.Name != "FileName" && c.sameKey(jsonName(f), s) {
+				keep[jsonName(f)], found = true, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownSection, s)
+		}
+	}
+	doc, err := configDocument(c)
+	if err != nil {
+		return nil, err
+	}
+	for k := range doc {
+		if keep[k] != named {
+			delete(doc, k)
+		}
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	nc := &Configuration{logger: c.logger, keyPolicy: c.keyPolicy, balance: c.balance}
+	if err = json.Unmarshal(b, nc); err != nil {
+		return nil, err
+	}
+	attachState(nc)
+	return nc, nil
+}
@@ -0,0 +1,39 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractStrip(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex, err := config.Extract("databases", "ApplicationID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Databases == nil || ex.ApplicationID == nil || ex.APIEndpoints != nil || ex.Flags != nil || ex.FileName != "" {
+		t.Fatalf(`Unexpected extracted configuration %v`, ex)
+	}
+	// the copy does not share the sections
+	(*ex.Databases)[0].ID = "CHANGED"
+	if (*config.Databases)[0].ID == "CHANGED" {
+		t.Fatal(`Expected the extracted sections to be copied`)
+	}
+	if err = ex.Save(); !errors.Is(err, ErrSaveNotLocalFile) {
+		t.Fatalf(`Expected ErrSaveNotLocalFile, got %v`, err)
+	}
+
+	st, err := config.Strip("Databases")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Databases != nil || st.ApplicationID == nil || st.APIEndpoints == nil {
+		t.Fatalf(`Unexpected stripped configuration %v`, st)
+	}
+	if _, err = config.Extract("Nope"); !errors.Is(err, ErrUnknownSection) {
+		t.Fatalf(`Expected ErrUnknownSection, got %v`, err)
+	}
+}
@@ -0,0 +1,78 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// filePolicy - files allowed to be read for the ${file:...} placeholders
+type filePolicy struct {
+	dirs []string // Directories of the files allowed to be read
+}
+
+var ErrFileNotAllowed = errors.New("file is not allowed")
+
+// WithFiles enables the ${file:path} placeholders, replaced on load with the content of the file
+// like ${file:/var/run/secrets/aws/credentials}, so credentials mounted or rotated by an agent are used.
+// Only the files in the directories named are read, after their symbolic links are followed, and at least
+// one directory must be named. The files are only read for the configurations loaded from local files.
+// The trailing line break of the content is removed. The path can not contain | or }.
+// On reload, the files are read when the loaded configuration enabled them.
+func WithFiles(dirs ...string) Option {
+	return func(o *options) {
+		o.files = &filePolicy{dirs: dirs}
+	}
+}
+
+// read returns the content of the file
+func (p *filePolicy) read(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("file name is empty")
+	}
+	if !p.allows(name) {
+		return "", fmt.Errorf("%w: %s", ErrFileNotAllowed, name)
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// check checks that the policy names a directory
+func (p *filePolicy) check() error {
+	if p != nil && len(p.dirs) == 0 {
+		return fmt.Errorf("%w: WithFiles names no directory", ErrFileNotAllowed)
+	}
+	return nil
+}
+
+// allows checks if the file is in one of the directories allowed, once the symbolic links are followed
+func (p *filePolicy) allows(name string) bool {
+	abs, err := realPath(name)
+	if err != nil {
+		return false
+	}
+	for _, d := range p.dirs {
+		ad, err := realPath(d)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(ad, abs); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// realPath returns the absolute path of the file with its symbolic links followed
+func realPath(name string) (string, error) {
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
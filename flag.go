@@ -1,14 +1,21 @@
 package cfg
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Flag - dynamic flags structure
 type Flag struct {
-	Key   string  `json:"key,omitempty"`
-	Value *string `json:"value,omitempty"`
+	Key       string   `json:"key,omitempty"`
+	Value     *string  `json:"value,omitempty"`
+	Public    bool     `json:"public,omitempty"`    // Exposed to clients by PublicView
+	Expr      string   `json:"expr,omitempty"`      // Expression computing the value on load like HostPort > 0 ? 8 : 2, over other flags and fields
+	DependsOn []string `json:"dependsOn,omitempty"` // Keys of the flags the Formula derives the value from
+	Formula   string   `json:"formula,omitempty"`   // Value derived on load from the DependsOn flags in braces like http://{host}:{port}
 }
 
 // Bool - return a boolean from flag value
@@ -85,3 +92,85 @@ func (f Flag) String() *string {
 	}
 	return f.Value
 }
+
+// FlagValue - the types a flag value is parsed to
+type FlagValue interface {
+	string | bool | int | int64 | float32 | float64 | time.Duration
+}
+
+var (
+	ErrFlagNotFound = errors.New("flag is not found")
+	ErrFlagNoValue  = errors.New("flag has no value")
+	ErrFlagParse    = errors.New("flag value is not valid")
+)
+
+// GetFlagErr gets the value of the flag with the key parsed to the type. Keys match like Flag.
+// It returns ErrFlagNotFound when there is no flag with the key, ErrFlagNoValue when the flag
+// has no value, and ErrFlagParse when the value does not parse, like a typo in a number.
+// Booleans are 1, on, yes, enabled, true, 0, off, no, disabled or false. Durations are like
+// 30s or a number of seconds.
+func GetFlagErr[T FlagValue](flgs []Flag, key string) (T, error) {
+	var zero T
+	key = strings.TrimSpace(key)
+	for _, f := range flgs {
+		if !KeyNormalize.equal(key, f.Key) {
+			continue
+		}
+		if f.Value == nil {
+			return zero, fmt.Errorf("%w: %s", ErrFlagNoValue, key)
+		}
+		v, err := parseFlag[T](strings.TrimSpace(*f.Value))
+		if err != nil {
+			return zero, fmt.Errorf("%w: %s: %q is not a %T", ErrFlagParse, key, *f.Value, zero)
+		}
+		return v, nil
+	}
+	return zero, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+}
+
+// GetFlagOK gets the value of the flag with the key parsed to the type like GetFlagErr,
+// and reports if the flag is found with a valid value
+func GetFlagOK[T FlagValue](flgs []Flag, key string) (T, bool) {
+	v, err := GetFlagErr[T](flgs, key)
+	return v, err == nil
+}
+
+// parseFlag parses the value of a flag to the type
+func parseFlag[T FlagValue](s string) (T, error) {
+	var (
+		v   any
+		err error
+		ret T
+	)
+	switch any(ret).(type) {
+	case string:
+		v = s
+	case bool:
+		switch strings.ToLower(s) {
+		case "1", "on", "yes", "enabled", "true":
+			v = true
+		case "0", "off", "no", "disabled", "false":
+			v = false
+		default:
+			err = strconv.ErrSyntax
+		}
+	case int:
+		v, err = strconv.Atoi(s)
+	case int64:
+		v, err = strconv.ParseInt(s, 0, 64)
+	case float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		v = float32(f)
+	case float64:
+		v, err = strconv.ParseFloat(s, 64)
+	case time.Duration:
+		var d Duration
+		d, err = parseDuration(s)
+		v = d.Duration()
+	}
+	if err != nil {
+		return ret, err
+	}
+	return v.(T), nil
+}
@@ -0,0 +1,54 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetFlagErr(t *testing.T) {
+	value := func(s string) *string { return &s }
+	flgs := []Flag{
+		{Key: "max_limit", Value: value("10000")},
+		{Key: "Retries", Value: value("1O")},
+		{Key: "Beta", Value: value("on")},
+		{Key: "Timeout", Value: value("1m30s")},
+		{Key: "Ratio", Value: value(" 0.25 ")},
+		{Key: "Name", Value: value("orders")},
+		{Key: "Unset"},
+	}
+	if v, err := GetFlagErr[int](flgs, "MaxLimit"); err != nil || v != 10000 {
+		t.Fatalf(`Unexpected int flag %d %v`, v, err)
+	}
+	if v, err := GetFlagErr[bool](flgs, "beta"); err != nil || !v {
+		t.Fatalf(`Unexpected bool flag %v %v`, v, err)
+	}
+	if v, err := GetFlagErr[time.Duration](flgs, "Timeout"); err != nil || v != 90*time.Second {
+		t.Fatalf(`Unexpected duration flag %s %v`, v, err)
+	}
+	if v, err := GetFlagErr[float64](flgs, "Ratio"); err != nil || v != 0.25 {
+		t.Fatalf(`Unexpected float flag %v %v`, v, err)
+	}
+	if v, ok := GetFlagOK[string](flgs, "Name"); !ok || v != "orders" {
+		t.Fatalf(`Unexpected string flag %s`, v)
+	}
+
+	for _, tt := range []struct {
+		key  string
+		want error
+	}{
+		{"Retries", ErrFlagParse},
+		{"Missing", ErrFlagNotFound},
+		{"Unset", ErrFlagNoValue},
+	} {
+		if _, err := GetFlagErr[int](flgs, tt.key); !errors.Is(err, tt.want) {
+			t.Errorf(`Expected %v for %s, got %v`, tt.want, tt.key, err)
+		}
+		if v, ok := GetFlagOK[int](flgs, tt.key); ok || v != 0 {
+			t.Errorf(`Expected no value for %s, got %d`, tt.key, v)
+		}
+	}
+	if _, err := GetFlagErr[bool](flgs, "Name"); !errors.Is(err, ErrFlagParse) {
+		t.Fatalf(`Expected ErrFlagParse for a bool, got %v`, err)
+	}
+}
@@ -0,0 +1,144 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type (
+	// Folder lists and moves the files of the folder of a source, on the local file system or
+	// a remote drop zone like an S3 bucket. Paths of the picked up files are relative to the store
+	// of the folder, so a picked up file is opened and moved with the folder that listed it.
+	Folder interface {
+		List(ctx context.Context) ([]Pickup, error)                        // Lists the files directly in the folder
+		Open(ctx context.Context, p Pickup) (io.ReadCloser, error)         // Opens a file of the folder
+		Move(ctx context.Context, p Pickup, folder string) (string, error) // Moves a file to a folder of the same store, like the ErrorFolder, and returns its new path
+		Remove(ctx context.Context, p Pickup) error                        // Removes a file of the folder
+	}
+
+	// FolderOpener opens the folder at the location, like ftp://host/inbound, with the credentials of the source
+	FolderOpener func(location *url.URL, si *SourceInfo) (Folder, error)
+
+	// localFolder - a folder on the local file system
+	localFolder struct {
+		dir string
+	}
+)
+
+var ErrUnsupportedFolder = errors.New("source folder scheme is not supported")
+
+var (
+	foldersMu sync.RWMutex
+	folders   = map[string]FolderOpener{"s3": openS3Folder, "sftp": openSFTPFolder}
+)
+
+// RegisterFolder registers the opener of the source folders of a scheme, like ftp, so SourceInfo.Source
+// can be a URI of the scheme. Local paths, file://, s3:// and sftp:// URIs are supported without registration.
+func RegisterFolder(scheme string, open FolderOpener) {
+	foldersMu.Lock()
+	defer foldersMu.Unlock()
+	folders[strings.ToLower(scheme)] = open
+}
+
+// isFolderURI checks if the location is a URI like s3://bucket/inbound rather than a local path
+func isFolderURI(location string) bool {
+	i := strings.Index(location, "://")
+	return i > 1 && !strings.ContainsAny(location[:i], `/\`)
+}
+
+// openFolder opens the folder at the location with the credentials of the source
+func openFolder(location string, si *SourceInfo) (Folder, error) {
+	if !isFolderURI(location) {
+		return &localFolder{dir: location}, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "file" {
+		return &localFolder{dir: filepath.FromSlash(u.Path)}, nil
+	}
+	foldersMu.RLock()
+	open, ok := folders[scheme]
+	foldersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s, register an opener with RegisterFolder", ErrUnsupportedFolder, u.Scheme)
+	}
+	return open(u, si)
+}
+
+// Folder opens the Source folder of the source
+func (si *SourceInfo) Folder() (Folder, error) {
+	if si.Source == "" {
+		return nil, fmt.Errorf("source %s: %w", si.ID, ErrNoSourceFolder)
+	}
+	return openFolder(si.Source, si)
+}
+
+// List lists the regular files of the folder, oldest first
+func (lf *localFolder) List(ctx context.Context) ([]Pickup, error) {
+	des, err := os.ReadDir(lf.dir)
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]Pickup, 0)
+	for _, de := range des {
+		if !de.Type().IsRegular() {
+			continue
+		}
+		fi, err := de.Info()
+		if err != nil {
+			// the file was moved by another consumer
+			continue
+		}
+		pks = append(pks, Pickup{
+			Path:    filepath.Join(lf.dir, de.Name()),
+			Name:    de.Name(),
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		})
+	}
+	sortPickups(pks)
+	return pks, nil
+}
+
+// Open opens the file
+func (lf *localFolder) Open(ctx context.Context, p Pickup) (io.ReadCloser, error) {
+	return os.Open(p.Path)
+}
+
+// Move moves the file to the folder
+func (lf *localFolder) Move(ctx context.Context, p Pickup, folder string) (string, error) {
+	if isFolderURI(folder) {
+		u, err := url.Parse(folder)
+		if err != nil || !strings.EqualFold(u.Scheme, "file") {
+			return "", fmt.Errorf("%w: moving a local file to %s", ErrUnsupportedFolder, folder)
+		}
+		folder = filepath.FromSlash(u.Path)
+	}
+	return moveFile(p.Path, folder)
+}
+
+// Remove removes the file
+func (lf *localFolder) Remove(ctx context.Context, p Pickup) error {
+	return os.Remove(p.Path)
+}
+
+// sortPickups sorts the files oldest first, then by name
+func sortPickups(pks []Pickup) {
+	sort.SliceStable(pks, func(i, j int) bool {
+		if pks[i].ModTime.Equal(pks[j].ModTime) {
+			return pks[i].Name < pks[j].Name
+		}
+		return pks[i].ModTime.Before(pks[j].ModTime)
+	})
+}
@@ -1,3 +1,21 @@
 module github.com/eaglebush/config
 
 go 1.19
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/pkg/sftp v1.13.5
+	golang.org/x/crypto v0.8.0
+	google.golang.org/grpc v1.56.3
+	google.golang.org/protobuf v1.31.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/kr/fs v0.1.0 // indirect
+	golang.org/x/net v0.9.0 // indirect
+	golang.org/x/sys v0.7.0 // indirect
+	golang.org/x/text v0.9.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+)
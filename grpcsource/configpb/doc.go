@@ -0,0 +1,4 @@
+// Package configpb contains the ConfigService protocol of grpcsource, generated from config.proto with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative config.proto
+package configpb
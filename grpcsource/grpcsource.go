@@ -0,0 +1,113 @@
+// Package grpcsource loads configuration from a gRPC ConfigService, defined in configpb/config.proto,
+// with streaming updates:
+//
+//	conn, err := grpc.Dial(target, grpc.WithTransportCredentials(creds))
+//	src := grpcsource.New(conn, "orders")
+//	c, err := cfg.LoadSource(ctx, src)
+//	go c.WatchEvents(ctx)
+package grpcsource
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	cfg "github.com/eaglebush/config"
+	"github.com/eaglebush/config/grpcsource/configpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Source - configuration source on a gRPC ConfigService
+type Source struct {
+	client configpb.ConfigServiceClient
+	name   string
+
+	mu      sync.Mutex
+	version string // Version of the last fetched document
+}
+
+// New returns the source of the named configuration on the connection
+func New(conn grpc.ClientConnInterface, name string) *Source {
+	return &Source{client: configpb.NewConfigServiceClient(conn), name: name}
+}
+
+// String returns the name of the source
+func (s *Source) String() string {
+	return "grpc:" + s.name
+}
+
+// Fetch gets the document of the configuration in JSON
+func (s *Source) Fetch(ctx context.Context) ([]byte, error) {
+	doc, err := s.client.GetConfig(ctx, &configpb.GetConfigRequest{Name: s.name})
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.version = doc.GetVersion()
+	s.mu.Unlock()
+	return toJSON(doc)
+}
+
+// Watch streams the changes of the configuration and calls changed on each version
+// other than the last fetched one
+func (s *Source) Watch(ctx context.Context, changed func()) error {
+	s.mu.Lock()
+	version := s.version
+	s.mu.Unlock()
+	st, err := s.client.WatchConfig(ctx, &configpb.WatchConfigRequest{Name: s.name, Version: version})
+	if err != nil {
+		return err
+	}
+	for {
+		doc, err := st.Recv()
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		same := doc.GetVersion() != "" && doc.GetVersion() == s.version
+		s.mu.Unlock()
+		if !same {
+			changed()
+		}
+	}
+}
+
+// MutualTLS returns the transport credentials for mutual TLS with the client certificate
+// and key, verifying the server with the certificate authorities in the CA file
+func MutualTLS(certFile, keyFile, caFile string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, errors.New("grpcsource: no certificates in " + caFile)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// toJSON converts the document to JSON
+func toJSON(doc *configpb.ConfigDocument) ([]byte, error) {
+	f := cfg.Format(strings.ToLower(doc.GetFormat()))
+	switch f {
+	case "", cfg.FormatJSON:
+		return doc.GetContent(), nil
+	case cfg.FormatYAML, cfg.FormatTOML:
+		return cfg.Convert(doc.GetContent(), f, cfg.FormatJSON)
+	}
+	return nil, fmt.Errorf("%w: %q", cfg.ErrUnknownFormat, doc.GetFormat())
+}
@@ -0,0 +1,99 @@
+package grpcsource
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	cfg "github.com/eaglebush/config"
+	"github.com/eaglebush/config/grpcsource/configpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testServer - a ConfigService with a document changed by the test
+type testServer struct {
+	configpb.UnimplementedConfigServiceServer
+	mu      sync.Mutex
+	doc     *configpb.ConfigDocument
+	changes chan *configpb.ConfigDocument
+}
+
+func (s *testServer) GetConfig(ctx context.Context, req *configpb.GetConfigRequest) (*configpb.ConfigDocument, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.doc, nil
+}
+
+func (s *testServer) WatchConfig(req *configpb.WatchConfigRequest, st configpb.ConfigService_WatchConfigServer) error {
+	for {
+		select {
+		case <-st.Context().Done():
+			return st.Context().Err()
+		case doc := <-s.changes:
+			s.mu.Lock()
+			s.doc = doc
+			s.mu.Unlock()
+			if err := st.Send(doc); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func TestSource(t *testing.T) {
+	lis := bufconn.Listen(1 << 20)
+	ts := &testServer{
+		doc:     &configpb.ConfigDocument{Content: []byte("hostport: 8000\n"), Format: "yaml", Version: "1"},
+		changes: make(chan *configpb.ConfigDocument),
+	}
+	gs := grpc.NewServer()
+	configpb.RegisterConfigServiceServer(gs, ts)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reloaded := make(chan int, 1)
+	c, err := cfg.LoadSource(context.Background(), New(conn, "orders"), cfg.WithReloadCheck(func(c *cfg.Configuration) error {
+		reloaded <- *c.HostPort
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *c.HostPort != 8000 || c.FileName != "grpc:orders" {
+		t.Fatalf(`Unexpected configuration %d %s`, *c.HostPort, c.FileName)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WatchEvents(ctx)
+	}()
+	ts.changes <- &configpb.ConfigDocument{Content: []byte(`{"HostPort": 8080}`), Version: "2"}
+	select {
+	case port := <-reloaded:
+		if port != 8080 {
+			t.Fatalf(`Unexpected port %d`, port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal(`Expected a reload on the streamed change`)
+	}
+	cancel()
+	if err = <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected the watch to stop on cancel, got %v`, err)
+	}
+}
@@ -0,0 +1,102 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type (
+	// HealthCheckers - functions that check the dependencies configured in the Health section.
+	// A dependency without a checker is skipped, except endpoints that are checked by an HTTP GET.
+	HealthCheckers struct {
+		Database func(ctx context.Context, db DatabaseInfo) error
+		Cache    func(ctx context.Context, ch CacheInfo) error
+		Endpoint func(ctx context.Context, ep EndpointInfo) error
+		Queue    func(ctx context.Context, q QueueInfo) error
+	}
+
+	// HealthResult - result of a dependency check
+	HealthResult struct {
+		Name     string        // Name of the dependency like database:DEFAULT
+		Err      error         // Error of the check. Nil when healthy
+		Duration time.Duration // Time spent on the check
+	}
+
+	// HealthReport - results of the readiness checks
+	HealthReport struct {
+		Healthy bool
+		Results []HealthResult
+	}
+)
+
+// CheckHealth runs the readiness checks configured in the Health section
+func (c *Configuration) CheckHealth(ctx context.Context, hc HealthCheckers) HealthReport {
+	c = c.view()
+	rpt := HealthReport{Healthy: true}
+	if c.Health == nil {
+		return rpt
+	}
+	c.record(`Health`, "")
+	if hc.Endpoint == nil {
+		hc.Endpoint = checkEndpoint
+	}
+	timeout := time.Duration(c.Health.Timeout) * time.Second
+	run := func(name string, check func(ctx context.Context) error) {
+		cctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		start := time.Now()
+		err := check(cctx)
+		rpt.Results = append(rpt.Results, HealthResult{
+			Name:     name,
+			Err:      err,
+			Duration: time.Since(start),
+		})
+		if err != nil {
+			rpt.Healthy = false
+		}
+	}
+	if hc.Database != nil {
+		for _, id := range c.Health.DatabaseIDs {
+			db := c.GetDatabaseInfo(id)
+			if db == nil {
+				continue
+			}
+			run("database:"+db.ID, func(ctx context.Context) error { return hc.Database(ctx, *db) })
+		}
+	}
+	if hc.Cache != nil && c.Health.CacheID != "" && c.Cache != nil {
+		ch := *c.Cache
+		run("cache:"+ch.ID, func(ctx context.Context) error { return hc.Cache(ctx, ch) })
+	}
+	for _, id := range c.Health.EndpointIDs {
+		ep := c.GetEndpointInfo(id)
+		if ep == nil {
+			continue
+		}
+		run("endpoint:"+ep.ID, func(ctx context.Context) error { return hc.Endpoint(ctx, *ep) })
+	}
+	if hc.Queue != nil && c.Health.Queue && c.Queue != nil {
+		q := *c.Queue
+		run("queue:"+q.ID, func(ctx context.Context) error { return hc.Queue(ctx, q) })
+	}
+	return rpt
+}
+
+// checkEndpoint checks if the endpoint address responds without a server error
+func checkEndpoint(ctx context.Context, ep EndpointInfo) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ep.Address, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("endpoint %s returned status %d", ep.ID, res.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckHealth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	c := &Configuration{
+		Databases:    &[]DatabaseInfo{{ID: "DEFAULT"}, {ID: "REPORTS"}},
+		APIEndpoints: &[]EndpointInfo{{ID: "api", Address: srv.URL}},
+		Cache:        &CacheInfo{ID: "DEFAULT"},
+		Health: &HealthInfo{
+			DatabaseIDs: []string{"DEFAULT", "REPORTS"},
+			EndpointIDs: []string{"api"},
+			CacheID:     "DEFAULT",
+			Timeout:     1,
+		},
+	}
+	if err := c.validate(); err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	rpt := c.CheckHealth(context.Background(), HealthCheckers{
+		Database: func(ctx context.Context, db DatabaseInfo) error {
+			if db.ID == "REPORTS" {
+				return errors.New("unreachable")
+			}
+			return nil
+		},
+		Cache: func(ctx context.Context, ch CacheInfo) error { return nil },
+	})
+	if rpt.Healthy || len(rpt.Results) != 4 {
+		t.Fatalf(`Unexpected report %+v`, rpt)
+	}
+	for _, r := range rpt.Results {
+		if (r.Err != nil) != (r.Name == "database:REPORTS") {
+			t.Errorf(`Unexpected result %s: %v`, r.Name, r.Err)
+		}
+	}
+
+	c.Health.DatabaseIDs = append(c.Health.DatabaseIDs, "MISSING")
+	if err := c.validate(); !errors.Is(err, ErrHealthReference) {
+		t.Fatalf(`Expected ErrHealthReference, got %v`, err)
+	}
+}
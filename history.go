@@ -0,0 +1,93 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Snapshot - a version of the configuration retained in the history
+	Snapshot struct {
+		Kind    EventKind // Load, reload or rollback that produced the version
+		Time    time.Time // Time the version became live
+		Source  string    // Source of the configuration
+		Changes []Change  // Changes from the previous version with secrets redacted. Nil for the first version
+		File    string    // File of the redacted version when the history is kept on disk
+	}
+
+	// history - versions shared across reloads of a configuration
+	history struct {
+		sync.Mutex
+		max       int
+		dir       string
+		snapshots []Snapshot
+		last      *Configuration
+	}
+)
+
+// WithHistory retains the last n versions of the configuration with the time and the changes
+// from the previous version, queryable with History. When dir is set, each version is also written
+// there as a redacted JSON document and the files of the versions dropped from the history are removed.
+func WithHistory(n int, dir string) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.history = &history{max: n, dir: dir}
+		}
+	}
+}
+
+// History returns the retained versions of the configuration, oldest first.
+// It returns nil when the configuration was not loaded with WithHistory.
+func (c *Configuration) History() []Snapshot {
+	if c.history == nil {
+		return nil
+	}
+	c.history.Lock()
+	defer c.history.Unlock()
+	return append([]Snapshot(nil), c.history.snapshots...)
+}
+
+// snapshot adds the current configuration to the history
+func (c *Configuration) snapshot(kind EventKind) {
+	h := c.history
+	if h == nil {
+		return
+	}
+	h.Lock()
+	defer h.Unlock()
+
+	s := Snapshot{Kind: kind, Time: c.now(), Source: c.FileName}
+	if h.last != nil {
+		chs, err := Diff(h.last, c)
+		if err != nil {
+			c.log().Warn("configuration history diff failed", "error", err)
+		}
+		s.Changes = chs
+	}
+	if h.dir != "" {
+		if b, err := c.Render(FormatJSON, true); err != nil {
+			c.log().Warn("configuration history render failed", "error", err)
+		} else {
+			name := strings.ReplaceAll(s.Time.UTC().Format("20060102T150405.000000000"), ".", "") + "-" + string(kind) + ".json"
+			s.File = filepath.Join(h.dir, name)
+			if err = os.WriteFile(s.File, b, 0600); err != nil {
+				c.log().Warn("configuration history write failed", "file", s.File, "error", err)
+				s.File = ""
+			}
+		}
+	}
+	last := *c
+	last.previous = nil
+	h.last = &last
+
+	h.snapshots = append(h.snapshots, s)
+	for len(h.snapshots) > h.max {
+		if f := h.snapshots[0].File; f != "" {
+			os.Remove(f)
+		}
+		h.snapshots = h.snapshots[1:]
+	}
+}
@@ -0,0 +1,72 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "config.json")
+	hdir := filepath.Join(dir, "history")
+	if err := os.Mkdir(hdir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	write := func(doc string) {
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+
+	write(`{"HostPort": 8000, "JWTSecret": "first"}`)
+	config, err := Load(fn, WithHistory(3, hdir), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	write(`{"HostPort": 8080, "JWTSecret": "second"}`)
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	hs := config.History()
+	if len(hs) != 3 || hs[0].Kind != EventLoad || hs[1].Kind != EventReload || hs[2].Kind != EventRollback {
+		t.Fatalf(`Unexpected history %+v`, hs)
+	}
+	if hs[0].Changes != nil || len(hs[1].Changes) != 2 || !hs[1].Time.After(hs[0].Time) {
+		t.Fatalf(`Unexpected snapshots %+v`, hs)
+	}
+	for _, ch := range hs[1].Changes {
+		if strings.Contains(ch.String(), "second") {
+			t.Fatalf(`Secret was not redacted: %s`, ch)
+		}
+	}
+	b, err := os.ReadFile(hs[1].File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "8080") || strings.Contains(string(b), "second") {
+		t.Fatalf(`Unexpected snapshot file %s`, b)
+	}
+
+	write(`{"HostPort": 9000}`)
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if hs = config.History(); len(hs) != 3 || hs[0].Kind != EventReload {
+		t.Fatalf(`Expected the oldest version to be dropped, got %+v`, hs)
+	}
+	if fs, _ := os.ReadDir(hdir); len(fs) != 3 {
+		t.Fatalf(`Expected 3 snapshot files, got %d`, len(fs))
+	}
+}
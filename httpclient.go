@@ -0,0 +1,55 @@
+package cfg
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// tlsVersions are the TLS versions of the TLSMinVersion
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// httpClient returns the client of the proxy and the client setting. The default client is returned
+// when neither is set.
+func httpClient(p *ProxyInfo, hc *HTTPClientInfo) *http.Client {
+	if p == nil && hc == nil {
+		return http.DefaultClient
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if p != nil {
+		tr.Proxy = p.ProxyFunc()
+	}
+	client := &http.Client{Transport: tr}
+	if hc == nil {
+		return client
+	}
+	if hc.Timeout != nil {
+		client.Timeout = time.Duration(*hc.Timeout)
+	}
+	if hc.MaxIdleConns > 0 {
+		tr.MaxIdleConns = hc.MaxIdleConns
+	}
+	tr.DisableKeepAlives = hc.DisableKeepAlives
+	tr.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if v, ok := tlsVersions[hc.TLSMinVersion]; ok {
+		tr.TLSClientConfig.MinVersion = v
+	}
+	return client
+}
+
+// DefaultHTTPClient returns the client of the HTTPClient and Proxy sections for the calls of the application.
+// The client is built on load and shared by the calls, so its connections are reused. It is the default client
+// of net/http when neither section is set.
+func (c *Configuration) DefaultHTTPClient() *http.Client {
+	c = c.view()
+	c.record(`HTTPClient`, "")
+	if c.client == nil {
+		return httpClient(c.Proxy, c.HTTPClient)
+	}
+	return c.client
+}
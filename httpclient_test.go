@@ -0,0 +1,67 @@
+package cfg
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDefaultHTTPClient(t *testing.T) {
+	if (&Configuration{}).DefaultHTTPClient() != http.DefaultClient {
+		t.Fatal(`Expected the default client without the sections`)
+	}
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"ApplicationID": "orders"}`))
+	}))
+	defer srv.Close()
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"HTTPClient": {"Timeout": "5s", "MaxIdleConns": 10, "TLSMinVersion": "1.3", "DisableKeepAlives": true}}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := config.DefaultHTTPClient()
+	if client != config.DefaultHTTPClient() {
+		t.Fatal(`Expected the client to be shared`)
+	}
+	tr := client.Transport.(*http.Transport)
+	if client.Timeout != 5*time.Second || tr.MaxIdleConns != 10 || !tr.DisableKeepAlives || tr.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+		t.Fatalf(`Unexpected client %+v %+v`, client, tr)
+	}
+	if res, err := client.Get(srv.URL); err != nil || res.StatusCode != http.StatusOK {
+		t.Fatalf(`Unexpected response %v`, err)
+	} else {
+		res.Body.Close()
+	}
+
+	second := Duration(time.Second)
+	o := newOptions(WithHTTPClient(&HTTPClientInfo{Timeout: &second}))
+	if o.fetchClient().Timeout != time.Second {
+		t.Fatal(`Expected the timeout of the option on fetching`)
+	}
+	remote, err := Load(srv.URL, WithHTTPClient(config.HTTPClient))
+	if err != nil || requests != 2 {
+		t.Fatalf(`Unexpected remote load %v, %d requests`, err, requests)
+	}
+	// the section of the loaded configuration is used on reload
+	remote.HTTPClient = &HTTPClientInfo{Timeout: &second}
+	if err = remote.Reload(); err != nil || requests != 3 {
+		t.Fatalf(`Unexpected reload %v`, err)
+	}
+
+	config.HTTPClient.TLSMinVersion = "1.4"
+	if err = config.Validate(); !errors.Is(err, ErrInvalidEnum) {
+		t.Fatalf(`Expected ErrInvalidEnum, got %v`, err)
+	}
+}
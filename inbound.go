@@ -0,0 +1,346 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pickup - a file found in the folder of a source
+type Pickup struct {
+	SourceID string    // ID of the source
+	Path     string    // Path of the file in the store of the folder, like a local path or the key of an object
+	Name     string    // Name of the file
+	Size     int64     // Size of the file in bytes
+	ModTime  time.Time // Modification time of the file
+}
+
+var ErrNoSourceFolder = errors.New("source folder is not set")
+
+// folder returns the folder, relative to the Source when Relative is set. The folders of a remote
+// Source are in the same store, like the same bucket, unless they are URIs.
+func (si *SourceInfo) folder(name string) string {
+	if name == "" || isFolderURI(name) || !isFolderURI(si.Source) {
+		if si.Relative && name != "" && !filepath.IsAbs(name) && !isFolderURI(name) {
+			return filepath.Join(si.Source, name)
+		}
+		return name
+	}
+	u, err := url.Parse(si.Source)
+	if err != nil {
+		return name
+	}
+	if si.Relative && !strings.HasPrefix(name, "/") {
+		u.Path = path.Join("/", u.Path, name)
+	} else {
+		u.Path = path.Join("/", name)
+	}
+	return u.String()
+}
+
+// ErrorFolder returns the Error folder, relative to the Source when Relative is set
+func (si *SourceInfo) ErrorFolder() string {
+	return si.folder(si.Error)
+}
+
+// SuccessFolder returns the Success folder, relative to the Source when Relative is set
+func (si *SourceInfo) SuccessFolder() string {
+	return si.folder(si.Success)
+}
+
+// ArchiveFolder returns the Archive folder, relative to the Source when Relative is set
+func (si *SourceInfo) ArchiveFolder() string {
+	return si.folder(si.Archive)
+}
+
+// matches checks if the file name has the Extension of the source. An empty Extension matches all files.
+func (si *SourceInfo) matches(name string) bool {
+	if si.Extension == "" {
+		return true
+	}
+	ext := si.Extension
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return strings.EqualFold(filepath.Ext(name), ext)
+}
+
+// Pending lists the files in the Source folder with the Extension, oldest first
+func (si *SourceInfo) Pending() ([]Pickup, error) {
+	return si.pending(context.Background())
+}
+
+// pending lists the files in the Source folder with the Extension
+func (si *SourceInfo) pending(ctx context.Context) ([]Pickup, error) {
+	f, err := si.Folder()
+	if err != nil {
+		return nil, err
+	}
+	all, err := f.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]Pickup, 0, len(all))
+	for _, p := range all {
+		if si.matches(p.Name) {
+			p.SourceID = si.ID
+			pks = append(pks, p)
+		}
+	}
+	return pks, nil
+}
+
+// Open opens a picked up file of the source
+func (si *SourceInfo) Open(ctx context.Context, p Pickup) (io.ReadCloser, error) {
+	f, err := si.Folder()
+	if err != nil {
+		return nil, err
+	}
+	return f.Open(ctx, p)
+}
+
+// moveTo moves a picked up file to a folder of the source
+func (si *SourceInfo) moveTo(p Pickup, folder string) (string, error) {
+	if folder == "" {
+		return "", ErrNoSourceFolder
+	}
+	f, err := si.Folder()
+	if err != nil {
+		return "", err
+	}
+	return f.Move(context.Background(), p, folder)
+}
+
+// MoveToError moves a picked up file to the Error folder and returns its new path
+func (si *SourceInfo) MoveToError(p Pickup) (string, error) {
+	return si.moveTo(p, si.ErrorFolder())
+}
+
+// MoveToSuccess moves a picked up file to the Success folder and returns its new path
+func (si *SourceInfo) MoveToSuccess(p Pickup) (string, error) {
+	return si.moveTo(p, si.SuccessFolder())
+}
+
+// MoveToArchive moves a picked up file to the Archive folder and returns its new path
+func (si *SourceInfo) MoveToArchive(p Pickup) (string, error) {
+	return si.moveTo(p, si.ArchiveFolder())
+}
+
+// Cleanup removes the files older than RetentionDays from the Error, Success and Archive folders,
+// local or remote, and returns their paths. Folders that do not exist are skipped. Nothing is removed when
+// RetentionDays is not set.
+func (si *SourceInfo) Cleanup(ctx context.Context) ([]string, error) {
+	removed := make([]string, 0)
+	if si.RetentionDays <= 0 {
+		return removed, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -si.RetentionDays)
+	done := make(map[string]bool)
+	for _, name := range []string{si.Error, si.Success, si.Archive} {
+		if name == "" {
+			continue
+		}
+		loc := si.folder(name)
+		if !isFolderURI(loc) {
+			loc = filepath.Clean(loc)
+		}
+		if done[loc] || loc == si.Source || loc == filepath.Clean(si.Source) {
+			// the pending files are never pruned
+			continue
+		}
+		done[loc] = true
+		f, err := openFolder(loc, si)
+		if err != nil {
+			return removed, err
+		}
+		pks, err := f.List(ctx)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		for _, p := range pks {
+			if err = ctx.Err(); err != nil {
+				return removed, err
+			}
+			if !p.ModTime.Before(cutoff) {
+				continue
+			}
+			if err = f.Remove(ctx, p); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return removed, err
+			}
+			removed = append(removed, p.Path)
+		}
+	}
+	return removed, nil
+}
+
+// CleanupSources removes the old files of all Sources like SourceInfo.Cleanup and returns their paths
+func (c *Configuration) CleanupSources(ctx context.Context) ([]string, error) {
+	removed := make([]string, 0)
+	if c.Sources == nil {
+		return removed, nil
+	}
+	for _, si := range *c.Sources {
+		r, err := si.Cleanup(ctx)
+		removed = append(removed, r...)
+		if err != nil {
+			return removed, fmt.Errorf("source %s: %w", si.ID, err)
+		}
+	}
+	return removed, nil
+}
+
+// moveFile moves the file into the folder, creating the folder. A file of the same name in the folder
+// is kept by adding the time to the name of the moved file. Files are copied across devices.
+func moveFile(name, folder string) (string, error) {
+	if folder == "" {
+		return "", ErrNoSourceFolder
+	}
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		return "", err
+	}
+	base := filepath.Base(name)
+	dst := filepath.Join(folder, base)
+	if _, err := os.Stat(dst); err == nil {
+		ext := filepath.Ext(base)
+		dst = filepath.Join(folder, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(base, ext), time.Now().UnixNano(), ext))
+	}
+	if err := os.Rename(name, dst); err == nil {
+		return dst, nil
+	}
+	if err := copyFile(name, dst); err != nil {
+		return "", err
+	}
+	return dst, os.Remove(name)
+}
+
+// copyFile copies the file with its permissions
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// Watch polls the Source folder at the interval, default 1 second, and calls pickup once for each
+// file with the Extension, oldest first, until the context is done. A file is picked up when its size
+// and modification time did not change since the previous poll, so files still being written are
+// not picked up. Files larger than MaxFileSize are moved to the Error folder instead. The handler moves
+// the file with MoveToSuccess, MoveToError or MoveToArchive; a file left in the folder is not picked
+// up again until it changes.
+func (si *SourceInfo) Watch(ctx context.Context, interval time.Duration, pickup func(Pickup)) error {
+	if si.Source == "" {
+		return fmt.Errorf("source %s: %w", si.ID, ErrNoSourceFolder)
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	type state struct {
+		size    int64
+		modTime time.Time
+		done    bool
+	}
+	seen := make(map[string]state)
+	tk := time.NewTicker(interval)
+	defer tk.Stop()
+	for {
+		pks, err := si.pending(ctx)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		found := make(map[string]bool, len(pks))
+		for _, p := range pks {
+			found[p.Path] = true
+			st, ok := seen[p.Path]
+			if ok && st.size == p.Size && st.modTime.Equal(p.ModTime) {
+				if !st.done {
+					seen[p.Path] = state{size: p.Size, modTime: p.ModTime, done: true}
+					if si.MaxFileSize > 0 && p.Size > si.MaxFileSize {
+						if _, err = si.MoveToError(p); err != nil {
+							return err
+						}
+						continue
+					}
+					pickup(p)
+				}
+				continue
+			}
+			seen[p.Path] = state{size: p.Size, modTime: p.ModTime}
+		}
+		for path := range seen {
+			if !found[path] {
+				delete(seen, path)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tk.C:
+		}
+	}
+}
+
+// WatchSources watches the folders of all Sources like SourceInfo.Watch and calls pickup with the files
+// picked up, one at a time, until the context is done. It returns the first error of a source.
+func (c *Configuration) WatchSources(ctx context.Context, interval time.Duration, pickup func(Pickup)) error {
+	if c.Sources == nil || len(*c.Sources) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu    sync.Mutex
+		wg    sync.WaitGroup
+		first error
+	)
+	for _, si := range *c.Sources {
+		si := si
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := si.Watch(ctx, interval, func(p Pickup) {
+				mu.Lock()
+				defer mu.Unlock()
+				pickup(p)
+			})
+			if err != nil && ctx.Err() == nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,112 @@
+package cfg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSourceWatch(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "inbound")
+	if err := os.MkdirAll(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	si := SourceInfo{ID: "order", Source: in, Relative: true, Error: "error", Success: "archive", Extension: "csv"}
+	if si.SuccessFolder() != filepath.Join(in, "archive") || si.ErrorFolder() != filepath.Join(in, "error") {
+		t.Fatalf(`Unexpected folders %s %s`, si.SuccessFolder(), si.ErrorFolder())
+	}
+	for _, name := range []string{"a.csv", "b.CSV", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(in, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	config := &Configuration{Sources: &[]SourceInfo{si}}
+	picked := make([]string, 0)
+	err := config.WatchSources(ctx, 10*time.Millisecond, func(p Pickup) {
+		picked = append(picked, p.Name)
+		var err error
+		if p.Name == "a.csv" {
+			_, err = si.MoveToSuccess(p)
+		} else {
+			_, err = si.MoveToError(p)
+		}
+		if err != nil {
+			t.Error(err)
+		}
+		if len(picked) == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled {
+		t.Fatalf(`Expected the watch to end with the context, got %v`, err)
+	}
+	if len(picked) != 2 {
+		t.Fatalf(`Expected the csv files picked up once, got %v`, picked)
+	}
+	for _, name := range []string{filepath.Join(in, "archive", "a.csv"), filepath.Join(in, "error", "b.CSV"), filepath.Join(in, "c.txt")} {
+		if _, err := os.Stat(name); err != nil {
+			t.Fatalf(`Expected %s: %v`, name, err)
+		}
+	}
+}
+
+func TestSourceCleanup(t *testing.T) {
+	in := t.TempDir()
+	si := SourceInfo{ID: "order", Source: in, Relative: true, Error: "error", Success: "success", Archive: "archive", RetentionDays: 7}
+	old := time.Now().AddDate(0, 0, -8)
+	files := map[string]bool{
+		filepath.Join(in, "error", "old.csv"):     true,
+		filepath.Join(in, "error", "new.csv"):     false,
+		filepath.Join(in, "archive", "old.csv"):   true,
+		filepath.Join(in, "pending.csv"):          false,
+		filepath.Join(in, "success", "fresh.csv"): false,
+	}
+	for name, expired := range files {
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if expired || name == filepath.Join(in, "pending.csv") {
+			if err := os.Chtimes(name, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	config := &Configuration{Sources: &[]SourceInfo{si}}
+	removed, err := config.CleanupSources(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 2 {
+		t.Fatalf(`Expected 2 files removed, got %v`, removed)
+	}
+	for name, expired := range files {
+		if _, err := os.Stat(name); os.IsNotExist(err) != expired {
+			t.Errorf(`Unexpected existence of %s: %v`, name, err)
+		}
+	}
+}
+
+func TestSourceMaxFileSize(t *testing.T) {
+	in := t.TempDir()
+	si := SourceInfo{ID: "order", Source: in, Relative: true, Error: "error", Extension: ".csv", MaxFileSize: 4}
+	if err := os.WriteFile(filepath.Join(in, "big.csv"), []byte("too large"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	si.Watch(ctx, 10*time.Millisecond, func(p Pickup) {
+		t.Errorf(`Unexpected pickup of %s`, p.Name)
+	})
+	if _, err := os.Stat(filepath.Join(in, "error", "big.csv")); err != nil {
+		t.Fatalf(`Expected the large file moved to the Error folder: %v`, err)
+	}
+}
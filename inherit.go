@@ -0,0 +1,166 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var ErrInheritCycle = errors.New("configuration inherits itself")
+
+// inheritsKey is the key of the document naming the base configuration
+const inheritsKey = "Inherits"
+
+// resolveInherits merges the chain of base configurations named by Inherits under the document, so the
+// values of the document override the values of its bases. It returns the files of the chain from the
+// base to the document, the paths of the values inherited from the bases that are not in the document,
+// and the file that set each value.
+func resolveInherits(doc map[string]any, source string, o *options, lim Limits) (map[string]any, []string, []companionValue, map[string]string, error) {
+	chain := []string{source}
+	layers := []map[string]any{doc}
+	for cur, d := source, doc; ; {
+		k, ok := lookupKey(d, inheritsKey)
+		if !ok {
+			break
+		}
+		base, _ := d[k].(string)
+		if base == "" {
+			break
+		}
+		base = resolveBase(cur, base)
+		for _, s := range chain {
+			if s == base {
+				return nil, nil, nil, nil, fmt.Errorf("%w: %s", ErrInheritCycle, strings.Join(append(chain, base), " -> "))
+			}
+		}
+		bd, err := readLayer(context.Background(), base, o, lim)
+		if err != nil {
+			return nil, nil, nil, nil, fmt.Errorf("inherits %s: %w", base, err)
+		}
+		chain = append(chain, base)
+		layers = append(layers, bd)
+		cur, d = base, bd
+	}
+	if len(layers) == 1 {
+		return doc, nil, nil, nil, nil
+	}
+
+	// bases first
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+		layers[i], layers[j] = layers[j], layers[i]
+	}
+	origins := make(map[string]string)
+	merged := make(map[string]any)
+	for i, ly := range layers {
+		if i < len(layers)-1 {
+			// the Inherits of the bases are not inherited
+			if k, ok := lookupKey(ly, inheritsKey); ok {
+				delete(ly, k)
+			}
+		}
+		walkLeaves(ly, "", func(path string) {
+			origins[strings.ToLower(path)] = chain[i]
+		})
+		if i == len(layers)-1 {
+			break
+		}
+		mergeDocuments(merged, ly)
+	}
+	inherited := make([]companionValue, 0)
+	for _, v := range companionOriginals(doc, merged) {
+		if v.absent {
+			inherited = append(inherited, v)
+		}
+	}
+	mergeDocuments(merged, doc)
+	return merged, chain, inherited, origins, nil
+}
+
+// resolveBase resolves the base configuration relative to the directory or URL of the configuration
+func resolveBase(source, base string) string {
+	if strings.HasPrefix(source, `http://`) || strings.HasPrefix(source, `https://`) {
+		if su, err := url.Parse(source); err == nil {
+			if bu, err := url.Parse(base); err == nil {
+				return su.ResolveReference(bu).String()
+			}
+		}
+		return base
+	}
+	if strings.HasPrefix(base, `http://`) || strings.HasPrefix(base, `https://`) || filepath.IsAbs(base) {
+		return base
+	}
+	return filepath.Join(filepath.Dir(source), base)
+}
+
+// walkLeaves calls the function with the path of every value of the document that is not an object
+// or an entry of a section, with the entries selected by their identifying key
+func walkLeaves(v any, path string, fn func(path string)) {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkLeaves(t[k], joinPath(path, k), fn)
+		}
+	case []any:
+		key := entryKey(t, nil)
+		if key == "" {
+			fn(path)
+			return
+		}
+		for _, e := range t {
+			walkLeaves(e, path+"["+fieldValue(e.(map[string]any), key).(string)+"]", fn)
+		}
+	default:
+		fn(path)
+	}
+}
+
+// loadedInherited sets the values of the inherited paths as loaded, after the defaults
+func loadedInherited(doc map[string]any, inherited []companionValue) {
+	for i, v := range inherited {
+		inherited[i].value, _ = getPath(doc, v.path)
+	}
+}
+
+// restoreInherited removes the values inherited from the base configurations from a document
+// when they are not changed, so only the overrides of the configuration are saved
+func restoreInherited(doc map[string]any, inherited []companionValue) {
+	for _, v := range inherited {
+		if cur, err := getPath(doc, v.path); err == nil && reflect.DeepEqual(cur, v.value) {
+			deletePath(doc, v.path)
+		}
+	}
+}
+
+// Inheritance returns the files of the chain of base configurations from the organization-wide
+// base to this configuration. It is empty when the configuration inherits nothing.
+func (c *Configuration) Inheritance() []string {
+	return append([]string(nil), c.chain...)
+}
+
+// InheritedFrom returns the file of the inheritance chain that set the value at the path like
+// Databases[DEFAULT].Schema or HostPort. It is empty when the configuration inherits nothing
+// or no file set the value, like a defaulted value.
+func (c *Configuration) InheritedFrom(path string) string {
+	p := strings.ToLower(path)
+	for {
+		if f, ok := c.origins[p]; ok {
+			return f
+		}
+		i := strings.LastIndexAny(p, ".[")
+		if i <= 0 {
+			return ""
+		}
+		p = p[:i]
+	}
+}
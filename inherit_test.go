@@ -0,0 +1,87 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInherits(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, doc string) string {
+		fn := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(fn), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return fn
+	}
+	org := write("org.json", `{
+		"CookieDomain": "example.com",
+		"HostPort": 8000,
+		"Databases": [{"ID": "DEFAULT", "ConnectionString": "sqlserver://db.example.com", "Schema": "dbo"}]
+	}`)
+	team := write("teams/team.json", `{"Inherits": "../org.json", "ApplicationName": "Team", "HostPort": 8100}`)
+	fn := write("teams/billing/config.json", `{
+		"Inherits": "../team.json",
+		"ApplicationID": "billing",
+		"Databases": [{"ID": "DEFAULT", "Schema": "billing"}]
+	}`)
+
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := config.GetDatabaseInfo("DEFAULT")
+	if db.ConnectionString != "sqlserver://db.example.com" || db.Schema != "billing" {
+		t.Fatalf(`Unexpected database %+v`, db)
+	}
+	if *config.HostPort != 8100 || *config.ApplicationName != "Team" || *config.CookieDomain != "example.com" {
+		t.Fatalf(`Unexpected inherited values %d %s %s`, *config.HostPort, *config.ApplicationName, *config.CookieDomain)
+	}
+	if chain := config.Inheritance(); len(chain) != 3 || chain[0] != org || chain[1] != team || chain[2] != fn {
+		t.Fatalf(`Unexpected inheritance %v`, chain)
+	}
+	for path, want := range map[string]string{
+		"HostPort":                            team,
+		"cookiedomain":                        org,
+		"Databases[DEFAULT].ConnectionString": org,
+		"Databases[DEFAULT].Schema":           fn,
+		"JWTSecret":                           "",
+	} {
+		if got := config.InheritedFrom(path); got != want {
+			t.Errorf(`Expected %s from %q, got %q`, path, want, got)
+		}
+	}
+
+	// only the overrides are saved
+	port := 9000
+	config.HostPort = &port
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"example.com", "Team"} {
+		if strings.Contains(string(b), s) {
+			t.Fatalf(`Expected the inherited %s not to be saved in %s`, s, b)
+		}
+	}
+	if config, err = Load(fn); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 9000 || *config.CookieDomain != "example.com" || config.GetDatabaseInfo("DEFAULT").Schema != "billing" {
+		t.Fatalf(`Unexpected configuration after saving %+v`, config)
+	}
+
+	write("org.json", `{"Inherits": "teams/billing/config.json"}`)
+	if _, err = Load(fn); !errors.Is(err, ErrInheritCycle) {
+		t.Fatalf(`Expected ErrInheritCycle, got %v`, err)
+	}
+}
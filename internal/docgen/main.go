@@ -0,0 +1,119 @@
+// Command docgen extracts the comments of the struct fields of the package
+// into a map used by the configuration reference generator.
+//
+// Usage:
+//
+//	go run ./internal/docgen <output>
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: docgen <output>")
+		os.Exit(2)
+	}
+	out := os.Args[1]
+	docs, err := extract(filepath.Dir(out), filepath.Base(out))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	b, err := generate(docs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err = os.WriteFile(out, b, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// extract collects the comments of the struct fields by type and field name
+func extract(dir, skip string) (map[string]map[string]string, error) {
+	fns, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	docs := make(map[string]map[string]string)
+	fset := token.NewFileSet()
+	for _, fn := range fns {
+		if strings.HasSuffix(fn, "_test.go") || filepath.Base(fn) == skip {
+			continue
+		}
+		f, err := parser.ParseFile(fset, fn, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok || !ts.Name.IsExported() {
+				return true
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			fields := make(map[string]string)
+			for _, fld := range st.Fields.List {
+				text := ""
+				switch {
+				case fld.Comment != nil:
+					text = fld.Comment.Text()
+				case fld.Doc != nil:
+					text = fld.Doc.Text()
+				}
+				text = strings.Join(strings.Fields(text), " ")
+				for _, name := range fld.Names {
+					if name.IsExported() && text != "" {
+						fields[name.Name] = text
+					}
+				}
+			}
+			if len(fields) > 0 {
+				docs[ts.Name.Name] = fields
+			}
+			return true
+		})
+	}
+	return docs, nil
+}
+
+// generate writes the Go source of the field documentation map
+func generate(docs map[string]map[string]string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString("// Code generated by internal/docgen. DO NOT EDIT.\n\npackage cfg\n\n")
+	buf.WriteString("// fieldDocs are the comments of the struct fields by type and field name\n")
+	buf.WriteString("var fieldDocs = map[string]map[string]string{\n")
+	types := make([]string, 0, len(docs))
+	for t := range docs {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Fprintf(buf, "%q: {\n", t)
+		names := make([]string, 0, len(docs[t]))
+		for n := range docs[t] {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(buf, "%q: %q,\n", n, docs[t][n])
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
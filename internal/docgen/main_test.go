@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	docs, err := extract("../..", "docs_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := generate(docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../../docs_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal(`docs_gen.go is out of date, run go generate`)
+	}
+}
@@ -0,0 +1,324 @@
+package cfg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	// EnvVar - an environment variable consumed by a placeholder
+	EnvVar struct {
+		Name string
+		Set  bool
+	}
+
+	// Interpolation - a field where placeholders were replaced on load
+	Interpolation struct {
+		Path string   // Path of the field like Databases[0].ConnectionString
+		Raw  string   // The value as written
+		Vars []EnvVar // Environment variables consumed
+	}
+
+	// envMemo - values interpolated on access, cached by the value as written until the
+	// configuration is reloaded or RefreshEnv is called. Safe for concurrent use, like the lookups of the
+	// configuration holding it, which are locked out while a reload or RefreshEnv swaps the configuration.
+	// The values interpolated on load are kept as they are.
+	envMemo struct {
+		sync.RWMutex
+		lookup func(string) (string, bool)
+		values map[string]string
+	}
+)
+
+// envPattern matches ${NAME} placeholders and the placeholders of the resolvers like ${exec:command args}
+// and ${file:path}, with optional pipelines like ${NAME|base64dec|trim}
+var envPattern = regexp.MustCompile(`\$\{(?:([A-Za-z_][A-Za-z0-9_]*)|(exec|file):([^|}]*))((?:\|[^|}]*)*)\}`)
+
+// resolver resolves the argument of the placeholders of a scheme like ${exec:op read op://app/db/password}
+type resolver func(arg string) (string, error)
+
+var ErrPlaceholder = errors.New("invalid placeholder")
+
+// pipeFuncs are the functions of the placeholder pipelines. Arguments follow a colon like default:8080.
+var pipeFuncs = map[string]func(v, arg string) (string, error){
+	"base64dec": func(v, _ string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			b, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(v, "="))
+		}
+		return string(b), err
+	},
+	"base64enc": func(v, _ string) (string, error) { return base64.StdEncoding.EncodeToString([]byte(v)), nil },
+	"upper":     func(v, _ string) (string, error) { return strings.ToUpper(v), nil },
+	"lower":     func(v, _ string) (string, error) { return strings.ToLower(v), nil },
+	"trim":      func(v, _ string) (string, error) { return strings.TrimSpace(v), nil },
+	"default": func(v, arg string) (string, error) {
+		if v == "" {
+			return arg, nil
+		}
+		return v, nil
+	},
+}
+
+// interpolate replaces ${NAME} placeholders with the value of the environment variable.
+// Unset variables are replaced with an empty string.
+func interpolate(value string) string {
+	return interpolateEnv(value, os.LookupEnv)
+}
+
+// interpolateEnv replaces ${NAME} placeholders with the value found by the lookup.
+// Placeholders with failing pipelines and of the resolvers are replaced with an empty string.
+func interpolateEnv(value string, lookup func(string) (string, bool)) string {
+	v, _ := interpolateValue(value, lookup, nil)
+	return v
+}
+
+// interpolateValue replaces ${NAME} placeholders with the value found by the lookup, and the placeholders
+// of a scheme with the value of its resolver, passed through their pipeline
+func interpolateValue(value string, lookup func(string) (string, bool), resolvers map[string]resolver) (string, error) {
+	var ferr error
+	v := envPattern.ReplaceAllStringFunc(value, func(m string) string {
+		sm := envPattern.FindStringSubmatch(m)
+		v, err := resolvePlaceholder(sm, lookup, resolvers)
+		if err == nil {
+			v, err = pipeValue(v, sm[4])
+		}
+		if err != nil && ferr == nil {
+			ferr = wrapError(ErrPlaceholder, fmt.Errorf("%s: %w", m, err))
+		}
+		return v
+	})
+	return v, ferr
+}
+
+// resolvePlaceholder returns the value of the submatches of a placeholder from the lookup or the resolver of its scheme
+func resolvePlaceholder(sm []string, lookup func(string) (string, bool), resolvers map[string]resolver) (string, error) {
+	if sm[2] == "" {
+		v, _ := lookup(sm[1])
+		return v, nil
+	}
+	rs, ok := resolvers[sm[2]]
+	if !ok {
+		return "", fmt.Errorf("%s placeholders are not enabled", sm[2])
+	}
+	return rs(strings.TrimSpace(sm[3]))
+}
+
+// pipeValue passes a value through a pipeline like |base64dec|trim
+func pipeValue(v, pipeline string) (string, error) {
+	if pipeline == "" {
+		return v, nil
+	}
+	for _, f := range strings.Split(pipeline[1:], "|") {
+		name, arg, _ := strings.Cut(f, ":")
+		fn, ok := pipeFuncs[strings.TrimSpace(name)]
+		if !ok {
+			return "", fmt.Errorf("unknown function %q", name)
+		}
+		var err error
+		if v, err = fn(v, arg); err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return v, nil
+}
+
+// interpolateDocument replaces the placeholders of every string value of a document
+// with the value found by the lookup or the resolvers and returns the interpolated fields
+func interpolateDocument(doc map[string]any, lookup func(string) (string, bool), resolvers map[string]resolver) ([]Interpolation, error) {
+	var ferr error
+	ips := make([]Interpolation, 0)
+	var walk func(v any, path string) any
+	walk = func(v any, path string) any {
+		switch t := v.(type) {
+		case map[string]any:
+			for k, mv := range t {
+				t[k] = walk(mv, joinPath(path, k))
+			}
+		case []any:
+			for i, sv := range t {
+				t[i] = walk(sv, path+"["+strconv.Itoa(i)+"]")
+			}
+		case string:
+			ms := envPattern.FindAllStringSubmatch(t, -1)
+			if len(ms) == 0 {
+				return t
+			}
+			ip := Interpolation{Path: path, Raw: t}
+			for _, m := range ms {
+				if m[1] != "" {
+					_, set := lookup(m[1])
+					ip.Vars = append(ip.Vars, EnvVar{Name: m[1], Set: set})
+				}
+			}
+			ips = append(ips, ip)
+			v, err := interpolateValue(t, lookup, resolvers)
+			if err != nil && ferr == nil {
+				ferr = fmt.Errorf("%s: %w", path, err)
+			}
+			return v
+		}
+		return v
+	}
+	walk(doc, "")
+	if ferr != nil {
+		return nil, ferr
+	}
+	sort.Slice(ips, func(i, j int) bool {
+		return ips[i].Path < ips[j].Path
+	})
+	return ips, nil
+}
+
+// Interpolations returns the fields where ${NAME} placeholders were replaced on load
+func (c *Configuration) Interpolations() []Interpolation {
+	return append([]Interpolation(nil), c.interpolations...)
+}
+
+// env returns the lookup of the environment variables of the configuration
+func (c *Configuration) env() func(string) (string, bool) {
+	if c.lookupEnv == nil {
+		return os.LookupEnv
+	}
+	return c.lookupEnv
+}
+
+// newEnvMemo returns an empty cache of the values interpolated with the lookup
+func newEnvMemo(lookup func(string) (string, bool)) *envMemo {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return &envMemo{lookup: lookup, values: make(map[string]string)}
+}
+
+// resolve returns the value with its placeholders replaced, interpolating it once.
+// A nil cache interpolates from the environment on every call.
+func (m *envMemo) resolve(value string) string {
+	if !strings.Contains(value, "${") {
+		return value
+	}
+	if m == nil {
+		return interpolate(value)
+	}
+	m.RLock()
+	v, ok := m.values[value]
+	m.RUnlock()
+	if ok {
+		return v
+	}
+	v = interpolateEnv(value, m.lookup)
+	m.Lock()
+	m.values[value] = v
+	m.Unlock()
+	return v
+}
+
+// keep caches the values produced on load as they are, so the values already interpolated on load
+// are not interpolated again on access
+func (m *envMemo) keep(origs []original) {
+	if m == nil {
+		return
+	}
+	m.Lock()
+	defer m.Unlock()
+	for _, o := range origs {
+		if strings.Contains(o.value, "${") {
+			m.values[o.value] = o.value
+		}
+	}
+}
+
+// reset drops the cached values so they are interpolated again on access
+func (m *envMemo) reset() {
+	if m == nil {
+		return
+	}
+	m.Lock()
+	m.values = make(map[string]string)
+	m.Unlock()
+}
+
+// RefreshEnv drops the values interpolated on access, like the JWT keys, the webhook secrets and the
+// proxy credentials, and interpolates the fields of the placeholders again, so credentials rotated in the
+// environment or in the files of ${file:...} placeholders are used without a reload.
+// Fields changed since load keep their value.
+func (c *Configuration) RefreshEnv() error {
+	// the refresh works on a copy swapped in at the end, so the lookups can run meanwhile
+	nc := *c.view()
+	if err := nc.writable(); err != nil {
+		return err
+	}
+	nc.memo.reset()
+	nc.memo.keep(nc.originals)
+	if len(nc.interpolations) == 0 {
+		return nil
+	}
+	doc, err := configDocument(&nc)
+	if err != nil {
+		return err
+	}
+	nc.originals = append([]original(nil), nc.originals...)
+	lookup, rs := nc.env(), nc.resolvers()
+	changed := 0
+	for _, ip := range nc.interpolations {
+		o := nc.interpolated(ip)
+		if o == nil {
+			continue
+		}
+		v, err := interpolateValue(ip.Raw, lookup, rs)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ip.Path, err)
+		}
+		if v == o.value {
+			continue
+		}
+		// values changed since load or replaced by the templates and the secrets are kept
+		if cur, err := getPath(doc, ip.Path); err != nil || cur != o.value {
+			continue
+		}
+		if err = setPath(doc, ip.Path, v); err != nil {
+			return err
+		}
+		o.value = v
+		changed++
+	}
+	if changed == 0 {
+		return nil
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	dc := Configuration{}
+	if err = json.Unmarshal(b, &dc); err != nil {
+		return wrapError(ErrDecode, err)
+	}
+	dc.FileName = nc.FileName
+	copyExported(&nc, &dc)
+	nc.memo.keep(nc.originals)
+	attachState(&nc)
+	if err = nc.computeFlags(); err != nil {
+		return wrapError(ErrDecode, err)
+	}
+	c.swap(&nc)
+	nc.log().Debug("environment refreshed", "source", nc.FileName, "fields", changed)
+	return nil
+}
+
+// interpolated returns the value as written of an interpolated field, saved instead of its effective value
+func (c *Configuration) interpolated(ip Interpolation) *original {
+	for i := len(c.originals) - 1; i >= 0; i-- {
+		if o := &c.originals[i]; o.path == ip.Path && o.raw == ip.Raw {
+			return o
+		}
+	}
+	return nil
+}
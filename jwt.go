@@ -0,0 +1,118 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+)
+
+type (
+	// JWTSignerConfig - resolved setting to sign tokens
+	JWTSignerConfig struct {
+		Algorithm  string
+		KeyID      string
+		Key        []byte
+		Issuer     string
+		Audience   []string
+		AccessTTL  time.Duration
+		RefreshTTL time.Duration
+	}
+
+	// JWTVerifierConfig - resolved setting to verify tokens
+	JWTVerifierConfig struct {
+		Algorithm string
+		Keys      map[string][]byte // Keys by key id. The current key is also stored with an empty id
+		Issuer    string
+		Audience  []string
+	}
+)
+
+var ErrJWTNoKey = errors.New("jwt key is not set")
+
+// jwtInfo returns the JWT setting, falling back to the deprecated JWTSecret
+func (c *Configuration) jwtInfo() (*JWTInfo, error) {
+	if c.JWT != nil {
+		c.record(`JWT`, "")
+		return c.JWT, nil
+	}
+	if c.JWTSecret == nil || *c.JWTSecret == "" {
+		return nil, ErrJWTNoKey
+	}
+	c.record(`JWTSecret`, "")
+	return &JWTInfo{
+		Algorithm: `HS256`,
+		Key:       *c.JWTSecret,
+	}, nil
+}
+
+// JWTSigner builds the setting to sign tokens
+func (c *Configuration) JWTSigner() (*JWTSignerConfig, error) {
+	ji, err := c.jwtInfo()
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.readJWTKey(ji.Key, ji.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTSignerConfig{
+		Algorithm:  ji.Algorithm,
+		KeyID:      ji.KeyID,
+		Key:        key,
+		Issuer:     ji.Issuer,
+		Audience:   ji.Audience,
+		AccessTTL:  time.Duration(ji.AccessTTL) * time.Second,
+		RefreshTTL: time.Duration(ji.RefreshTTL) * time.Second,
+	}, nil
+}
+
+// JWTVerifier builds the setting to verify tokens, including the rotated keys
+func (c *Configuration) JWTVerifier() (*JWTVerifierConfig, error) {
+	ji, err := c.jwtInfo()
+	if err != nil {
+		return nil, err
+	}
+	key, err := c.readJWTKey(ji.Key, ji.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	vc := &JWTVerifierConfig{
+		Algorithm: ji.Algorithm,
+		Keys:      map[string][]byte{"": key},
+		Issuer:    ji.Issuer,
+		Audience:  ji.Audience,
+	}
+	if ji.KeyID != "" {
+		vc.Keys[ji.KeyID] = key
+	}
+	for _, k := range ji.Keys {
+		if k.ID == "" {
+			continue
+		}
+		kb, err := c.readJWTKey(k.Key, k.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		vc.Keys[k.ID] = kb
+	}
+	return vc, nil
+}
+
+// readJWTKey returns the key, or the contents of the key file when the key is not set
+func (c *Configuration) readJWTKey(key, keyFile string) ([]byte, error) {
+	if key = c.memo.resolve(key); key != "" {
+		return []byte(key), nil
+	}
+	if keyFile == "" {
+		return nil, ErrJWTNoKey
+	}
+	b, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(strings.TrimSpace(string(b))) == 0 {
+		return nil, ErrJWTNoKey
+	}
+	return b, nil
+}
@@ -0,0 +1,51 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJWTSecretFallback(t *testing.T) {
+	c := &Configuration{JWTSecret: new_string("legacy")}
+	sc, err := c.JWTSigner()
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	if sc.Algorithm != "HS256" || string(sc.Key) != "legacy" {
+		t.Fatalf(`Unexpected signer %+v`, sc)
+	}
+}
+
+func TestJWTRotation(t *testing.T) {
+	kf := filepath.Join(t.TempDir(), "old.key")
+	if err := os.WriteFile(kf, []byte("oldkey"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_KEY", "newkey")
+	c := &Configuration{
+		JWTSecret: new_string("legacy"),
+		JWT: &JWTInfo{
+			Algorithm: "HS512",
+			Key:       "${JWT_KEY}",
+			KeyID:     "2",
+			Keys:      []JWTKeyInfo{{ID: "1", KeyFile: kf}},
+			AccessTTL: 300,
+		},
+	}
+	sc, err := c.JWTSigner()
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	if string(sc.Key) != "newkey" || sc.KeyID != "2" || sc.AccessTTL != 5*time.Minute {
+		t.Fatalf(`Unexpected signer %+v`, sc)
+	}
+	vc, err := c.JWTVerifier()
+	if err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	if string(vc.Keys["2"]) != "newkey" || string(vc.Keys["1"]) != "oldkey" {
+		t.Fatalf(`Unexpected verifier keys %v`, vc.Keys)
+	}
+}
@@ -0,0 +1,71 @@
+package cfg
+
+import "strings"
+
+// KeyPolicy - policy comparing the keys and ids of lookups like Flag, GetFlag, GetDirectoryItem and GetDatabaseInfo
+type KeyPolicy int
+
+const (
+	KeyDefault   KeyPolicy = iota // Compares the flag keys like KeyNormalize and the other keys and ids like KeyFold. Default.
+	KeyNormalize                  // Ignores the case, underscores and dashes, like max_limit matching MaxLimit
+	KeyFold                       // Ignores the case
+	KeyStrict                     // Compares exactly
+)
+
+// WithKeyPolicy sets the policy comparing the keys and ids of the lookups.
+// On reload, the policy of the loaded configuration is used when this option is not set.
+func WithKeyPolicy(p KeyPolicy) Option {
+	return func(o *options) {
+		o.keyPolicy = &p
+	}
+}
+
+// normalize converts a key for comparison under the policy
+func (p KeyPolicy) normalize(key string) string {
+	switch p {
+	case KeyStrict:
+		return key
+	case KeyDefault, KeyFold:
+		return strings.ToLower(key)
+	}
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+}
+
+// normalizePattern converts a wildcard pattern for comparison under the policy, keeping the ranges of [] classes
+func (p KeyPolicy) normalizePattern(pattern string) string {
+	if p != KeyNormalize {
+		return p.normalize(pattern)
+	}
+	var sb strings.Builder
+	class := false
+	for _, r := range strings.ToLower(pattern) {
+		switch {
+		case r == '[':
+			class = true
+		case r == ']':
+			class = false
+		case !class && (r == '_' || r == '-'):
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// equal reports whether two keys are the same under the policy
+func (p KeyPolicy) equal(a, b string) bool {
+	return p.normalize(a) == p.normalize(b)
+}
+
+// sameKey reports whether two keys or ids are the same under the key policy of the configuration
+func (c *Configuration) sameKey(a, b string) bool {
+	return c.keyPolicy.equal(a, b)
+}
+
+// sameFlag reports whether two flag keys are the same, ignoring underscores and dashes under the default policy
+func (c *Configuration) sameFlag(a, b string) bool {
+	if c.keyPolicy == KeyDefault {
+		return KeyNormalize.equal(a, b)
+	}
+	return c.keyPolicy.equal(a, b)
+}
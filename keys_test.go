@@ -0,0 +1,116 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyPolicy(t *testing.T) {
+	tests := []struct {
+		policy KeyPolicy
+		a, b   string
+		want   bool
+	}{
+		{KeyDefault, "reporting-eu", "REPORTING-EU", true},
+		{KeyDefault, "reporting-eu", "REPORTING_EU", false},
+		{KeyNormalize, "max_limit", "MaxLimit", true},
+		{KeyNormalize, "reporting-eu", "REPORTING_EU", true},
+		{KeyFold, "max_limit", "MAX_LIMIT", true},
+		{KeyFold, "max_limit", "MaxLimit", false},
+		{KeyStrict, "MaxLimit", "MaxLimit", true},
+		{KeyStrict, "MaxLimit", "maxlimit", false},
+	}
+	for _, tt := range tests {
+		if got := tt.policy.equal(tt.a, tt.b); got != tt.want {
+			t.Fatalf(`Policy %d comparing %q and %q = %v, expected %v`, tt.policy, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestWithKeyPolicy(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"Databases": [{"ID": "Reporting_EU"}],
+		"Flags": [{"key": "max_limit", "value": "10"}],
+		"Directories": [{"GroupID": "paths", "Items": [{"key": "upload-dir", "value": "/tmp"}]}]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the keys and ids ignore the case, and the flags also underscores and dashes
+	if config.GetDatabaseInfo("reporting_eu") == nil || config.GetDatabaseInfo("reporting-eu") != nil ||
+		config.Flag("MaxLimit").Value == nil || config.GetDirectoryItem("PATHS", "UploadDir") != nil ||
+		config.GetDirectoryItem("PATHS", "UPLOAD-DIR") == nil {
+		t.Fatal(`Expected the default lookups to ignore the case`)
+	}
+
+	if config, err = Load(fn, WithKeyPolicy(KeyNormalize)); err != nil {
+		t.Fatal(err)
+	}
+	if config.GetDatabaseInfo("reporting-eu") == nil || config.Flag("MaxLimit").Value == nil ||
+		config.GetDirectoryItem("PATHS", "UploadDir") == nil {
+		t.Fatal(`Expected the lookups to match the normalized keys`)
+	}
+
+	if config, err = Load(fn, WithKeyPolicy(KeyStrict)); err != nil {
+		t.Fatal(err)
+	}
+	if config.GetDatabaseInfo("reporting_eu") != nil || config.Flag("MaxLimit").Value != nil {
+		t.Fatal(`Expected the strict lookups to fail`)
+	}
+	if config.GetDatabaseInfo("Reporting_EU") == nil || config.Flag("max_limit").Value == nil {
+		t.Fatal(`Expected the strict lookups to match the exact keys`)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if config.Flag("MAX_LIMIT").Value != nil {
+		t.Fatal(`Expected the key policy to be kept on reload`)
+	}
+}
+
+func TestKeyPolicyDuplicateIDs(t *testing.T) {
+	config := Configuration{Databases: &[]DatabaseInfo{
+		{ID: "orders-db", ConnectionString: "postgres://localhost/orders", StorageType: "SERVER"},
+		{ID: "ORDERS_DB", ConnectionString: "postgres://localhost/orders", StorageType: "SERVER"},
+	}}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	// the ids collide when the lookups normalize them
+	config.keyPolicy = KeyNormalize
+	if err := config.Validate(); !errors.Is(err, ErrDuplicateID) {
+		t.Fatalf(`Expected ErrDuplicateID, got %v`, err)
+	}
+	// the ids differing by case are distinct when compared exactly
+	config.Databases = &[]DatabaseInfo{
+		{ID: "A", ConnectionString: "postgres://localhost/a", StorageType: "SERVER"},
+		{ID: "a", ConnectionString: "postgres://localhost/a", StorageType: "SERVER"},
+	}
+	config.keyPolicy = KeyStrict
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKeyPolicyNotification(t *testing.T) {
+	config := Configuration{
+		keyPolicy:             KeyStrict,
+		DefaultNotificationID: new_string("DEFAULT"),
+		Notifications:         &[]NotificationInfo{{ID: "DEFAULT"}},
+	}
+	for _, id := range []string{"DEFAULT", ""} {
+		if nf := config.GetNotificationInfo(id); nf == nil || nf.ID != "DEFAULT" {
+			t.Fatalf(`Expected the notification for %q, got %+v`, id, nf)
+		}
+	}
+	if nf := config.GetNotificationInfo("default"); nf != nil {
+		t.Fatalf(`Expected no notification with another case, got %+v`, nf)
+	}
+}
@@ -0,0 +1,112 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Limits - limits enforced on loading a document, so a hostile or corrupted source can't exhaust memory
+type Limits struct {
+	MaxSize         int64 // Maximum size of the document in bytes. Default is 4 MiB
+	MaxStringLength int   // Maximum length of a key or a string value in bytes. Default is 64 KiB
+	MaxDepth        int   // Maximum nesting of objects and arrays. Default is 32
+}
+
+// DefaultLimits are the limits used when WithLimits is not set. Zero fields of the limits set
+// with WithLimits also take their value from DefaultLimits while negative fields disable the limit.
+var DefaultLimits = Limits{
+	MaxSize:         4 << 20,
+	MaxStringLength: 64 << 10,
+	MaxDepth:        32,
+}
+
+var ErrLimitExceeded = errors.New("configuration exceeds a limit")
+
+// utf8BOM is the byte order mark some editors write at the start of the file
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// WithLimits sets the size, string length and nesting limits enforced on loading the document.
+// On reload, the limits of the loaded configuration are used when this option is not set.
+func WithLimits(l Limits) Option {
+	return func(o *options) {
+		o.limits = &l
+	}
+}
+
+// withDefaults returns the limits with the zero fields set from DefaultLimits
+func (l *Limits) withDefaults() Limits {
+	if l == nil {
+		return DefaultLimits
+	}
+	r := *l
+	if r.MaxSize == 0 {
+		r.MaxSize = DefaultLimits.MaxSize
+	}
+	if r.MaxStringLength == 0 {
+		r.MaxStringLength = DefaultLimits.MaxStringLength
+	}
+	if r.MaxDepth == 0 {
+		r.MaxDepth = DefaultLimits.MaxDepth
+	}
+	return r
+}
+
+// readLimited reads up to the maximum size and fails if the reader has more
+func readLimited(r io.Reader, l Limits) ([]byte, error) {
+	if l.MaxSize < 0 {
+		return io.ReadAll(r)
+	}
+	b, err := io.ReadAll(io.LimitReader(r, l.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(b)) > l.MaxSize {
+		return nil, fmt.Errorf("%w: document is larger than %d bytes", ErrLimitExceeded, l.MaxSize)
+	}
+	return b, nil
+}
+
+// sanitizeJSON removes the byte order mark and checks the encoding, nesting and string lengths
+// of a JSON document by scanning its tokens, before any value is built
+func sanitizeJSON(b []byte, l Limits) ([]byte, error) {
+	b = bytes.TrimPrefix(b, utf8BOM)
+	if !utf8.Valid(b) {
+		return nil, errors.New("document is not valid UTF-8")
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				depth++
+				if l.MaxDepth >= 0 && depth > l.MaxDepth {
+					return nil, fmt.Errorf("%w: document is nested deeper than %d levels", ErrLimitExceeded, l.MaxDepth)
+				}
+			default:
+				depth--
+			}
+		case string:
+			if l.MaxStringLength >= 0 && len(t) > l.MaxStringLength {
+				return nil, fmt.Errorf("%w: string at offset %d is longer than %d bytes", ErrLimitExceeded, dec.InputOffset(), l.MaxStringLength)
+			}
+		case json.Number:
+			if l.MaxStringLength >= 0 && len(t) > l.MaxStringLength {
+				return nil, fmt.Errorf("%w: number at offset %d is longer than %d bytes", ErrLimitExceeded, dec.InputOffset(), l.MaxStringLength)
+			}
+		}
+	}
+}
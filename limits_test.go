@@ -0,0 +1,95 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadLimits(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, doc string) string {
+		fn := filepath.Join(dir, name)
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return fn
+	}
+
+	big := write("big.json", `{"ApplicationName": "`+strings.Repeat("a", 200)+`"}`)
+	if _, err := Load(big, WithLimits(Limits{MaxSize: 100})); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf(`Expected ErrLimitExceeded on size, got %v`, err)
+	}
+	if _, err := Load(big, WithLimits(Limits{MaxStringLength: 100})); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf(`Expected ErrLimitExceeded on string length, got %v`, err)
+	}
+	if _, err := Load(big, WithLimits(Limits{MaxSize: -1, MaxStringLength: -1})); err != nil {
+		t.Fatalf(`Expected disabled limits to load, got %v`, err)
+	}
+
+	deep := write("deep.json", `{"Flags": `+strings.Repeat("[", 40)+strings.Repeat("]", 40)+`}`)
+	if _, err := Load(deep); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf(`Expected ErrLimitExceeded on depth by default, got %v`, err)
+	}
+
+	bom := write("bom.json", "\xEF\xBB\xBF"+`{"HostPort": 8000}`)
+	config, err := Load(bom)
+	if err != nil || *config.HostPort != 8000 {
+		t.Fatalf(`Expected the byte order mark to be removed, got %v`, err)
+	}
+
+	invalid := write("invalid.json", "{\"ApplicationName\": \"\xff\"}")
+	if _, err = Load(invalid); !errors.Is(err, ErrDecode) {
+		t.Fatalf(`Expected ErrDecode on invalid UTF-8, got %v`, err)
+	}
+}
+
+func FuzzSanitizeJSON(f *testing.F) {
+	f.Add([]byte(`{"Databases": [{"ID": "DEFAULT", "ConnectionString": "x"}], "HostPort": 8000}`))
+	f.Add([]byte(`{"a": [[[[{"b": 1e400}]]]]}`))
+	f.Add([]byte("\xEF\xBB\xBF{}"))
+	lim := Limits{MaxSize: 1 << 10, MaxStringLength: 64, MaxDepth: 4}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		sb, err := sanitizeJSON(b, lim)
+		if err != nil {
+			return
+		}
+		doc, err := decodeDocument(sb, FormatJSON)
+		if err != nil {
+			return
+		}
+		var depth func(v any) int
+		depth = func(v any) int {
+			d := 0
+			switch vv := v.(type) {
+			case map[string]any:
+				for k, mv := range vv {
+					if len(k) > lim.MaxStringLength {
+						t.Fatalf(`Key longer than the limit: %q`, k)
+					}
+					if n := depth(mv); n > d {
+						d = n
+					}
+				}
+				return d + 1
+			case []any:
+				for _, sv := range vv {
+					if n := depth(sv); n > d {
+						d = n
+					}
+				}
+				return d + 1
+			case string:
+				if len(vv) > lim.MaxStringLength {
+					t.Fatalf(`String longer than the limit: %q`, vv)
+				}
+			}
+			return 0
+		}
+		if n := depth(doc); n > lim.MaxDepth {
+			t.Fatalf(`Document nested %d levels`, n)
+		}
+	})
+}
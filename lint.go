@@ -0,0 +1,186 @@
+package cfg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Severity - severity of a lint issue
+type Severity int
+
+const (
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+)
+
+// Lint rules
+const (
+	RuleDeprecated      = "deprecated"
+	RuleDuplicateID     = "duplicate-id"
+	RulePlaintextSecret = "plaintext-secret"
+	RuleEmptyConnection = "empty-connection-string"
+	RuleUnparseableFlag = "unparseable-flag"
+)
+
+// DefaultSeverities are the severities of the lint rules when not configured
+var DefaultSeverities = map[string]Severity{
+	RuleDeprecated:      SeverityWarning,
+	RuleDuplicateID:     SeverityError,
+	RulePlaintextSecret: SeverityWarning,
+	RuleEmptyConnection: SeverityError,
+	RuleUnparseableFlag: SeverityInfo,
+}
+
+// LintIssue - an unused, deprecated or suspicious setting
+type LintIssue struct {
+	Rule     string
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+var (
+	passwordInDSN = regexp.MustCompile(`(?i)(password|pwd)\s*=|://[^/@:]+:[^/@]+@`)
+	looksNumeric  = regexp.MustCompile(`^[-+.]?[0-9]`)
+)
+
+// String returns the name of the severity
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "off"
+}
+
+// ParseSeverity parses the name of a severity
+func ParseSeverity(name string) (Severity, error) {
+	for _, s := range []Severity{SeverityOff, SeverityInfo, SeverityWarning, SeverityError} {
+		if strings.EqualFold(name, s.String()) {
+			return s, nil
+		}
+	}
+	return SeverityOff, fmt.Errorf("%w: severity %q", ErrInvalidEnum, name)
+}
+
+// String formats the issue
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s: %s: %s [%s]", i.Severity, i.Path, i.Message, i.Rule)
+}
+
+// LintDocument checks a configuration document as written, before defaults are applied.
+// Severities override the default severity of the rules. Rules set to SeverityOff are skipped.
+func LintDocument(b []byte, f Format, severities map[string]Severity) ([]LintIssue, error) {
+	doc, err := decodeDocument(b, f)
+	if err != nil {
+		return nil, err
+	}
+	jb, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	c := &Configuration{}
+	if err = json.Unmarshal(jb, c); err != nil {
+		return nil, err
+	}
+	issues := make([]LintIssue, 0)
+	add := func(rule, path, msg string) {
+		sev, ok := severities[rule]
+		if !ok {
+			sev = DefaultSeverities[rule]
+		}
+		if sev == SeverityOff {
+			return
+		}
+		issues = append(issues, LintIssue{Rule: rule, Severity: sev, Path: path, Message: msg})
+	}
+
+	if _, ok := lookupKey(doc, "JWTSecret"); ok {
+		add(RuleDeprecated, "JWTSecret", "JWTSecret is deprecated, use the JWT section")
+	}
+
+	v := &ValidationError{}
+	c.checkSchema(v)
+	for _, p := range v.Problems {
+		if errors.Is(p.Err, ErrDuplicateID) {
+			add(RuleDuplicateID, p.Path, p.Err.Error())
+		}
+	}
+
+	walkDocument(doc, "", "", func(path, pattern string, val any) {
+		s, ok := val.(string)
+		if !ok || s == "" || !isSensitivePath(pattern) || IsEncrypted(s) || strings.Contains(s, "${") {
+			return
+		}
+		if strings.EqualFold(pattern, "Databases[].ConnectionString") && !passwordInDSN.MatchString(s) {
+			return
+		}
+		add(RulePlaintextSecret, path, "secret is in plain text, use an ${ENV} placeholder or encrypt it")
+	})
+
+	if c.Databases != nil {
+		for i, db := range *c.Databases {
+			if strings.TrimSpace(db.ConnectionString) == "" {
+				add(RuleEmptyConnection, fmt.Sprintf("Databases[%d].ConnectionString", i), fmt.Sprintf("database %s has no connection string", db.ID))
+			}
+		}
+	}
+
+	if c.Flags != nil {
+		for i, fl := range *c.Flags {
+			path := fmt.Sprintf("Flags[%d]", i)
+			if fl.Value == nil {
+				add(RuleUnparseableFlag, path, fmt.Sprintf("flag %s has no value", fl.Key))
+				continue
+			}
+			fv := strings.TrimSpace(*fl.Value)
+			if !looksNumeric.MatchString(fv) {
+				continue
+			}
+			if _, err := strconv.ParseInt(fv, 0, 64); err == nil {
+				continue
+			}
+			if _, err := strconv.ParseFloat(fv, 64); err == nil {
+				continue
+			}
+			add(RuleUnparseableFlag, path, fmt.Sprintf("flag %s looks numeric but %q does not parse as a number", fl.Key, fv))
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Severity > issues[j].Severity
+	})
+	return issues, nil
+}
+
+// walkDocument calls the function on every scalar value of a document with its path and pattern
+func walkDocument(v any, path, pattern string, fn func(path, pattern string, v any)) {
+	switch t := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			walkDocument(t[k], joinPath(path, k), joinPath(pattern, k), fn)
+		}
+	case []any:
+		for i, sv := range t {
+			walkDocument(sv, path+"["+strconv.Itoa(i)+"]", pattern+"[]", fn)
+		}
+	default:
+		fn(path, pattern, v)
+	}
+}
@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"testing"
+)
+
+func TestLintDocument(t *testing.T) {
+	doc := []byte(`{
+		"JWTSecret": "secret",
+		"APIEndpoints": [
+			{"ID": "a", "Address": "http://a", "Token": "plain"},
+			{"ID": "A", "Address": "http://b", "Token": "${TOKEN}"}
+		],
+		"Databases": [
+			{"ID": "DEFAULT", "ConnectionString": "sqlserver://sa:pass@db"},
+			{"ID": "REPORTS", "ConnectionString": "sqlserver://db?user=${DB_USER}"},
+			{"ID": "EMPTY"}
+		],
+		"Flags": [
+			{"key": "MaxLimit", "value": "10O00"},
+			{"key": "Enabled", "value": "yes"},
+			{"key": "Empty"}
+		]
+	}`)
+	issues, err := LintDocument(doc, FormatJSON, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, i := range issues {
+		found[i.Path+" "+i.Rule] = true
+	}
+	for _, want := range []string{
+		"JWTSecret " + RuleDeprecated,
+		"APIEndpoints[1].ID " + RuleDuplicateID,
+		"APIEndpoints[0].Token " + RulePlaintextSecret,
+		"Databases[0].ConnectionString " + RulePlaintextSecret,
+		"Databases[2].ConnectionString " + RuleEmptyConnection,
+		"Flags[0] " + RuleUnparseableFlag,
+		"Flags[2] " + RuleUnparseableFlag,
+	} {
+		if !found[want] {
+			t.Errorf(`Expected issue %s`, want)
+		}
+	}
+	for _, i := range issues {
+		switch i.Path {
+		case "APIEndpoints[1].Token", "Databases[1].ConnectionString", "Flags[1]":
+			t.Errorf(`Unexpected issue %s`, i)
+		}
+	}
+	if issues[0].Severity != SeverityError {
+		t.Errorf(`Expected errors first, got %v`, issues[0])
+	}
+
+	issues, err = LintDocument(doc, FormatJSON, map[string]Severity{RulePlaintextSecret: SeverityOff})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, i := range issues {
+		if i.Rule == RulePlaintextSecret {
+			t.Errorf(`Expected rule %s to be off`, RulePlaintextSecret)
+		}
+	}
+}
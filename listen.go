@@ -0,0 +1,64 @@
+package cfg
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// unixPrefix is the prefix of the unix socket listen addresses
+const unixPrefix = "unix:"
+
+// Addr returns the network and the address the application binds, for net.Listen.
+// The HostListenAddress is used with the HostPort when it has no port, and the
+// HostPort alone binds all interfaces. Unix sockets are written like unix:/tmp/app.sock.
+func (c *Configuration) Addr() (network, address string) {
+	network, address, _ = c.listenAddr()
+	return
+}
+
+// listenAddr reconciles the HostListenAddress and the HostPort
+func (c *Configuration) listenAddr() (network, address string, err error) {
+	la := ""
+	if c.HostListenAddress != nil {
+		la = strings.TrimSpace(*c.HostListenAddress)
+	}
+	port := ""
+	if c.HostPort != nil {
+		port = strconv.Itoa(*c.HostPort)
+	}
+	if strings.HasPrefix(la, unixPrefix) {
+		path := strings.TrimPrefix(la, unixPrefix)
+		if path == "" {
+			return "unix", "", fmt.Errorf("%w: %q has no socket path", ErrListenAddress, la)
+		}
+		return "unix", path, nil
+	}
+	if la == "" {
+		if port == "" {
+			return "tcp", "", nil
+		}
+		return "tcp", ":" + port, nil
+	}
+	host, p, serr := net.SplitHostPort(la)
+	if serr != nil {
+		// a host without a port like 0.0.0.0 or [::1]
+		if strings.Contains(la, ":") && !strings.HasPrefix(la, "[") {
+			return "tcp", la, fmt.Errorf("%w: %q", ErrListenAddress, la)
+		}
+		host = strings.TrimSuffix(strings.TrimPrefix(la, "["), "]")
+	}
+	switch {
+	case p == "" && port == "":
+		return "tcp", la, fmt.Errorf("%w: %q has no port and HostPort is not set", ErrListenAddress, la)
+	case p == "":
+		p = port
+	case port != "" && p != port:
+		return "tcp", la, fmt.Errorf("%w: port of %q conflicts with HostPort %s", ErrListenAddress, la, port)
+	}
+	if n, perr := strconv.Atoi(p); perr != nil || n < 0 || n > 65535 {
+		return "tcp", la, fmt.Errorf("%w: %q has an invalid port", ErrListenAddress, la)
+	}
+	return "tcp", net.JoinHostPort(host, p), nil
+}
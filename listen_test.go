@@ -0,0 +1,48 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddr(t *testing.T) {
+	port := func(p int) *int { return &p }
+	tests := []struct {
+		listen  *string
+		port    *int
+		network string
+		address string
+		err     bool
+	}{
+		{nil, port(8000), "tcp", ":8000", false},
+		{nil, nil, "tcp", "", false},
+		{new_string("0.0.0.0:8080"), nil, "tcp", "0.0.0.0:8080", false},
+		{new_string("127.0.0.1"), port(8000), "tcp", "127.0.0.1:8000", false},
+		{new_string("[::1]"), port(8000), "tcp", "[::1]:8000", false},
+		{new_string("0.0.0.0:8080"), port(8080), "tcp", "0.0.0.0:8080", false},
+		{new_string("unix:/tmp/app.sock"), port(8000), "unix", "/tmp/app.sock", false},
+		{new_string("0.0.0.0:8080"), port(8000), "tcp", "", true},
+		{new_string("127.0.0.1"), nil, "tcp", "", true},
+		{new_string("unix:"), nil, "unix", "", true},
+		{new_string("localhost:http"), nil, "tcp", "", true},
+	}
+	for _, tt := range tests {
+		config := Configuration{HostListenAddress: tt.listen, HostPort: tt.port}
+		network, address, err := config.listenAddr()
+		if tt.err {
+			if !errors.Is(err, ErrListenAddress) {
+				t.Fatalf(`Expected ErrListenAddress for %v, got %v`, *tt.listen, err)
+			}
+			if verr := config.Validate(); !errors.Is(verr, ErrListenAddress) {
+				t.Fatalf(`Expected the validation to fail for %v, got %v`, *tt.listen, verr)
+			}
+			continue
+		}
+		if err != nil || network != tt.network || address != tt.address {
+			t.Fatalf(`Unexpected address %s %s, %v, expected %s %s`, network, address, err, tt.network, tt.address)
+		}
+		if n, a := config.Addr(); n != network || a != address {
+			t.Fatalf(`Unexpected Addr %s %s`, n, a)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package cfg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// Loader loads a configuration from layers of files, environment variables and overrides.
+	// Layers are applied in the order they are added so later layers take precedence. Objects are
+	// merged key by key, entries of sections are merged by their ID, Name, GroupID or Key,
+	// and other values are replaced.
+	Loader struct {
+		layers []loaderLayer
+		opts   []Option
+	}
+
+	// loaderLayer - a layer of the loader
+	loaderLayer struct {
+		file      string
+		env       string
+		overrides map[string]any
+	}
+)
+
+// NewLoader returns a loader with the load options
+func NewLoader(opts ...Option) *Loader {
+	return &Loader{opts: opts}
+}
+
+// File adds a local file or URL. The format is taken from the extension and defaults to JSON.
+// When signature verification is set, every file is verified with its detached signature.
+func (l *Loader) File(source string) *Loader {
+	l.layers = append(l.layers, loaderLayer{file: source})
+	return l
+}
+
+// Env adds the environment variables starting with the prefix, named as in LoadFromEnv.
+// The layer can not be verified, so loading fails with ErrInvalidSignature under a signature option.
+func (l *Loader) Env(prefix string) *Loader {
+	l.layers = append(l.layers, loaderLayer{env: prefix})
+	return l
+}
+
+// Overrides adds values by their dot path like Databases[DEFAULT].Schema or HostPort
+func (l *Loader) Overrides(o map[string]any) *Loader {
+	l.layers = append(l.layers, loaderLayer{overrides: o})
+	return l
+}
+
+// Load merges the layers and loads the configuration. Defaults and validation apply to the merged
+// document like Load. The configuration reloads from all layers and can not be saved.
+func (l *Loader) Load(ctx context.Context) (*Configuration, error) {
+	o := newOptions(l.opts...)
+	start := o.now()
+	config, err := l.load(ctx, o)
+	if config == nil {
+		(&Configuration{instrumentation: o.instrumentation, clock: o.clock}).observe(EventLoad, l.source(), start, err)
+		return nil, err
+	}
+	if o.audit {
+		config.audit = &accessAudit{counts: make(map[string]uint64)}
+	}
+	if err == nil && o.history != nil {
+		config.history = o.history
+		config.snapshot(EventLoad)
+	}
+	if err == nil {
+		config.touch(o.now())
+	}
+	config.observe(EventLoad, l.source(), start, err)
+	return config, err
+}
+
+// source describes the layers like base.json+override.json+env:APP_+overrides
+func (l *Loader) source() string {
+	parts := make([]string, 0, len(l.layers))
+	for _, ly := range l.layers {
+		switch {
+		case ly.file != "":
+			parts = append(parts, ly.file)
+		case ly.overrides != nil:
+			parts = append(parts, "overrides")
+		default:
+			parts = append(parts, envSource+ly.env)
+		}
+	}
+	return strings.Join(parts, "+")
+}
+
+// load merges the layers into a document and parses it
+func (l *Loader) load(ctx context.Context, o *options) (*Configuration, error) {
+	if len(l.layers) == 0 {
+		return nil, ErrNoDataFromSource
+	}
+	lim := o.limits.withDefaults()
+	doc := make(map[string]any)
+	layers := make(map[string]Provenance)
+	for _, ly := range l.layers {
+		var (
+			ld  map[string]any
+			pv  Provenance
+			err error
+		)
+		switch {
+		case ly.file != "":
+			ld, err = readLayer(ctx, ly.file, o, lim)
+			pv = Provenance{Layer: LayerFile, Source: ly.file}
+		case ly.overrides != nil:
+			if err = applyOverrides(doc, ly.overrides); err != nil {
+				return nil, err
+			}
+			for p := range ly.overrides {
+				recordLayer(layers, canonicalPath(doc, p), Provenance{Layer: LayerProgrammatic, Source: "overrides"})
+			}
+			continue
+		default:
+			if o.verifier != nil {
+				// the environment has no detached signature to verify the layer with
+				return nil, wrapError(ErrInvalidSignature, fmt.Errorf("layer %s%s can not be verified", envSource, ly.env))
+			}
+			var b []byte
+			if b, err = envDocument(ly.env, os.Environ()); err == nil {
+				ld, err = decodeDocument(b, FormatJSON)
+			}
+			pv = Provenance{Layer: LayerEnv, Source: envSource + ly.env}
+		}
+		if err != nil {
+			return nil, err
+		}
+		mergeDocuments(doc, ld)
+		recordLayers(layers, ld, pv)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	config := newConfiguration(o)
+	config.loader = l
+	config.layers = layers
+	return parse(config, l.source(), b, o)
+}
+
+// readLayer reads, verifies and decodes a file of the loader
+func readLayer(ctx context.Context, source string, o *options, lim Limits) (map[string]any, error) {
+	local := !(strings.HasPrefix(source, `http://`) || strings.HasPrefix(source, `https://`))
+	b, f, err := readDocument(ctx, source, local, o, lim)
+	if err != nil {
+		return nil, err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return nil, ErrNoDataFromSource
+	}
+	if o.verifier != nil {
+		if err = verifySource(ctx, source, local, b, o); err != nil {
+			return nil, err
+		}
+	}
+	if b, _, err = unsealDocument(b, o); err != nil {
+		return nil, err
+	}
+	if f == "" {
+		f = FormatOf(source)
+	}
+	if f == "" {
+		f = FormatJSON
+	}
+	if f == FormatJSON {
+		if b, err = sanitizeJSON(b, lim); err != nil {
+			if errors.Is(err, ErrLimitExceeded) {
+				return nil, err
+			}
+			return nil, wrapError(ErrDecode, err)
+		}
+	}
+	doc, err := decodeDocument(b, f)
+	if err != nil {
+		return nil, wrapError(ErrDecode, err)
+	}
+	normalizeKeys(doc, reflect.TypeOf(Configuration{}), "", nil)
+	return doc, nil
+}
+
+// applyOverrides sets the values by their dot path in the document
+func applyOverrides(doc map[string]any, o map[string]any) error {
+	paths := make([]string, 0, len(o))
+	for p := range o {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		b, err := json.Marshal(o[p])
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		var v any
+		if err = dec.Decode(&v); err != nil {
+			return err
+		}
+		if err = setPath(doc, p, normalizeValue(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeDocuments merges the source into the destination. Keys are matched case-insensitively.
+func mergeDocuments(dst, src map[string]any) {
+	for k, sv := range src {
+		dk := k
+		for ek := range dst {
+			if strings.EqualFold(ek, k) {
+				dk = ek
+				break
+			}
+		}
+		dst[dk] = mergeValue(dst[dk], sv)
+	}
+}
+
+// mergeValue merges objects and entries of sections, and replaces other values
+func mergeValue(dv, sv any) any {
+	switch s := sv.(type) {
+	case map[string]any:
+		if d, ok := dv.(map[string]any); ok {
+			mergeDocuments(d, s)
+			return d
+		}
+	case []any:
+		d, ok := dv.([]any)
+		if !ok {
+			return sv
+		}
+		key := entryKey(d, s)
+		if key == "" {
+			return sv
+		}
+		idx := make(map[string]int, len(d))
+		for i, e := range d {
+			idx[fieldValue(e.(map[string]any), key).(string)] = i
+		}
+		for _, e := range s {
+			id := fieldValue(e.(map[string]any), key).(string)
+			if i, ok := idx[id]; ok {
+				d[i] = mergeValue(d[i], e)
+				continue
+			}
+			d = append(d, e)
+		}
+		return d
+	}
+	return sv
+}
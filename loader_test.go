@@ -0,0 +1,69 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	override := filepath.Join(dir, "override.yaml")
+	err := os.WriteFile(base, []byte(`{
+		"HostPort": 8000,
+		"ApplicationID": "orders",
+		"Databases": [
+			{"ID": "DEFAULT", "ConnectionString": "sqlserver://base", "Schema": "dbo"},
+			{"ID": "REPORTS", "ConnectionString": "sqlserver://reports"}
+		]
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(override, []byte("hostport: 8080\ndatabases:\n  - id: DEFAULT\n    connectionstring: sqlserver://override\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_APPLICATIONID", "billing")
+
+	l := NewLoader().
+		File(base).
+		File(override).
+		Env("APP_").
+		Overrides(map[string]any{"Databases[DEFAULT].Schema": "sales", "Secure": true})
+	config, err := l.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8080 || *config.ApplicationID != "billing" || !*config.Secure {
+		t.Fatalf(`Unexpected fields %d %s %v`, *config.HostPort, *config.ApplicationID, *config.Secure)
+	}
+	db := config.GetDatabaseInfo("DEFAULT")
+	if db == nil || db.ConnectionString != "sqlserver://override" || db.Schema != "sales" {
+		t.Fatalf(`Unexpected database %+v`, db)
+	}
+	if db = config.GetDatabaseInfo("REPORTS"); db == nil || db.ConnectionString != "sqlserver://reports" {
+		t.Fatalf(`Expected the base entry to be kept, got %+v`, db)
+	}
+	if config.FileName != base+"+"+override+"+env:APP_+overrides" {
+		t.Fatalf(`Unexpected source %s`, config.FileName)
+	}
+
+	t.Setenv("APP_HOSTPORT", "9000")
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 9000 || config.GetDatabaseInfo("DEFAULT").Schema != "sales" {
+		t.Fatalf(`Expected the reload to merge all layers, got %d`, *config.HostPort)
+	}
+	if err = config.Save(); !errors.Is(err, ErrSaveNotLocalFile) {
+		t.Fatalf(`Expected ErrSaveNotLocalFile, got %v`, err)
+	}
+
+	if _, err = NewLoader().File(filepath.Join(dir, "missing.json")).Load(context.Background()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf(`Expected a missing file error, got %v`, err)
+	}
+}
@@ -0,0 +1,51 @@
+package cfg
+
+import (
+	"sync"
+)
+
+// Logger receives non-fatal conditions on load, save and reload.
+// The arguments are alternating keys and values. A *slog.Logger satisfies this interface.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+}
+
+// nopLogger discards everything
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+
+var (
+	loggerMu      sync.RWMutex
+	defaultLogger Logger = nopLogger{}
+)
+
+// SetLogger sets the logger of the package. Loads with the WithLogger option use their own logger.
+// Setting nil discards the logs.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = nopLogger{}
+	}
+	loggerMu.Lock()
+	defaultLogger = l
+	loggerMu.Unlock()
+}
+
+// packageLogger returns the logger of the package
+func packageLogger() Logger {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return defaultLogger
+}
+
+// log returns the logger of the configuration, or the logger of the package when not set
+func (c *Configuration) log() Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return packageLogger()
+}
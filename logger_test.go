@@ -0,0 +1,54 @@
+package cfg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testLogger records the logs
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) add(level, msg string, args ...any) {
+	l.lines = append(l.lines, strings.TrimSpace(level+" "+msg+" "+fmt.Sprintln(args...)))
+}
+
+func (l *testLogger) Debug(msg string, args ...any) { l.add("DEBUG", msg, args...) }
+func (l *testLogger) Info(msg string, args ...any)  { l.add("INFO", msg, args...) }
+func (l *testLogger) Warn(msg string, args ...any)  { l.add("WARN", msg, args...) }
+
+func TestLoadLogger(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(fn, []byte(`{"ApplicationName": "${UNSET_APP_NAME}", "Databases": [{"ID": "DEFAULT"}]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := &testLogger{}
+	config, err := Load(fn, WithLogger(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := strings.Join(l.lines, "\n")
+	for _, want := range []string{
+		"WARN environment variable is not set field ApplicationName variable UNSET_APP_NAME",
+		"WARN field defaulted to an insecure value field JWTSecret",
+		"INFO field defaulted field CookieDomain",
+		"INFO field defaulted field Databases[DEFAULT].ParameterPlaceholder",
+		"DEBUG configuration loaded",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in\n%s", want, out)
+		}
+	}
+	l.lines = nil
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(l.lines, "\n"), "DEBUG configuration reloaded") {
+		t.Errorf("Expected the reload to be logged with the same logger")
+	}
+}
@@ -0,0 +1,45 @@
+//go:build go1.21
+
+package cfg
+
+import "log/slog"
+
+// redactedValue returns the value as a slog group of its JSON fields with the secrets located at the pattern replaced
+func redactedValue(v any, pattern string) slog.Value {
+	return slog.AnyValue(redactEntry(v, pattern))
+}
+
+// RedactedLogValue logs the configuration with the secrets replaced, like slog.Any("config", c.RedactedLogValue()).
+// Unlike the entries, the configuration does not implement slog.LogValuer, which would be promoted to
+// the structs embedding it and hide their own fields.
+func (c *Configuration) RedactedLogValue() slog.Value { return redactedValue(c.view(), "") }
+
+// LogValue logs the endpoint with the token replaced
+func (ep EndpointInfo) LogValue() slog.Value { return redactedValue(ep, "APIEndpoints[]") }
+
+// LogValue logs the API key with the key and token replaced
+func (k APIKeyInfo) LogValue() slog.Value { return redactedValue(k, "APIKeys[]") }
+
+// LogValue logs the cache with the password replaced
+func (ch CacheInfo) LogValue() slog.Value { return redactedValue(ch, "Cache") }
+
+// LogValue logs the database with the connection string replaced
+func (db DatabaseInfo) LogValue() slog.Value { return redactedValue(db, "Databases[]") }
+
+// LogValue logs the domain with the authorized password replaced
+func (d DomainInfo) LogValue() slog.Value { return redactedValue(d, "Domains[]") }
+
+// LogValue logs the JSON Web Token setting with the keys replaced
+func (j JWTInfo) LogValue() slog.Value { return redactedValue(j, "JWT") }
+
+// LogValue logs the notification with the password replaced
+func (nf NotificationInfo) LogValue() slog.Value { return redactedValue(nf, "Notifications[]") }
+
+// LogValue logs the OAuth provider with the client secret replaced
+func (oa OAuthProviderInfo) LogValue() slog.Value { return redactedValue(oa, "OAuths[]") }
+
+// LogValue logs the proxy with the password replaced
+func (p ProxyInfo) LogValue() slog.Value { return redactedValue(p, "Proxy") }
+
+// LogValue logs the webhook with the secret replaced
+func (wh WebhookInfo) LogValue() slog.Value { return redactedValue(wh, "Webhooks[]") }
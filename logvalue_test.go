@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package cfg
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValueRedacted(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+	wh := WebhookInfo{ID: "orders", URL: "https://hooks.example.com", Secret: "s3cr3t"}
+	l.Info("webhook", "webhook", wh, "database", DatabaseInfo{ID: "DEFAULT", ConnectionString: "sqlserver://sa:s3cr3t@db"})
+	if s := buf.String(); strings.Contains(s, "s3cr3t") || !strings.Contains(s, `"URL":"https://hooks.example.com"`) {
+		t.Fatalf(`Unexpected log %s`, s)
+	}
+}
+
+func TestLogValueConfiguration(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewJSONHandler(&buf, nil))
+	config := &Configuration{Databases: &[]DatabaseInfo{{ID: "DEFAULT", ConnectionString: "sqlserver://sa:s3cr3t@db"}}}
+	l.Info("loaded", "config", config.RedactedLogValue(), "app", EmbeddedConfiguration{ID: "orders", Configuration: *config})
+	s := buf.String()
+	if !strings.Contains(s, `"config":{`) || !strings.Contains(s, `"ID":"orders"`) {
+		t.Fatalf(`Unexpected log %s`, s)
+	}
+	if strings.Contains(s[:strings.Index(s, `"app"`)], "s3cr3t") {
+		t.Fatalf(`Expected the configuration to be redacted in %s`, s)
+	}
+}
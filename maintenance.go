@@ -0,0 +1,77 @@
+package cfg
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const windowLayout = "2006-01-02 15:04"
+
+// bounds parses the start and end of the window in its time zone
+func (w MaintenanceWindowInfo) bounds() (start, end time.Time, err error) {
+	loc := time.UTC
+	if w.TimeZone != "" {
+		if loc, err = time.LoadLocation(w.TimeZone); err != nil {
+			return start, end, fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+		}
+	}
+	if start, err = time.ParseInLocation(windowLayout, w.Start, loc); err != nil {
+		return start, end, fmt.Errorf("%w: invalid start %q", ErrInvalidWindow, w.Start)
+	}
+	if end, err = time.ParseInLocation(windowLayout, w.End, loc); err != nil {
+		return start, end, fmt.Errorf("%w: invalid end %q", ErrInvalidWindow, w.End)
+	}
+	if !end.After(start) {
+		return start, end, fmt.Errorf("%w: end is not after start", ErrInvalidWindow)
+	}
+	return start, end, nil
+}
+
+// Active checks if the time falls within the window
+func (w MaintenanceWindowInfo) Active(now time.Time) bool {
+	start, end, err := w.bounds()
+	if err != nil {
+		return false
+	}
+	return !now.Before(start) && now.Before(end)
+}
+
+// Allowed checks if the IP address is allowed to pass during maintenance
+func (m MaintenanceInfo) Allowed(ip string) bool {
+	if h, _, err := net.SplitHostPort(ip); err == nil {
+		ip = h
+	}
+	pip := net.ParseIP(ip)
+	for _, a := range m.AllowedIPs {
+		a = strings.TrimSpace(a)
+		if _, cidr, err := net.ParseCIDR(a); err == nil {
+			if pip != nil && cidr.Contains(pip) {
+				return true
+			}
+			continue
+		}
+		if ap := net.ParseIP(a); ap != nil && pip != nil && ap.Equal(pip) {
+			return true
+		}
+	}
+	return false
+}
+
+// InMaintenance checks if the application is in maintenance at the time
+func (c *Configuration) InMaintenance(now time.Time) bool {
+	if c.Maintenance == nil {
+		return false
+	}
+	c.record(`Maintenance`, "")
+	if c.Maintenance.Enabled {
+		return true
+	}
+	for _, w := range c.Maintenance.Windows {
+		if w.Active(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package cfg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInMaintenance(t *testing.T) {
+	c := &Configuration{
+		Maintenance: &MaintenanceInfo{
+			Windows: []MaintenanceWindowInfo{
+				{Start: "2024-03-01 22:00", End: "2024-03-02 02:00", TimeZone: "Asia/Manila"},
+			},
+			AllowedIPs: []string{"10.0.0.0/8", "192.168.1.5"},
+		},
+	}
+	if err := c.validate(); err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	in := time.Date(2024, 3, 1, 15, 0, 0, 0, time.UTC) // 23:00 in Manila
+	out := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)
+	if !c.InMaintenance(in) {
+		t.Fatal(`Expected to be in maintenance`)
+	}
+	if c.InMaintenance(out) {
+		t.Fatal(`Expected not to be in maintenance`)
+	}
+	c.Maintenance.Enabled = true
+	if !c.InMaintenance(out) {
+		t.Fatal(`Expected enabled maintenance`)
+	}
+	if !c.Maintenance.Allowed("10.2.3.4:5000") || !c.Maintenance.Allowed("192.168.1.5") || c.Maintenance.Allowed("192.168.1.6") {
+		t.Fatal(`Unexpected allowlist result`)
+	}
+}
@@ -0,0 +1,81 @@
+package cfg
+
+import (
+	"path"
+	"strings"
+)
+
+// matchID reports whether an id matches a pattern under the key policy. Patterns with the wildcards *, ? or [
+// are matched like path.Match, while other patterns match the ids starting with them.
+func (c *Configuration) matchID(pattern, id string) bool {
+	pattern, id = c.keyPolicy.normalizePattern(pattern), c.keyPolicy.normalize(id)
+	if !strings.ContainsAny(pattern, "*?[") {
+		return strings.HasPrefix(id, pattern)
+	}
+	ok, err := path.Match(pattern, id)
+	return ok && err == nil
+}
+
+// GetDatabaseInfos gets the databases with the ids matching a pattern like reporting-* or a prefix like reporting-
+func (c *Configuration) GetDatabaseInfos(pattern string) []DatabaseInfo {
+	c = c.view()
+	dbs := make([]DatabaseInfo, 0)
+	if c.Databases == nil || pattern == "" {
+		return dbs
+	}
+	for _, v := range *c.Databases {
+		if c.matchID(pattern, v.ID) {
+			c.record(`Databases`, v.ID)
+			dbs = append(dbs, *guard(c, &v))
+		}
+	}
+	return dbs
+}
+
+// GetEndpointInfos gets the endpoints with the ids matching a pattern like shard-? or a prefix like shard-
+func (c *Configuration) GetEndpointInfos(pattern string) []EndpointInfo {
+	c = c.view()
+	eps := make([]EndpointInfo, 0)
+	if c.APIEndpoints == nil || pattern == "" {
+		return eps
+	}
+	for _, ep := range *c.APIEndpoints {
+		if c.matchID(pattern, ep.ID) {
+			c.record(`APIEndpoints`, ep.ID)
+			eps = append(eps, *guard(c, &ep))
+		}
+	}
+	return eps
+}
+
+// GetJobInfos gets the jobs with the ids matching a pattern or a prefix
+func (c *Configuration) GetJobInfos(pattern string) []JobInfo {
+	c = c.view()
+	jbs := make([]JobInfo, 0)
+	if c.Jobs == nil || pattern == "" {
+		return jbs
+	}
+	for _, v := range *c.Jobs {
+		if c.matchID(pattern, v.ID) {
+			c.record(`Jobs`, v.ID)
+			jbs = append(jbs, *guard(c, &v))
+		}
+	}
+	return jbs
+}
+
+// GetNotificationInfos gets the notifications with the ids matching a pattern or a prefix
+func (c *Configuration) GetNotificationInfos(pattern string) []NotificationInfo {
+	c = c.view()
+	nfs := make([]NotificationInfo, 0)
+	if c.Notifications == nil || pattern == "" {
+		return nfs
+	}
+	for _, nf := range *c.Notifications {
+		if c.matchID(pattern, nf.ID) {
+			c.record(`Notifications`, nf.ID)
+			nfs = append(nfs, *guard(c, &nf))
+		}
+	}
+	return nfs
+}
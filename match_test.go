@@ -0,0 +1,51 @@
+package cfg
+
+import "testing"
+
+func TestMatchID(t *testing.T) {
+	tests := []struct {
+		pattern, id string
+		policy      KeyPolicy
+		want        bool
+	}{
+		{"reporting-*", "REPORTING-eu", KeyDefault, true},
+		{"reporting-*", "reporting", KeyDefault, false},
+		{"shard-?", "shard-1", KeyDefault, true},
+		{"shard-?", "shard-10", KeyDefault, false},
+		{"shard-[12]", "shard-2", KeyDefault, true},
+		{"tenant-", "tenant-acme", KeyDefault, true},
+		{"tenant-", "main", KeyDefault, false},
+		{"[", "[", KeyDefault, false},
+		{"shard-[1-3]", "shard_2", KeyDefault, false},
+		{"shard-[1-3]", "shard_2", KeyNormalize, true},
+		{"reporting-*", "report", KeyNormalize, false},
+		{"reporting-*", "REPORTING-eu", KeyStrict, false},
+		{"Reporting-*", "Reporting-eu", KeyStrict, true},
+	}
+	for _, tt := range tests {
+		if got := (&Configuration{keyPolicy: tt.policy}).matchID(tt.pattern, tt.id); got != tt.want {
+			t.Fatalf(`matchID(%q, %q) = %v, expected %v`, tt.pattern, tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestGetInfos(t *testing.T) {
+	config := Configuration{
+		Databases: &[]DatabaseInfo{{ID: "reporting-eu"}, {ID: "reporting-us"}, {ID: "main"}},
+		APIEndpoints: &[]EndpointInfo{
+			{ID: "shard-1"}, {ID: "shard-2"}, {ID: "auth"},
+		},
+	}
+	if dbs := config.GetDatabaseInfos("reporting-*"); len(dbs) != 2 || dbs[1].ID != "reporting-us" {
+		t.Fatalf(`Unexpected databases %+v`, dbs)
+	}
+	if eps := config.GetEndpointInfos("shard-"); len(eps) != 2 {
+		t.Fatalf(`Unexpected endpoints %+v`, eps)
+	}
+	if eps := config.GetEndpointInfos(""); len(eps) != 0 {
+		t.Fatalf(`Expected no endpoints for an empty pattern, got %+v`, eps)
+	}
+	if jbs := config.GetJobInfos("*"); len(jbs) != 0 {
+		t.Fatalf(`Expected no jobs, got %+v`, jbs)
+	}
+}
@@ -0,0 +1,56 @@
+package cfg
+
+import (
+	"strings"
+	"time"
+)
+
+type (
+	// SourceKind - the kind of source a configuration was loaded from
+	SourceKind string
+
+	// SourceMetadata - where and when the current configuration was loaded from
+	SourceMetadata struct {
+		Source      string     // File name, URL or description of the source, like memory or base.json+env:APP_
+		Kind        SourceKind // Kind of the source
+		LoadedAt    time.Time  // Time of the last successful load or reload
+		ContentHash string     // SHA-256 of the loaded document in hex, after decryption and conversion to JSON
+	}
+)
+
+const (
+	SourceKindFile   SourceKind = "file"   // A local file
+	SourceKindURL    SourceKind = "url"    // A remote configuration fetched over HTTP
+	SourceKindEnv    SourceKind = "env"    // The environment variables of LoadFromEnv
+	SourceKindBundle SourceKind = "bundle" // A signed bundle
+	SourceKindLoader SourceKind = "loader" // The layers of a Loader
+	SourceKindCustom SourceKind = "source" // A Source like etcd or Consul
+)
+
+// SourceInfoMeta returns where and when the current configuration was loaded from, so health endpoints
+// and logs can report the provenance and the staleness of the configuration
+func (c *Configuration) SourceInfoMeta() SourceMetadata {
+	return SourceMetadata{
+		Source:      c.FileName,
+		Kind:        c.sourceKind(),
+		LoadedAt:    c.LastRefreshed(),
+		ContentHash: c.digest,
+	}
+}
+
+// sourceKind returns the kind of source the configuration was loaded from
+func (c *Configuration) sourceKind() SourceKind {
+	switch {
+	case c.loader != nil:
+		return SourceKindLoader
+	case c.source != nil:
+		return SourceKindCustom
+	case c.bundle != nil:
+		return SourceKindBundle
+	case strings.HasPrefix(c.FileName, envSource):
+		return SourceKindEnv
+	case c.local:
+		return SourceKindFile
+	}
+	return SourceKindURL
+}
@@ -0,0 +1,56 @@
+package cfg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSourceInfoMeta(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"HostPort": 8000}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config, err := Load(fn, WithClock(func() time.Time { return clock }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(doc))
+	want := SourceMetadata{Source: fn, Kind: SourceKindFile, LoadedAt: clock, ContentHash: hex.EncodeToString(sum[:])}
+	if got := config.SourceInfoMeta(); got != want {
+		t.Fatalf("Unexpected metadata\nwant: %+v\ngot:  %+v", want, got)
+	}
+
+	doc = `{"HostPort": 9000}`
+	if err = os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clock = clock.Add(time.Minute)
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	sum = sha256.Sum256([]byte(doc))
+	if got := config.SourceInfoMeta(); !got.LoadedAt.Equal(clock) || got.ContentHash != hex.EncodeToString(sum[:]) {
+		t.Fatalf(`Unexpected metadata after reload %+v`, got)
+	}
+
+	if config, err = LoadSource(context.Background(), &memorySource{doc: doc}); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.SourceInfoMeta(); got.Kind != SourceKindCustom || got.Source != "memory" {
+		t.Fatalf(`Unexpected metadata of the source %+v`, got)
+	}
+	t.Setenv("META_HOSTPORT", "8000")
+	if config, err = LoadFromEnv("META_"); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.SourceInfoMeta(); got.Kind != SourceKindEnv {
+		t.Fatalf(`Unexpected metadata of the environment %+v`, got)
+	}
+}
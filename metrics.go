@@ -0,0 +1,137 @@
+package cfg
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// EventKind - kind of an instrumentation event
+	EventKind string
+
+	// Event - an instrumentation event
+	Event struct {
+		Kind     EventKind     // Kind of the event
+		Source   string        // Source of the configuration
+		Time     time.Time     // Time when the event completed
+		Duration time.Duration // Time spent on the operation
+		Err      error         // Error of the operation. Nil on success
+	}
+
+	// Instrumentation receives events to export as metrics
+	Instrumentation interface {
+		Observe(e Event)
+	}
+
+	// InstrumentationFunc adapts a function to an Instrumentation
+	InstrumentationFunc func(e Event)
+
+	// Stats - counters of a configuration
+	Stats struct {
+		Loads           uint64        // Number of loads including reloads
+		ReloadSuccesses uint64        // Number of successful reloads
+		ReloadFailures  uint64        // Number of failed reloads
+		WatchEvents     uint64        // Number of change notifications received by watchers
+		LastReload      time.Time     // Time of the last successful reload
+		LastDuration    time.Duration // Time spent on the last load or reload
+	}
+
+	// stats - counters shared across reloads of a configuration
+	stats struct {
+		sync.Mutex
+		Stats
+	}
+)
+
+const (
+	EventLoad   EventKind = "load"
+	EventReload EventKind = "reload"
+	EventWatch  EventKind = "watch"
+
+	EventRollback EventKind = "rollback"
+)
+
+var (
+	instrumentationMu      sync.RWMutex
+	defaultInstrumentation Instrumentation
+)
+
+// Observe calls the function
+func (f InstrumentationFunc) Observe(e Event) {
+	f(e)
+}
+
+// SetInstrumentation sets the instrumentation of the package. Loads with the WithInstrumentation option use their own.
+func SetInstrumentation(i Instrumentation) {
+	instrumentationMu.Lock()
+	defaultInstrumentation = i
+	instrumentationMu.Unlock()
+}
+
+// WithInstrumentation sets the instrumentation of the configuration, overriding the instrumentation of the package
+func WithInstrumentation(i Instrumentation) Option {
+	return func(o *options) {
+		o.instrumentation = i
+	}
+}
+
+// Stats returns the counters of the configuration
+func (c *Configuration) Stats() Stats {
+	if c.stats == nil {
+		return Stats{}
+	}
+	c.stats.Lock()
+	defer c.stats.Unlock()
+	return c.stats.Stats
+}
+
+// observe updates the counters and sends the event to the instrumentation
+func (c *Configuration) observe(kind EventKind, source string, start time.Time, err error) {
+	if c.stats == nil {
+		c.stats = &stats{}
+	}
+	now := c.now()
+	e := Event{
+		Kind:     kind,
+		Source:   source,
+		Time:     now,
+		Duration: now.Sub(start),
+		Err:      err,
+	}
+	c.stats.Lock()
+	switch kind {
+	case EventLoad:
+		c.stats.Loads++
+		c.stats.LastDuration = e.Duration
+	case EventReload:
+		c.stats.Loads++
+		c.stats.LastDuration = e.Duration
+		if err != nil {
+			c.stats.ReloadFailures++
+		} else {
+			c.stats.ReloadSuccesses++
+			c.stats.LastReload = now
+		}
+	case EventWatch:
+		c.stats.WatchEvents++
+	}
+	c.stats.Unlock()
+
+	i := c.instrumentation
+	if i == nil {
+		instrumentationMu.RLock()
+		i = defaultInstrumentation
+		instrumentationMu.RUnlock()
+	}
+	if i != nil {
+		i.Observe(e)
+	}
+}
+
+// now returns the current time from the clock of the configuration
+func (c *Configuration) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock()
+}
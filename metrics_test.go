@@ -0,0 +1,41 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstrumentation(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	events := make([]Event, 0)
+	ins := InstrumentationFunc(func(e Event) {
+		events = append(events, e)
+	})
+	config, err := Load(fn, WithInstrumentation(ins))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(fn, []byte(`{"HostPort": `), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Reload(); err == nil {
+		t.Fatal(`Expected reload to fail`)
+	}
+	st := config.Stats()
+	if st.Loads != 3 || st.ReloadSuccesses != 1 || st.ReloadFailures != 1 || st.LastReload.IsZero() {
+		t.Fatalf(`Unexpected stats %+v`, st)
+	}
+	if len(events) != 3 || events[0].Kind != EventLoad || events[2].Kind != EventReload || events[2].Err == nil {
+		t.Fatalf(`Unexpected events %+v`, events)
+	}
+	if _, err = Load(filepath.Join(t.TempDir(), "missing.json"), WithInstrumentation(ins)); err == nil || events[3].Err == nil {
+		t.Fatal(`Expected failed load event`)
+	}
+}
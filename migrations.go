@@ -0,0 +1,42 @@
+package cfg
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// defaultMigrationsTable is the table recording the applied migrations when the MigrationsTable is not set
+const defaultMigrationsTable = "schema_migrations"
+
+// MigrationsURL returns the MigrationsPath as a URL like file://migrations/orders for migration tools,
+// or empty when it is not set. A path is relative to the working directory, and a URL is returned as is.
+func (db DatabaseInfo) MigrationsURL() string {
+	if db.MigrationsPath == "" || strings.Contains(db.MigrationsPath, "://") {
+		return db.MigrationsPath
+	}
+	return "file://" + filepath.ToSlash(db.MigrationsPath)
+}
+
+// MigrationsTableName returns the table recording the applied migrations, schema_migrations when not set
+func (db DatabaseInfo) MigrationsTableName() string {
+	if db.MigrationsTable == "" {
+		return defaultMigrationsTable
+	}
+	return db.MigrationsTable
+}
+
+// AutoMigrations returns the databases whose migrations run at startup, in the order they are configured
+func (c *Configuration) AutoMigrations() []DatabaseInfo {
+	c = c.view()
+	dbs := make([]DatabaseInfo, 0)
+	if c.Databases == nil {
+		return dbs
+	}
+	for _, db := range *c.Databases {
+		if db.AutoMigrate {
+			c.record(`Databases`, db.ID)
+			dbs = append(dbs, *guard(c, &db))
+		}
+	}
+	return dbs
+}
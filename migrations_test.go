@@ -0,0 +1,34 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	config := Configuration{
+		Databases: &[]DatabaseInfo{
+			{ID: "orders", ConnectionString: "postgres://localhost/orders", StorageType: "SERVER", MigrationsPath: "migrations/orders", AutoMigrate: true},
+			{ID: "reports", ConnectionString: "postgres://localhost/reports", StorageType: "SERVER", MigrationsPath: "s3://bucket/migrations", MigrationsTable: "reports_migrations"},
+			{ID: "cache", ConnectionString: "postgres://localhost/cache", StorageType: "SERVER"},
+		},
+	}
+	orders, reports, cache := config.GetDatabaseInfo("orders"), config.GetDatabaseInfo("reports"), config.GetDatabaseInfo("cache")
+	if orders.MigrationsURL() != "file://migrations/orders" || reports.MigrationsURL() != "s3://bucket/migrations" || cache.MigrationsURL() != "" {
+		t.Fatalf(`Unexpected migrations URLs %s %s %s`, orders.MigrationsURL(), reports.MigrationsURL(), cache.MigrationsURL())
+	}
+	if orders.MigrationsTableName() != "schema_migrations" || reports.MigrationsTableName() != "reports_migrations" {
+		t.Fatalf(`Unexpected migrations tables %s %s`, orders.MigrationsTableName(), reports.MigrationsTableName())
+	}
+	if dbs := config.AutoMigrations(); len(dbs) != 1 || dbs[0].ID != "orders" {
+		t.Fatalf(`Unexpected auto migrations %+v`, dbs)
+	}
+
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	(*config.Databases)[2].AutoMigrate = true
+	if err := config.Validate(); !errors.Is(err, ErrRequired) {
+		t.Fatalf(`Expected ErrRequired, got %v`, err)
+	}
+}
@@ -0,0 +1,122 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var ErrAmbiguous = errors.New("more than one entry matches")
+
+// GetEndpointInfoByName gets an endpoint by its Name, compared without case. It returns nil when no endpoint
+// has the name, and ErrAmbiguous with the ids of the endpoints when more than one has it.
+func (c *Configuration) GetEndpointInfoByName(name string) (*EndpointInfo, error) {
+	c = c.view()
+	if c.APIEndpoints == nil || strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	return findEndpoint(c, name, func(ep EndpointInfo) bool {
+		return sameName(name, ep.Name)
+	})
+}
+
+// GetEndpointInfoByHost gets an endpoint by the host of its Address or Addresses, like api.example.com.
+// A host with a port like api.example.com:8443 also matches the port. It returns nil when no endpoint
+// has the host, and ErrAmbiguous with the ids of the endpoints when more than one has it.
+func (c *Configuration) GetEndpointInfoByHost(host string) (*EndpointInfo, error) {
+	c = c.view()
+	if c.APIEndpoints == nil || strings.TrimSpace(host) == "" {
+		return nil, nil
+	}
+	return findEndpoint(c, host, func(ep EndpointInfo) bool {
+		return sameHost(host, append([]string{ep.Address}, ep.Addresses...)...)
+	})
+}
+
+// GetOAuthInfoByName gets an OAuth provider by its Name, compared without case. It returns nil when no provider
+// has the name, and ErrAmbiguous with the ids of the providers when more than one has it.
+func (c *Configuration) GetOAuthInfoByName(name string) (*OAuthProviderInfo, error) {
+	c = c.view()
+	if c.OAuths == nil || strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	return findOAuth(c, name, func(oa OAuthProviderInfo) bool {
+		return sameName(name, oa.Name)
+	})
+}
+
+// GetOAuthInfoByHost gets an OAuth provider by the host of its ProviderWebUri, ProviderApiUri or IssuerUri,
+// like login.example.com. It returns nil when no provider has the host, and ErrAmbiguous with the ids of the
+// providers when more than one has it.
+func (c *Configuration) GetOAuthInfoByHost(host string) (*OAuthProviderInfo, error) {
+	c = c.view()
+	if c.OAuths == nil || strings.TrimSpace(host) == "" {
+		return nil, nil
+	}
+	return findOAuth(c, host, func(oa OAuthProviderInfo) bool {
+		return sameHost(host, oa.ProviderWebUri, oa.ProviderApiUri, oa.IssuerUri)
+	})
+}
+
+// findEndpoint returns the only endpoint matching
+func findEndpoint(c *Configuration, key string, match func(EndpointInfo) bool) (*EndpointInfo, error) {
+	var found *EndpointInfo
+	ids := make([]string, 0)
+	for i, ep := range *c.APIEndpoints {
+		if match(ep) {
+			found = &(*c.APIEndpoints)[i]
+			ids = append(ids, ep.ID)
+		}
+	}
+	if len(ids) > 1 {
+		return nil, fmt.Errorf("%w: %s is %s", ErrAmbiguous, key, strings.Join(ids, ", "))
+	}
+	if found == nil {
+		return nil, nil
+	}
+	ep := *found
+	c.record(`APIEndpoints`, ep.ID)
+	return guard(c, &ep), nil
+}
+
+// findOAuth returns the only OAuth provider matching
+func findOAuth(c *Configuration, key string, match func(OAuthProviderInfo) bool) (*OAuthProviderInfo, error) {
+	var found *OAuthProviderInfo
+	ids := make([]string, 0)
+	for i, oa := range *c.OAuths {
+		if match(oa) {
+			found = &(*c.OAuths)[i]
+			ids = append(ids, oa.ID)
+		}
+	}
+	if len(ids) > 1 {
+		return nil, fmt.Errorf("%w: %s is %s", ErrAmbiguous, key, strings.Join(ids, ", "))
+	}
+	if found == nil {
+		return nil, nil
+	}
+	oa := *found
+	c.record(`OAuths`, oa.ID)
+	return guard(c, &oa), nil
+}
+
+// sameName compares display names without case and surrounding spaces
+func sameName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// sameHost checks if one of the URLs has the host, and the port when the host has one
+func sameHost(host string, urls ...string) bool {
+	host = strings.TrimSpace(host)
+	for _, u := range urls {
+		p, err := url.Parse(strings.TrimSpace(u))
+		if err != nil || p.Host == "" {
+			continue
+		}
+		if strings.EqualFold(host, p.Hostname()) || strings.EqualFold(host, p.Host) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupsByName(t *testing.T) {
+	config := Configuration{
+		APIEndpoints: &[]EndpointInfo{
+			{ID: "orders", Name: "Order Service", Address: "https://orders.example.com/api"},
+			{ID: "billing", Name: "Billing", Address: "https://billing.example.com:8443", Addresses: []string{"https://billing-2.example.com"}},
+			{ID: "billing-old", Name: "billing ", Address: "https://legacy.example.com"},
+		},
+		OAuths: &[]OAuthProviderInfo{
+			{ID: "google", Name: "Google", ProviderWebUri: "https://accounts.google.com/o/oauth2/auth"},
+			{ID: "azure", Name: "Azure", IssuerUri: "https://login.microsoftonline.com/tenant/v2.0"},
+		},
+	}
+	if ep, err := config.GetEndpointInfoByName("order service"); err != nil || ep == nil || ep.ID != "orders" {
+		t.Fatalf(`Unexpected endpoint %+v, %v`, ep, err)
+	}
+	if ep, err := config.GetEndpointInfoByName("Billing"); !errors.Is(err, ErrAmbiguous) || ep != nil {
+		t.Fatalf(`Expected ErrAmbiguous, got %+v, %v`, ep, err)
+	}
+	if ep, err := config.GetEndpointInfoByName("Shipping"); err != nil || ep != nil {
+		t.Fatalf(`Expected no endpoint, got %+v, %v`, ep, err)
+	}
+	for _, host := range []string{"billing.example.com", "billing.example.com:8443", "BILLING-2.example.com"} {
+		if ep, err := config.GetEndpointInfoByHost(host); err != nil || ep == nil || ep.ID != "billing" {
+			t.Fatalf(`Unexpected endpoint of %s %+v, %v`, host, ep, err)
+		}
+	}
+	if ep, err := config.GetEndpointInfoByHost("billing.example.com:443"); err != nil || ep != nil {
+		t.Fatalf(`Expected no endpoint on another port, got %+v, %v`, ep, err)
+	}
+	if oa, err := config.GetOAuthInfoByName("azure"); err != nil || oa == nil || oa.ID != "azure" {
+		t.Fatalf(`Unexpected provider %+v, %v`, oa, err)
+	}
+	if oa, err := config.GetOAuthInfoByHost("login.microsoftonline.com"); err != nil || oa == nil || oa.ID != "azure" {
+		t.Fatalf(`Unexpected provider %+v, %v`, oa, err)
+	}
+	if oa, err := config.GetOAuthInfoByHost("example.com"); err != nil || oa != nil {
+		t.Fatalf(`Expected no provider, got %+v, %v`, oa, err)
+	}
+}
@@ -0,0 +1,64 @@
+package cfg
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Option returns a provider specific option of the notification by case insensitive name
+func (nf *NotificationInfo) Option(name string) (string, bool) {
+	if v, ok := nf.Options[name]; ok {
+		return v, true
+	}
+	for k, v := range nf.Options {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// OptionString returns an option, or the default when not set
+func (nf *NotificationInfo) OptionString(name, def string) string {
+	if v, ok := nf.Option(name); ok {
+		return v
+	}
+	return def
+}
+
+// OptionInt returns an option as an int, or the default when not set or not a number
+func (nf *NotificationInfo) OptionInt(name string, def int) int {
+	v, ok := nf.Option(name)
+	if !ok {
+		return def
+	}
+	i, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return i
+}
+
+// OptionBool returns an option as a bool like the Bool of a Flag, or the default when not set
+func (nf *NotificationInfo) OptionBool(name string, def bool) bool {
+	v, ok := nf.Option(name)
+	if !ok {
+		return def
+	}
+	return *Flag{Value: &v}.Bool()
+}
+
+// OptionDuration returns an option like 30s or a number of seconds as a duration,
+// or the default when not set or not a duration
+func (nf *NotificationInfo) OptionDuration(name string, def time.Duration) time.Duration {
+	v, ok := nf.Option(name)
+	if !ok {
+		return def
+	}
+	d, err := parseDuration(strings.TrimSpace(v))
+	if err != nil {
+		return def
+	}
+	return d.Duration()
+}
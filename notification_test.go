@@ -0,0 +1,35 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotificationOptions(t *testing.T) {
+	nf := NotificationInfo{
+		ID: "SMS",
+		Options: map[string]string{
+			"Region":         "ap-southeast-1",
+			"retries":        "3",
+			"sandbox":        "yes",
+			"timeout":        "15s",
+			"signing-secret": "wh-s3cret",
+		},
+	}
+	if nf.OptionString("region", "") != "ap-southeast-1" || nf.OptionString("api-version", "v3") != "v3" {
+		t.Fatalf(`Unexpected string options`)
+	}
+	if nf.OptionInt("retries", 0) != 3 || nf.OptionInt("region", 1) != 1 {
+		t.Fatalf(`Unexpected int options`)
+	}
+	if !nf.OptionBool("sandbox", false) || !nf.OptionBool("missing", true) {
+		t.Fatalf(`Unexpected bool options`)
+	}
+	if nf.OptionDuration("timeout", 0) != 15*time.Second || nf.OptionDuration("missing", time.Minute) != time.Minute {
+		t.Fatalf(`Unexpected duration options`)
+	}
+	if s := nf.String(); strings.Contains(s, "wh-s3cret") || !strings.Contains(s, "ap-southeast-1") {
+		t.Fatalf(`Expected only the secret options to be redacted in %s`, s)
+	}
+}
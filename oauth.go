@@ -0,0 +1,217 @@
+package cfg
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// oidcDiscovery - fields of the OpenID discovery document used by the configuration
+type oidcDiscovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	JwksUri                           string   `json:"jwks_uri"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+// discoveryCache - discovery documents by issuer, shared by the providers of a configuration
+type discoveryCache struct {
+	sync.Mutex
+	docs map[string]*oidcDiscovery
+}
+
+// Discover fetches the OpenID discovery document from the issuer and fills the URIs that are not set.
+// The authorization endpoint fills ProviderWebUri while the token endpoint fills ProviderApiUri.
+// The documents of the providers of a configuration are cached, and fetched again after a reload.
+func (oa *OAuthProviderInfo) Discover(ctx context.Context) error {
+	if oa.IssuerUri == "" {
+		return fmt.Errorf("oauth %s: issuer uri is not set", oa.ID)
+	}
+	issuer := strings.TrimSuffix(oa.IssuerUri, "/")
+	var doc *oidcDiscovery
+	if dc := oa.discovery; dc != nil {
+		dc.Lock()
+		doc = dc.docs[issuer]
+		dc.Unlock()
+	}
+	if doc == nil {
+		var err error
+		if doc, err = oa.fetchDiscovery(ctx, issuer); err != nil {
+			return err
+		}
+		if dc := oa.discovery; dc != nil {
+			dc.Lock()
+			dc.docs[issuer] = doc
+			dc.Unlock()
+		}
+	}
+	if oa.ProviderWebUri == "" {
+		oa.ProviderWebUri = doc.AuthorizationEndpoint
+	}
+	if oa.ProviderApiUri == "" {
+		oa.ProviderApiUri = doc.TokenEndpoint
+	}
+	if oa.JwksUri == "" {
+		oa.JwksUri = doc.JwksUri
+	}
+	if oa.TokenEndpointAuthMethod == "" && len(doc.TokenEndpointAuthMethodsSupported) > 0 {
+		oa.TokenEndpointAuthMethod = doc.TokenEndpointAuthMethodsSupported[0]
+	}
+	return nil
+}
+
+// fetchDiscovery fetches the OpenID discovery document of the issuer
+func (oa *OAuthProviderInfo) fetchDiscovery(ctx context.Context, issuer string) (*oidcDiscovery, error) {
+	uri := issuer + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth %s: discovery returned status %d", oa.ID, res.StatusCode)
+	}
+	var doc oidcDiscovery
+	if err = json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Issuer != "" && strings.TrimSuffix(doc.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("oauth %s: discovery issuer %s does not match %s", oa.ID, doc.Issuer, oa.IssuerUri)
+	}
+	return &doc, nil
+}
+
+// TokenURL returns the URL of the token endpoint, the ProviderApiUri joined with the TokenPath when set
+func (oa *OAuthProviderInfo) TokenURL() string {
+	if oa.TokenPath == "" {
+		return oa.ProviderApiUri
+	}
+	return strings.TrimSuffix(oa.ProviderApiUri, "/") + "/" + strings.TrimPrefix(oa.TokenPath, "/")
+}
+
+// redirectURI returns the redirect URI, or the first of the RedirectUris when empty.
+// The redirect URI must be one of the RedirectUris when they are set.
+func (oa *OAuthProviderInfo) redirectURI(uri string) (string, error) {
+	if uri == "" {
+		if len(oa.RedirectUris) == 0 {
+			return "", fmt.Errorf("oauth %s: redirect uri is not set", oa.ID)
+		}
+		return oa.RedirectUris[0], nil
+	}
+	if len(oa.RedirectUris) == 0 {
+		return uri, nil
+	}
+	for _, ru := range oa.RedirectUris {
+		if ru == uri {
+			return uri, nil
+		}
+	}
+	return "", fmt.Errorf("oauth %s: redirect uri %s is not registered", oa.ID, uri)
+}
+
+// AuthorizeURL builds the URL redirecting the user to the provider for authorization with the state.
+// An empty redirect URI is the first of the RedirectUris. When UsePKCE is set, the code verifier to
+// pass to TokenRequest is returned, otherwise it is empty.
+func (oa *OAuthProviderInfo) AuthorizeURL(state, redirectURI string) (authURL, verifier string, err error) {
+	if oa.ProviderWebUri == "" {
+		return "", "", fmt.Errorf("oauth %s: provider web uri is not set", oa.ID)
+	}
+	if redirectURI, err = oa.redirectURI(redirectURI); err != nil {
+		return "", "", err
+	}
+	rt := oa.ResponseType
+	if rt == "" {
+		rt = "code"
+	}
+	q := url.Values{
+		"response_type": {rt},
+		"client_id":     {oa.ClientID},
+		"redirect_uri":  {redirectURI},
+	}
+	if oa.Scope != "" {
+		q.Set("scope", oa.Scope)
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+	if oa.UsePKCE {
+		b := make([]byte, 32)
+		if _, err = rand.Read(b); err != nil {
+			return "", "", err
+		}
+		verifier = base64.RawURLEncoding.EncodeToString(b)
+		sum := sha256.Sum256([]byte(verifier))
+		q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(sum[:]))
+		q.Set("code_challenge_method", "S256")
+	}
+	sep := "?"
+	if strings.Contains(oa.ProviderWebUri, "?") {
+		sep = "&"
+	}
+	return oa.ProviderWebUri + sep + q.Encode(), verifier, nil
+}
+
+// TokenRequest builds the request exchanging the authorization code for tokens at the TokenURL.
+// The client authenticates by the TokenEndpointAuthMethod: client_secret_basic by default,
+// client_secret_post sends the secret in the form, and none sends only the client id.
+// The verifier is the one returned by AuthorizeURL when UsePKCE is set.
+func (oa *OAuthProviderInfo) TokenRequest(ctx context.Context, code, redirectURI, verifier string) (*http.Request, error) {
+	tu := oa.TokenURL()
+	if tu == "" {
+		return nil, fmt.Errorf("oauth %s: provider api uri is not set", oa.ID)
+	}
+	redirectURI, err := oa.redirectURI(redirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if oa.UsePKCE && verifier == "" {
+		return nil, fmt.Errorf("oauth %s: code verifier is required with PKCE", oa.ID)
+	}
+	form := url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {redirectURI},
+	}
+	if verifier != "" {
+		form.Set("code_verifier", verifier)
+	}
+	return oa.tokenRequest(ctx, tu, form)
+}
+
+// tokenRequest builds the request posting the form to the token endpoint, authenticating the client
+// by the TokenEndpointAuthMethod
+func (oa *OAuthProviderInfo) tokenRequest(ctx context.Context, tu string, form url.Values) (*http.Request, error) {
+	method := oa.TokenEndpointAuthMethod
+	switch method {
+	case "client_secret_post":
+		form.Set("client_id", oa.ClientID)
+		form.Set("client_secret", oa.ClientSecret)
+	case "none":
+		form.Set("client_id", oa.ClientID)
+	case "", "client_secret_basic":
+	default:
+		return nil, fmt.Errorf("oauth %s: token endpoint auth method %s is not supported", oa.ID, method)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tu, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if method == "" || method == "client_secret_basic" {
+		req.SetBasicAuth(url.QueryEscape(oa.ClientID), url.QueryEscape(oa.ClientSecret))
+	}
+	return req, nil
+}
@@ -0,0 +1,150 @@
+package cfg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOAuthDiscover(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"issuer":"%[1]s","authorization_endpoint":"%[1]s/authorize","token_endpoint":"%[1]s/token","jwks_uri":"%[1]s/jwks","token_endpoint_auth_methods_supported":["client_secret_basic"]}`, srv.URL)
+	}))
+	defer srv.Close()
+
+	oa := OAuthProviderInfo{
+		ID:             "oidc",
+		IssuerUri:      srv.URL,
+		ProviderApiUri: "http://localhost/token",
+	}
+	if err := oa.Discover(context.Background()); err != nil {
+		t.Fatalf(`Error %v`, err)
+	}
+	if oa.ProviderWebUri != srv.URL+"/authorize" || oa.JwksUri != srv.URL+"/jwks" {
+		t.Fatalf(`Unexpected discovery result %+v`, oa)
+	}
+	if oa.ProviderApiUri != "http://localhost/token" {
+		t.Fatalf(`Configured URI was overwritten: %s`, oa.ProviderApiUri)
+	}
+	if oa.TokenEndpointAuthMethod != "client_secret_basic" {
+		t.Fatalf(`Unexpected auth method %s`, oa.TokenEndpointAuthMethod)
+	}
+}
+
+func TestOAuthAuthorize(t *testing.T) {
+	oa := OAuthProviderInfo{
+		ID:             "oidc",
+		ClientID:       "app",
+		ClientSecret:   "s3cret",
+		ProviderWebUri: "https://idp/authorize",
+		ProviderApiUri: "https://idp/api/",
+		TokenPath:      "/token",
+		Scope:          "openid email",
+		RedirectUris:   []string{"https://app/callback"},
+		UsePKCE:        true,
+	}
+	au, verifier, err := oa.AuthorizeURL("xyz", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(au)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	sum := sha256.Sum256([]byte(verifier))
+	if q.Get("client_id") != "app" || q.Get("redirect_uri") != "https://app/callback" || q.Get("state") != "xyz" ||
+		q.Get("response_type") != "code" || q.Get("scope") != "openid email" ||
+		q.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf(`Unexpected authorize URL %s`, au)
+	}
+	if _, _, err = oa.AuthorizeURL("xyz", "https://evil/callback"); err == nil {
+		t.Fatal(`Expected an error for an unregistered redirect uri`)
+	}
+	if _, err = oa.TokenRequest(context.Background(), "abc", "", ""); err == nil {
+		t.Fatal(`Expected an error without the code verifier`)
+	}
+
+	req, err := oa.TokenRequest(context.Background(), "abc", "", verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.String() != "https://idp/api/token" || req.Method != http.MethodPost {
+		t.Fatalf(`Unexpected token request %s %s`, req.Method, req.URL)
+	}
+	if user, pass, ok := req.BasicAuth(); !ok || user != "app" || pass != "s3cret" {
+		t.Fatalf(`Expected the client secret in basic auth`)
+	}
+	if err = req.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+	if req.PostForm.Get("code") != "abc" || req.PostForm.Get("code_verifier") != verifier || req.PostForm.Get("grant_type") != "authorization_code" {
+		t.Fatalf(`Unexpected token form %v`, req.PostForm)
+	}
+
+	oa.TokenEndpointAuthMethod = "client_secret_post"
+	if req, err = oa.TokenRequest(context.Background(), "abc", "", verifier); err != nil {
+		t.Fatal(err)
+	}
+	if err = req.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok := req.BasicAuth(); ok || req.PostForm.Get("client_secret") != "s3cret" {
+		t.Fatalf(`Expected the client secret in the form %v`, req.PostForm)
+	}
+}
+
+func TestOAuthDiscoverCached(t *testing.T) {
+	var (
+		srv     *httptest.Server
+		fetches int
+	)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		fmt.Fprintf(w, `{"issuer":"%[1]s","authorization_endpoint":"%[1]s/authorize","token_endpoint":"%[1]s/token","jwks_uri":"%[1]s/jwks"}`, srv.URL)
+	}))
+	defer srv.Close()
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := fmt.Sprintf(`{"OAuths": [{"ID": "oidc", "IssuerUri": %q}, {"ID": "other", "IssuerUri": %q}]}`, srv.URL, srv.URL+"/")
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"oidc", "oidc", "other"} {
+		oa := config.GetOAuthInfo(id)
+		if err = oa.Discover(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if oa.JwksUri != srv.URL+"/jwks" {
+			t.Fatalf(`Unexpected discovery result %+v`, oa)
+		}
+	}
+	if fetches != 1 {
+		t.Fatalf(`Expected the discovery document fetched once, got %d`, fetches)
+	}
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.GetOAuthInfo("oidc").Discover(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 2 {
+		t.Fatalf(`Expected the discovery document fetched again after reload, got %d`, fetches)
+	}
+}
@@ -0,0 +1,159 @@
+package cfg
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+type (
+	// Option sets an option on loading the configuration
+	Option func(*options)
+
+	// options - load options
+	options struct {
+		proxy     *ProxyInfo      // Proxy used on fetching remote configuration
+		transport *HTTPClientInfo // HTTP client setting used on fetching remote configuration
+		key       []byte          // Key to decrypt the encrypted values
+		logger    Logger          // Logger of the configuration
+
+		instrumentation Instrumentation // Instrumentation of the configuration
+		audit           bool            // Records the access to sections
+
+		lookupEnv func(string) (string, bool) // Looks up the environment variables of the placeholders
+		clock     func() time.Time            // Current time
+		limits    *Limits                     // Limits enforced on loading the document
+		verifier  signatureVerifier           // Verifies the detached signature of the document
+		checkLive func(*Configuration) error  // Checks the configuration after a reload, rolling back on failure
+		history   *history                    // Retains the versions of the configuration
+		bearer    string                      // Bearer token sent on fetching remote configuration
+		redirects *int                        // Redirects followed on fetching remote configuration
+		keyPolicy *KeyPolicy                  // Policy comparing the keys and ids of the lookups
+		templates bool                        // Evaluates the templates of the values on load
+		appEnv    string                      // Environment whose overrides are applied
+		vars      map[string]string           // Variables of the When conditions
+		raw       bool                        // Skips the implicit defaults on load
+
+		passphrase string      // Passphrase of an encrypted configuration file
+		passEnv    string      // Environment variable of the passphrase
+		hostname   string      // Host name of the override file
+		noHost     bool        // Skips the override file of the host name
+		platform   string      // Operating system and architecture of the override files like linux/amd64
+		exec       *execPolicy // Commands allowed to run for the ${exec:...} placeholders
+		files      *filePolicy // Files allowed to be read for the ${file:...} placeholders
+		suffixIDs  bool        // Looks up the ids suffixed with the environment first
+		readOnly   bool        // Rejects the changes of the configuration after load
+
+		refreshCtx   context.Context // Stops the refresh of a remote configuration
+		refreshEvery time.Duration   // Interval of the refresh of a remote configuration
+	}
+)
+
+// newOptions applies the options
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithProxy sets the proxy used on fetching a remote configuration.
+// On reload, the proxy of the loaded configuration is used when this option is not set.
+func WithProxy(p *ProxyInfo) Option {
+	return func(o *options) {
+		o.proxy = p
+	}
+}
+
+// WithHTTPClient sets the timeout and transport setting used on fetching a remote configuration.
+// On reload, the HTTPClient section of the loaded configuration is used when this option is not set.
+func WithHTTPClient(hc *HTTPClientInfo) Option {
+	return func(o *options) {
+		o.transport = hc
+	}
+}
+
+// WithBearerToken sets the bearer token sent on fetching a remote configuration, like from a Server.
+// On reload, the token of the loaded configuration is used when this option is not set.
+func WithBearerToken(token string) Option {
+	return func(o *options) {
+		o.bearer = token
+	}
+}
+
+// WithRedirects sets the number of redirects followed on fetching a remote configuration, instead of 10.
+// Zero follows none, so a redirect fails like any status other than 2xx.
+// On reload, the redirects of the loaded configuration are used when this option is not set.
+func WithRedirects(max int) Option {
+	return func(o *options) {
+		o.redirects = &max
+	}
+}
+
+// WithDecryptionKey sets the key to decrypt the sensitive fields in the encrypted-value format.
+// On reload, the key of the loaded configuration is used when this option is not set. Save writes
+// the decrypted fields encrypted as written.
+func WithDecryptionKey(key []byte) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// WithLogger sets the logger of the configuration, overriding the logger of the package
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// WithLookupEnv sets the function looking up the environment variables of the ${NAME} placeholders
+// instead of os.LookupEnv, so tests do not depend on the process environment.
+// On reload, the function of the loaded configuration is used when this option is not set.
+func WithLookupEnv(fn func(string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookupEnv = fn
+	}
+}
+
+// WithClock sets the function returning the current time instead of time.Now.
+// On reload, the clock of the loaded configuration is used when this option is not set.
+func WithClock(fn func() time.Time) Option {
+	return func(o *options) {
+		o.clock = fn
+	}
+}
+
+// WithReloadCheck sets a check run after each successful reload, like reconnecting to the databases.
+// When the check fails, the configuration is rolled back to the previous one.
+func WithReloadCheck(fn func(*Configuration) error) Option {
+	return func(o *options) {
+		o.checkLive = fn
+	}
+}
+
+// WithoutDefaults skips the implicit defaults on load, like the DEFAULT ids, the localhost cookie domain,
+// the defaultsecretkey JWT secret and the characters of the databases, so tools inspecting or transforming
+// the files see the values as written. Placeholders, overrides and conditions are still applied.
+// On reload, the defaults are skipped when the loaded configuration skipped them.
+func WithoutDefaults() Option {
+	return func(o *options) {
+		o.raw = true
+	}
+}
+
+// env returns the lookup of the environment variables
+func (o *options) env() func(string) (string, bool) {
+	if o.lookupEnv == nil {
+		return os.LookupEnv
+	}
+	return o.lookupEnv
+}
+
+// now returns the current time from the clock
+func (o *options) now() time.Time {
+	if o.clock == nil {
+		return time.Now()
+	}
+	return o.clock()
+}
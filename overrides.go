@@ -0,0 +1,153 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+type (
+	// overlay - a local override file merged over the configuration file
+	overlay struct {
+		name string
+		doc  map[string]any
+	}
+
+	// overrideValue - a value of the configuration file replaced by an override file
+	overrideValue struct {
+		companionValue
+		loaded any // The value after load
+	}
+)
+
+// WithHostname sets the host name of the override file like config.web01.json merged over config.json,
+// instead of the name of the machine.
+// On reload, the host name of the loaded configuration is used when this option is not set.
+func WithHostname(name string) Option {
+	return func(o *options) {
+		o.hostname = name
+	}
+}
+
+// WithoutHostOverrides skips the override file of the host name like config.web01.json on load.
+// On reload, the file is skipped when the loaded configuration skipped it.
+func WithoutHostOverrides() Option {
+	return func(o *options) {
+		o.noHost = true
+	}
+}
+
+// WithPlatform sets the operating system and architecture like linux and arm64 of the override files
+// like config.linux.json and config.linux-arm64.json, instead of the platform the application runs on.
+// On reload, the platform of the loaded configuration is used when this option is not set.
+func WithPlatform(goos, goarch string) Option {
+	return func(o *options) {
+		o.platform = goos + "/" + goarch
+	}
+}
+
+// overrideName returns the name of the override file of a configuration file like config.web01.json for config.json
+func overrideName(name, suffix string) string {
+	name = trimGzip(name)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + suffix + ext
+}
+
+// host returns the host name of the override file, or empty when it is skipped
+func (o *options) host() string {
+	if o.noHost {
+		return ""
+	}
+	if o.hostname != "" {
+		return o.hostname
+	}
+	h, _ := os.Hostname()
+	return h
+}
+
+// goosArch returns the operating system and architecture of the override files
+func (o *options) goosArch() (string, string) {
+	if o.platform == "" {
+		return runtime.GOOS, runtime.GOARCH
+	}
+	goos, goarch, _ := strings.Cut(o.platform, "/")
+	return goos, goarch
+}
+
+// readOverlays reads the override files of a local configuration file that exist, in the order they are merged:
+// the file of the operating system like config.windows.json, of the operating system and architecture like
+// config.windows-amd64.json, then of the host name like config.web01.json
+func readOverlays(name string, o *options, lim Limits) ([]overlay, error) {
+	goos, goarch := o.goosArch()
+	suffixes := make([]string, 0, 3)
+	if goos != "" {
+		suffixes = append(suffixes, goos)
+		if goarch != "" {
+			suffixes = append(suffixes, goos+"-"+goarch)
+		}
+	}
+	if h := o.host(); h != "" {
+		suffixes = append(suffixes, h)
+	}
+	ovs := make([]overlay, 0)
+	for _, sf := range suffixes {
+		on := overrideName(name, sf)
+		doc, err := readOverlay(on, lim)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			ovs = append(ovs, overlay{name: on, doc: doc})
+		}
+	}
+	return ovs, nil
+}
+
+// applyOverlays merges the override files over the document and returns the values they replaced as written
+func applyOverlays(doc map[string]any, ovs []overlay) []overrideValue {
+	ovr := make([]overrideValue, 0)
+	seen := make(map[string]bool)
+	for _, ov := range ovs {
+		for _, v := range companionOriginals(doc, ov.doc) {
+			// a value replaced by an earlier file was written as the first
+			if !seen[v.path] {
+				seen[v.path] = true
+				ovr = append(ovr, overrideValue{companionValue: v})
+			}
+		}
+		mergeDocuments(doc, ov.doc)
+	}
+	return ovr
+}
+
+// loadedOverrides sets the values of the overridden paths as loaded, after the defaults
+func loadedOverrides(doc map[string]any, ovr []overrideValue) {
+	for i, v := range ovr {
+		ovr[i].loaded, _ = getPath(doc, v.path)
+	}
+}
+
+// restoreOverrides writes the values as written in the configuration file back to a document for the
+// values of the override files that were not changed, so the overrides stay in their own files
+func restoreOverrides(doc map[string]any, ovr []overrideValue) {
+	for _, v := range ovr {
+		cur, err := getPath(doc, v.path)
+		if err != nil || !reflect.DeepEqual(cur, v.loaded) {
+			continue
+		}
+		if v.absent {
+			deletePath(doc, v.path)
+			continue
+		}
+		setPath(doc, v.path, v.value)
+	}
+}
+
+// OverrideFiles returns the override files merged over the configuration file on load, like config.linux.json
+// and config.web01.json
+func (c *Configuration) OverrideFiles() []string {
+	names := make([]string, 0, len(c.overlaid))
+	return append(names, c.overlaid...)
+}
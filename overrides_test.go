@@ -0,0 +1,119 @@
+package cfg
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHostOverrides(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "config.json")
+	doc := `{
+		"ApplicationID": "orders",
+		"HostPort": 8000,
+		"Sources": [{"ID": "order", "Source": "/var/lib/orders/inbound"}]
+	}`
+	host := `{
+		"HostPort": 9000,
+		"CertificateFile": "/etc/ssl/web01.pem",
+		"Sources": [{"ID": "order", "Source": "/mnt/inbound"}]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	hn := overrideName(fn, "web01")
+	if hn != filepath.Join(dir, "config.web01.json") {
+		t.Fatalf(`Unexpected override name %s`, hn)
+	}
+	if err := os.WriteFile(hn, []byte(host), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(fn, WithHostname("web01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 9000 || *config.CertificateFile != "/etc/ssl/web01.pem" || config.GetSourceInfo("order").Source != "/mnt/inbound" {
+		t.Fatalf(`Unexpected overrides %d %s %+v`, *config.HostPort, *config.CertificateFile, config.GetSourceInfo("order"))
+	}
+	if files := config.OverrideFiles(); len(files) != 1 || files[0] != hn {
+		t.Fatalf(`Unexpected override files %v`, files)
+	}
+
+	// the overrides stay in their file unless changed
+	config.ApplicationID = new_string("billing")
+	port := 9100
+	config.HostPort = &port
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := make(map[string]any)
+	if err = json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	src := saved["Sources"].([]any)[0].(map[string]any)
+	if saved["ApplicationID"] != "billing" || saved["HostPort"] != 9100.0 || saved["CertificateFile"] != nil || src["Source"] != "/var/lib/orders/inbound" {
+		t.Fatalf(`Unexpected saved file %s`, b)
+	}
+
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if *config.CertificateFile != "/etc/ssl/web01.pem" {
+		t.Fatalf(`Expected the host name to be kept on reload`)
+	}
+	if config, err = Load(fn, WithHostname("web01"), WithoutHostOverrides()); err != nil {
+		t.Fatal(err)
+	}
+	if config.CertificateFile != nil || len(config.OverrideFiles()) != 0 {
+		t.Fatalf(`Expected the override file to be skipped`)
+	}
+}
+
+func TestPlatformOverrides(t *testing.T) {
+	dir := t.TempDir()
+	fn := filepath.Join(dir, "config.json")
+	files := map[string]string{
+		fn:                                       `{"HostPort": 8000, "Sources": [{"ID": "order", "Source": "/var/lib/orders/inbound"}]}`,
+		overrideName(fn, "windows"):              `{"CertificateFile": "C:\\certs\\app.pem", "Sources": [{"ID": "order", "Source": "D:\\inbound"}]}`,
+		overrideName(fn, "windows-arm64"):        `{"HostPort": 9001}`,
+		overrideName(fn, "web01"):                `{"HostPort": 9002}`,
+		filepath.Join(dir, "config.darwin.json"): `{"HostPort": 9003}`,
+	}
+	for name, doc := range files {
+		if err := os.WriteFile(name, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := Load(fn, WithPlatform("windows", "arm64"), WithHostname("web01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 9002 || *config.CertificateFile != `C:\certs\app.pem` || config.GetSourceInfo("order").Source != `D:\inbound` {
+		t.Fatalf(`Unexpected overrides %d %s %+v`, *config.HostPort, *config.CertificateFile, config.GetSourceInfo("order"))
+	}
+	want := []string{overrideName(fn, "windows"), overrideName(fn, "windows-arm64"), overrideName(fn, "web01")}
+	if got := config.OverrideFiles(); len(got) != 3 || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf(`Unexpected override files %v`, got)
+	}
+
+	if config, err = Load(fn, WithPlatform("windows", "amd64"), WithoutHostOverrides()); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8000 || *config.CertificateFile != `C:\certs\app.pem` {
+		t.Fatalf(`Unexpected overrides for windows/amd64 %d`, *config.HostPort)
+	}
+	if config, err = Load(fn, WithPlatform("linux", "amd64"), WithoutHostOverrides()); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8000 || config.CertificateFile != nil {
+		t.Fatalf(`Expected no overrides for linux`)
+	}
+}
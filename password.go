@@ -0,0 +1,53 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrPasswordTooShort   = errors.New("password is too short")
+	ErrPasswordComplexity = errors.New("password does not meet complexity requirements")
+)
+
+// Validate checks a password against the policy
+func (p PasswordPolicyInfo) Validate(password string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("%w: minimum of %d characters", ErrPasswordTooShort, p.MinLength)
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.RequireUpper && !upper:
+		return fmt.Errorf("%w: requires an upper case letter", ErrPasswordComplexity)
+	case p.RequireLower && !lower:
+		return fmt.Errorf("%w: requires a lower case letter", ErrPasswordComplexity)
+	case p.RequireDigit && !digit:
+		return fmt.Errorf("%w: requires a digit", ErrPasswordComplexity)
+	case p.RequireSymbol && !symbol:
+		return fmt.Errorf("%w: requires a symbol", ErrPasswordComplexity)
+	}
+	classes := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.MinClasses {
+		return fmt.Errorf("%w: requires %d character classes", ErrPasswordComplexity, p.MinClasses)
+	}
+	return nil
+}
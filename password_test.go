@@ -0,0 +1,29 @@
+package cfg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	p := PasswordPolicyInfo{
+		MinLength:    8,
+		RequireDigit: true,
+		MinClasses:   3,
+	}
+	tests := []struct {
+		password string
+		err      error
+	}{
+		{"Ab1!", ErrPasswordTooShort},
+		{"abcdefgh", ErrPasswordComplexity},
+		{"abcdefg1", ErrPasswordComplexity},
+		{"Abcdefg1", nil},
+		{"abcdef1!", nil},
+	}
+	for _, tt := range tests {
+		if err := p.Validate(tt.password); !errors.Is(err, tt.err) {
+			t.Errorf(`Password %q: expected %v, got %v`, tt.password, tt.err, err)
+		}
+	}
+}
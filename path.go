@@ -0,0 +1,313 @@
+package cfg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidPath  = errors.New("invalid path")
+	ErrPathNotFound = errors.New("path not found")
+)
+
+// pathSegment - a field name with optional entry selectors like Databases[DEFAULT] or Items[0]
+type pathSegment struct {
+	name      string
+	selectors []string
+}
+
+// parsePath parses a dot path like Databases[DEFAULT].ConnectionString
+func parsePath(path string) ([]pathSegment, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("%w: path is empty", ErrInvalidPath)
+	}
+	segs := make([]pathSegment, 0)
+	for _, part := range strings.Split(path, ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		seg := pathSegment{name: name}
+		if name == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+		}
+		if rest != "" {
+			rest = "[" + rest
+		}
+		for rest != "" {
+			end := strings.Index(rest, "]")
+			if !strings.HasPrefix(rest, "[") || end < 2 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidPath, path)
+			}
+			seg.selectors = append(seg.selectors, rest[1:end])
+			rest = rest[end+1:]
+		}
+		segs = append(segs, seg)
+	}
+	return segs, nil
+}
+
+// lookupEntry selects an entry of a slice by index or by its ID, Name, GroupID or Key
+func lookupEntry(s []any, sel string) (int, bool) {
+	if i, err := strconv.Atoi(sel); err == nil {
+		return i, i >= 0 && i < len(s)
+	}
+	for _, k := range []string{"ID", "Name", "GroupID", "Key"} {
+		for i, e := range s {
+			m, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			if id, ok := fieldValue(m, k).(string); ok && strings.EqualFold(id, sel) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// lookupKey gets the actual key of a map by case insensitive name
+func lookupKey(m map[string]any, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+	return name, false
+}
+
+// getPath gets the value at the path of a generic document
+func getPath(doc map[string]any, path string) (any, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	var cur any = doc
+	for _, seg := range segs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		k, ok := lookupKey(m, seg.name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		cur = m[k]
+		for _, sel := range seg.selectors {
+			s, ok := cur.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			i, ok := lookupEntry(s, sel)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			cur = s[i]
+		}
+	}
+	return cur, nil
+}
+
+// setPath sets the value at the path of a generic document. Missing fields of objects are created.
+func setPath(doc map[string]any, path string, value any) error {
+	segs, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	var (
+		cur    any = doc
+		assign func(any)
+	)
+	for si, seg := range segs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+		}
+		k, found := lookupKey(m, seg.name)
+		if !found {
+			if len(seg.selectors) > 0 {
+				return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			if si < len(segs)-1 {
+				m[k] = make(map[string]any)
+			}
+		}
+		cur = m[k]
+		assign = func(v any) { m[k] = v }
+		for _, sel := range seg.selectors {
+			s, ok := cur.([]any)
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			i, ok := lookupEntry(s, sel)
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrPathNotFound, path)
+			}
+			cur = s[i]
+			assign = func(v any) { s[i] = v }
+		}
+		if si < len(segs)-1 && cur == nil {
+			nm := make(map[string]any)
+			assign(nm)
+			cur = nm
+		}
+	}
+	assign(value)
+	return nil
+}
+
+// deletePath removes the field or the entry of a section at the path of a generic document
+func deletePath(doc map[string]any, path string) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return
+	}
+	last := segs[len(segs)-1]
+	parent := doc
+	if len(segs) > 1 {
+		prefix := path[:strings.LastIndex(path, "."+last.name)]
+		v, err := getPath(doc, prefix)
+		if err != nil {
+			return
+		}
+		if parent, _ = v.(map[string]any); parent == nil {
+			return
+		}
+	}
+	k, ok := lookupKey(parent, last.name)
+	if !ok {
+		return
+	}
+	if len(last.selectors) == 0 {
+		delete(parent, k)
+		return
+	}
+	cur, assign := parent[k], func(v any) { parent[k] = v }
+	for i, sel := range last.selectors {
+		s, ok := cur.([]any)
+		if !ok {
+			return
+		}
+		j, ok := lookupEntry(s, sel)
+		if !ok {
+			return
+		}
+		if i == len(last.selectors)-1 {
+			assign(append(s[:j:j], s[j+1:]...))
+			return
+		}
+		cur, assign = s[j], func(v any) { s[j] = v }
+	}
+}
+
+// ParseValue converts text to a value of the same type as the current value.
+// When there is no current value, the text is parsed as JSON and falls back to a string.
+func ParseValue(current any, text string) (any, error) {
+	switch current.(type) {
+	case string:
+		return text, nil
+	case bool:
+		return strconv.ParseBool(text)
+	case int64, float64, json.Number:
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return i, nil
+		}
+		return strconv.ParseFloat(text, 64)
+	}
+	var v any
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return text, nil
+	}
+	return normalizeValue(v), nil
+}
+
+// GetDocumentValue gets the value at the path of a configuration document.
+// Values are returned as written so ${ENV} placeholders are preserved.
+func GetDocumentValue(b []byte, f Format, path string) (any, error) {
+	doc, err := decodeDocument(b, f)
+	if err != nil {
+		return nil, err
+	}
+	return getPath(doc, path)
+}
+
+// SetDocumentValue sets the value at the path of a configuration document from text,
+// keeping the type of the current value. Other values are kept as written.
+func SetDocumentValue(b []byte, f Format, path, text string) ([]byte, error) {
+	doc, err := decodeDocument(b, f)
+	if err != nil {
+		return nil, err
+	}
+	cur, _ := getPath(doc, path)
+	v, err := ParseValue(cur, text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err = setPath(doc, path, v); err != nil {
+		return nil, err
+	}
+	return encodeDocument(doc, f)
+}
+
+// GetField gets the value of a field by its dot path like Databases[DEFAULT].Schema or Flags[MaxLimit].value.
+// Entries of sections are selected by index or by ID.
+func (c *Configuration) GetField(path string) (any, error) {
+	c = c.view()
+	if segs, err := parsePath(path); err == nil {
+		sel := ""
+		if len(segs[0].selectors) > 0 {
+			sel = segs[0].selectors[0]
+		}
+		c.record(segs[0].name, sel)
+	}
+	doc, err := configDocument(c)
+	if err != nil {
+		return nil, err
+	}
+	return getPath(doc, path)
+}
+
+// SetField sets the value of a field by its dot path
+func (c *Configuration) SetField(path string, value any) error {
+	if err := c.writable(); err != nil {
+		return err
+	}
+	doc, err := configDocument(c)
+	if err != nil {
+		return err
+	}
+	if err = setPath(doc, path, value); err != nil {
+		return err
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	nc := Configuration{}
+	if err = json.Unmarshal(b, &nc); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	nc.FileName = c.FileName
+	copyExported(c, &nc)
+	if c.layers == nil {
+		c.layers = make(map[string]Provenance)
+	}
+	recordLayer(c.layers, canonicalPath(doc, path), Provenance{Layer: LayerProgrammatic, Source: "SetField"})
+	return nil
+}
+
+// copyExported copies the exported fields, keeping the unexported state of the destination
+func copyExported(dst, src *Configuration) {
+	dv, sv := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := 0; i < dv.NumField(); i++ {
+		if dv.Type().Field(i).IsExported() {
+			dv.Field(i).Set(sv.Field(i))
+		}
+	}
+}
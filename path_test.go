@@ -0,0 +1,78 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDocumentValue(t *testing.T) {
+	b, err := os.ReadFile("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]any{
+		"HostPort":                      int64(8000),
+		"Databases[DEFAULT].DriverName": "mssql",
+		"Databases[0].SequenceGenerator.NamePlaceHolder": "{SequenceName}",
+		"flags[maxlimit].Value":                          "10000",
+		"Webhooks[orders].Secret":                        "${WEBHOOK_SECRET}",
+	}
+	for path, want := range tests {
+		got, err := GetDocumentValue(b, FormatJSON, path)
+		if err != nil || got != want {
+			t.Errorf(`Path %s: expected %v, got %v (%v)`, path, want, got, err)
+		}
+	}
+	if _, err = GetDocumentValue(b, FormatJSON, "Databases[NONE].ID"); !errors.Is(err, ErrPathNotFound) {
+		t.Errorf(`Expected ErrPathNotFound, got %v`, err)
+	}
+
+	b, err = SetDocumentValue(b, FormatJSON, "HostPort", "9000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = SetDocumentValue(b, FormatJSON, "LicenseSerial", "00001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = SetDocumentValue(b, FormatJSON, "Proxy.HTTP", "proxy:3128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = SetDocumentValue(b, FormatJSON, "HostPort", "many"); err == nil {
+		t.Error(`Expected an error on setting a number to text`)
+	}
+	if !strings.Contains(string(b), "${WEBHOOK_SECRET}") {
+		t.Error(`Placeholder was not preserved`)
+	}
+	if v, _ := GetDocumentValue(b, FormatJSON, "HostPort"); v != int64(9000) {
+		t.Errorf(`Expected HostPort 9000, got %v`, v)
+	}
+	if v, _ := GetDocumentValue(b, FormatJSON, "LicenseSerial"); v != "00001" {
+		t.Errorf(`Expected LicenseSerial 00001, got %v`, v)
+	}
+	if v, _ := GetDocumentValue(b, FormatJSON, "Proxy.HTTP"); v != "proxy:3128" {
+		t.Errorf(`Expected Proxy.HTTP proxy:3128, got %v`, v)
+	}
+}
+
+func TestConfigurationField(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.SetField("Databases[DEFAULT].Schema", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); db.Schema != "app" {
+		t.Fatalf(`Expected schema app, got %s`, db.Schema)
+	}
+	if v, err := config.GetField("Databases[DEFAULT].Schema"); err != nil || v != "app" {
+		t.Fatalf(`Expected schema app, got %v (%v)`, v, err)
+	}
+	if config.FileName != "samples/config.mssql.json" {
+		t.Fatalf(`File name was lost`)
+	}
+}
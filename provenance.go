@@ -0,0 +1,123 @@
+package cfg
+
+import (
+	"strings"
+)
+
+type (
+	// Layer - a layer of the configuration setting values
+	Layer string
+
+	// Provenance - the layer that set the effective value of a field
+	Provenance struct {
+		Layer  Layer  // Layer like file, override or default. Empty when no layer set the value
+		Source string // File, environment variables or function of the layer, like config.web01.json or DB_PASSWORD
+	}
+)
+
+const (
+	LayerFile         Layer = "file"         // The configuration file or a file of the loader
+	LayerBase         Layer = "base"         // A base configuration of the inheritance chain
+	LayerOverride     Layer = "override"     // An override file like config.web01.json
+	LayerSecrets      Layer = "secrets"      // The companion secrets file
+	LayerEnvironment  Layer = "environment"  // The Environments overrides of the entry
+	LayerEnv          Layer = "env"          // Environment variables, or the placeholders of the value
+	LayerProgrammatic Layer = "programmatic" // The overrides of the loader or SetField
+	LayerDefault      Layer = "default"      // The implicit defaults
+)
+
+// Provenance returns the layer that set the effective value at the path like Databases[DEFAULT].Schema,
+// Databases[0].Schema or HostPort, like the file, an override file, an environment variable or a default.
+// Values of an object are set by the layer that set the object.
+func (c *Configuration) Provenance(path string) Provenance {
+	doc, err := configDocument(c)
+	if err != nil {
+		return Provenance{}
+	}
+	p := strings.ToLower(canonicalPath(doc, path))
+	for {
+		if pv, ok := c.layers[p]; ok {
+			return pv
+		}
+		i := strings.LastIndexAny(p, ".[")
+		if i <= 0 {
+			return Provenance{}
+		}
+		p = p[:i]
+	}
+}
+
+// recordLayers records the layer of every value of the document
+func recordLayers(layers map[string]Provenance, doc map[string]any, pv Provenance) {
+	walkLeaves(doc, "", func(path string) {
+		layers[strings.ToLower(path)] = pv
+	})
+}
+
+// recordLayer records the layer of the value at the path like Databases[DEFAULT].Schema,
+// replacing the layers of the values it contains
+func recordLayer(layers map[string]Provenance, path string, pv Provenance) {
+	p := strings.ToLower(path)
+	for k := range layers {
+		if strings.HasPrefix(k, p+".") || strings.HasPrefix(k, p+"[") {
+			delete(layers, k)
+		}
+	}
+	layers[p] = pv
+}
+
+// canonicalPath converts a path selecting entries by index like Databases[0].Schema to the path
+// selecting them by their identifying key like Databases[DEFAULT].Schema, the way the layers are recorded
+func canonicalPath(doc map[string]any, path string) string {
+	segs, err := parsePath(path)
+	if err != nil {
+		return path
+	}
+	parts := make([]string, 0, len(segs))
+	var cur any = doc
+	for _, seg := range segs {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return path
+		}
+		k, ok := lookupKey(m, seg.name)
+		if !ok {
+			return path
+		}
+		part := k
+		cur = m[k]
+		for _, sel := range seg.selectors {
+			s, ok := cur.([]any)
+			if !ok {
+				return path
+			}
+			i, ok := lookupEntry(s, sel)
+			if !ok {
+				return path
+			}
+			cur = s[i]
+			key := entryKey(s, nil)
+			if key == "" {
+				part += "[" + sel + "]"
+				continue
+			}
+			id, _ := fieldValue(cur.(map[string]any), key).(string)
+			part += "[" + id + "]"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// placeholderSource names the environment variables and the resolvers of the placeholders of a value
+func placeholderSource(raw string) string {
+	names := make([]string, 0)
+	for _, m := range envPattern.FindAllStringSubmatch(raw, -1) {
+		if m[1] != "" {
+			names = append(names, m[1])
+			continue
+		}
+		names = append(names, m[2]+":"+strings.TrimSpace(m[3]))
+	}
+	return strings.Join(names, ", ")
+}
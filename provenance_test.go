@@ -0,0 +1,73 @@
+package cfg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProvenance(t *testing.T) {
+	dir := t.TempDir()
+	fn, base := filepath.Join(dir, "config.json"), filepath.Join(dir, "base.json")
+	files := map[string]string{
+		base: `{"ApplicationName": "Orders", "HostPort": 8000}`,
+		fn: `{
+			"Inherits": "base.json",
+			"ApplicationID": "orders",
+			"Databases": [{
+				"ID": "DEFAULT",
+				"ConnectionString": "postgres://app:${DB_PASSWORD}@db/orders",
+				"Schema": "dbo",
+				"Environments": {"prod": {"Schema": "app"}}
+			}]
+		}`,
+		overrideName(fn, "web01"): `{"HostPort": 9000}`,
+	}
+	for name, doc := range files {
+		if err := os.WriteFile(name, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lookup := func(string) (string, bool) { return "s3cret", true }
+	config, err := Load(fn, WithHostname("web01"), WithEnvironment("prod"), WithLookupEnv(lookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want Provenance
+	}{
+		{"ApplicationID", Provenance{Layer: LayerFile, Source: fn}},
+		{"ApplicationName", Provenance{Layer: LayerBase, Source: base}},
+		{"HostPort", Provenance{Layer: LayerOverride, Source: overrideName(fn, "web01")}},
+		{"Databases[DEFAULT].ConnectionString", Provenance{Layer: LayerEnv, Source: "DB_PASSWORD"}},
+		{"Databases[0].ConnectionString", Provenance{Layer: LayerEnv, Source: "DB_PASSWORD"}},
+		{"databases[default].schema", Provenance{Layer: LayerEnvironment, Source: "prod"}},
+		{"CookieDomain", Provenance{Layer: LayerDefault}},
+		{"LicenseSerial", Provenance{}},
+	}
+	for _, tt := range tests {
+		if got := config.Provenance(tt.path); got != tt.want {
+			t.Fatalf(`Provenance of %s = %+v, expected %+v`, tt.path, got, tt.want)
+		}
+	}
+	if err = config.SetField("HostPort", 9100); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Provenance("HostPort"); got.Layer != LayerProgrammatic {
+		t.Fatalf(`Unexpected provenance after SetField %+v`, got)
+	}
+
+	config, err = NewLoader(WithLookupEnv(lookup)).File(base).Overrides(map[string]any{"HostPort": 7000}).Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Provenance("ApplicationName"); got != (Provenance{Layer: LayerFile, Source: base}) {
+		t.Fatalf(`Unexpected provenance of the loader file %+v`, got)
+	}
+	if got := config.Provenance("HostPort"); got != (Provenance{Layer: LayerProgrammatic, Source: "overrides"}) {
+		t.Fatalf(`Unexpected provenance of the loader overrides %+v`, got)
+	}
+}
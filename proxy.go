@@ -0,0 +1,81 @@
+package cfg
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyFunc returns a function that selects the proxy for a request, suitable for http.Transport.
+// HTTPS requests use the HTTPS proxy, falling back to the HTTP proxy when it is not set.
+func (p ProxyInfo) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	return func(r *http.Request) (*url.URL, error) {
+		return p.proxyURL(r.URL)
+	}
+}
+
+// proxyURL returns the proxy URL for the target, or nil if no proxy is used
+func (p ProxyInfo) proxyURL(target *url.URL) (*url.URL, error) {
+	if p.bypass(target.Hostname()) {
+		return nil, nil
+	}
+	raw := p.HTTP
+	if strings.EqualFold(target.Scheme, "https") && p.HTTPS != "" {
+		raw = p.HTTPS
+	}
+	raw = p.memo.resolve(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	pu, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if pu.User == nil && p.Username != "" {
+		pu.User = url.UserPassword(p.memo.resolve(p.Username), p.memo.resolve(p.Password))
+	}
+	return pu, nil
+}
+
+// bypass checks if the host is listed in NoProxy
+func (p ProxyInfo) bypass(host string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+	for _, np := range strings.Split(p.NoProxy, ",") {
+		np = strings.ToLower(strings.TrimSpace(np))
+		if np == "" {
+			continue
+		}
+		if np == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(np); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, _, err := net.SplitHostPort(np); err == nil {
+			np = h
+		}
+		np = strings.TrimPrefix(np, "*")
+		if host == strings.TrimPrefix(np, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(np, ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProxyFunc returns the proxy selector from the Proxy section.
+// The environment proxy settings are used when the section is not set.
+func (c *Configuration) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	if c.Proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+	c.record(`Proxy`, "")
+	return c.Proxy.ProxyFunc()
+}
@@ -0,0 +1,81 @@
+package cfg
+
+import "encoding/json"
+
+type (
+	// PublicInfo - the part of the configuration safe to expose to browsers and mobile apps
+	PublicInfo struct {
+		ApplicationID    string            `json:",omitempty"`
+		ApplicationName  string            `json:",omitempty"`
+		ApplicationTheme string            `json:",omitempty"`
+		HostExternalURL  string            `json:",omitempty"`
+		OAuths           []PublicOAuthInfo `json:",omitempty"`
+		Flags            map[string]string `json:",omitempty"` // Flags marked Public
+	}
+
+	// PublicOAuthInfo - the public part of an OAuth provider
+	PublicOAuthInfo struct {
+		ID             string
+		Name           string   `json:",omitempty"`
+		IconUrl        string   `json:",omitempty"`
+		EmbedText      string   `json:",omitempty"`
+		Label          string   `json:",omitempty"`
+		ClientID       string   `json:",omitempty"`
+		ProviderWebUri string   `json:",omitempty"`
+		ResponseType   string   `json:",omitempty"`
+		Scope          string   `json:",omitempty"`
+		IssuerUri      string   `json:",omitempty"`
+		RedirectUris   []string `json:",omitempty"`
+	}
+)
+
+// Public returns the part of the configuration safe to expose to clients: the application name, id and theme,
+// the external host URL, the OAuth providers without their secrets and the flags marked Public
+func (c *Configuration) Public() PublicInfo {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	pi := PublicInfo{
+		ApplicationID:    deref(c.ApplicationID),
+		ApplicationName:  deref(c.ApplicationName),
+		ApplicationTheme: deref(c.ApplicationTheme),
+		HostExternalURL:  deref(c.HostExternalURL),
+	}
+	if c.OAuths != nil {
+		for _, oa := range *c.OAuths {
+			pi.OAuths = append(pi.OAuths, PublicOAuthInfo{
+				ID:             oa.ID,
+				Name:           oa.Name,
+				IconUrl:        oa.IconUrl,
+				EmbedText:      oa.EmbedText,
+				Label:          oa.Label,
+				ClientID:       oa.ClientID,
+				ProviderWebUri: oa.ProviderWebUri,
+				ResponseType:   oa.ResponseType,
+				Scope:          oa.Scope,
+				IssuerUri:      oa.IssuerUri,
+				RedirectUris:   oa.RedirectUris,
+			})
+		}
+	}
+	if c.Flags != nil {
+		for _, f := range *c.Flags {
+			if !f.Public || f.Value == nil {
+				continue
+			}
+			if pi.Flags == nil {
+				pi.Flags = make(map[string]string)
+			}
+			pi.Flags[f.Key] = *f.Value
+		}
+	}
+	return pi
+}
+
+// PublicView encodes the public part of the configuration returned by Public as JSON
+func (c *Configuration) PublicView() ([]byte, error) {
+	return json.Marshal(c.Public())
+}
@@ -0,0 +1,36 @@
+package cfg
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPublicView(t *testing.T) {
+	config := Configuration{
+		ApplicationID:   new_string("orders"),
+		HostExternalURL: new_string("https://orders.example.com"),
+		HostInternalURL: new_string("http://10.0.0.5:8000"),
+		OAuths:          &[]OAuthProviderInfo{{ID: "google", ClientID: "client-1", ClientSecret: "s3cr3t"}},
+		Flags: &[]Flag{
+			{Key: "Beta", Value: new_string("on"), Public: true},
+			{Key: "AdminToken", Value: new_string("t0k3n")},
+		},
+	}
+	b, err := config.PublicView()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{"s3cr3t", "t0k3n", "10.0.0.5"} {
+		if strings.Contains(string(b), s) {
+			t.Fatalf(`Expected %s to be left out of %s`, s, b)
+		}
+	}
+	var pi PublicInfo
+	if err = json.Unmarshal(b, &pi); err != nil {
+		t.Fatal(err)
+	}
+	if pi.ApplicationID != "orders" || len(pi.OAuths) != 1 || pi.OAuths[0].ClientID != "client-1" || pi.Flags["Beta"] != "on" || len(pi.Flags) != 1 {
+		t.Fatalf(`Unexpected public view %s`, b)
+	}
+}
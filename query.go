@@ -0,0 +1,48 @@
+package cfg
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// txIsolations are the isolation levels of the DefaultTxIsolation
+var txIsolations = map[string]sql.IsolationLevel{
+	"READ-UNCOMMITTED": sql.LevelReadUncommitted,
+	"READ-COMMITTED":   sql.LevelReadCommitted,
+	"REPEATABLE-READ":  sql.LevelRepeatableRead,
+	"SNAPSHOT":         sql.LevelSnapshot,
+	"SERIALIZABLE":     sql.LevelSerializable,
+}
+
+// QueryTimeout returns the DefaultQueryTimeout of the database, or zero when the queries have no limit
+func (db DatabaseInfo) QueryTimeout() time.Duration {
+	if db.DefaultQueryTimeout == nil {
+		return 0
+	}
+	return db.DefaultQueryTimeout.Duration()
+}
+
+// QueryContext returns a context ending after the DefaultQueryTimeout for running a query, or the context
+// with a no-op cancel when the queries have no limit or the context ends sooner
+func (db DatabaseInfo) QueryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	d := db.QueryTimeout()
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= d {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// TxOptions returns the options of the transactions with the DefaultTxIsolation for BeginTx, or nil
+// for the defaults of the driver when it is not set
+func (db DatabaseInfo) TxOptions(readOnly bool) *sql.TxOptions {
+	level, ok := txIsolations[strings.ToUpper(db.DefaultTxIsolation)]
+	if !ok && !readOnly {
+		return nil
+	}
+	return &sql.TxOptions{Isolation: level, ReadOnly: readOnly}
+}
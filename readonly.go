@@ -0,0 +1,87 @@
+package cfg
+
+import (
+	"errors"
+	"reflect"
+)
+
+var ErrReadOnly = errors.New("configuration is read-only")
+
+// WithReadOnly makes the configuration immutable after load: Save, SetField, RefreshEnv, the reloads,
+// the watches and Rollback return ErrReadOnly, and the lookups like GetDatabaseInfo, GetDatabaseInfos and
+// GetDatabaseInfoGroup return entries whose exported pointers, slices and maps are copies, so changing them
+// leaves the configuration as loaded. The state of the entries like the token cache of a provider is still
+// shared, and the exported fields of the configuration can still be changed directly.
+func WithReadOnly() Option {
+	return func(o *options) {
+		o.readOnly = true
+	}
+}
+
+// writable returns ErrReadOnly when the configuration is read-only
+func (c *Configuration) writable() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// guard returns the entry found by a lookup, copying the values it shares with a read-only configuration
+func guard[T any](c *Configuration, v *T) *T {
+	if c.readOnly {
+		cloneExported(reflect.ValueOf(v).Elem())
+	}
+	return v
+}
+
+// cloneExported replaces the pointers, slices and maps reached through the exported fields of a value
+// with copies. The unexported state like the selection state of an endpoint stays shared.
+func cloneExported(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(v.Elem())
+		cloneExported(p.Elem())
+		v.Set(p)
+	case reflect.Slice:
+		if v.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		for i := 0; i < s.Len(); i++ {
+			cloneExported(s.Index(i))
+		}
+		v.Set(s)
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			e := reflect.New(v.Type().Elem()).Elem()
+			e.Set(iter.Value())
+			cloneExported(e)
+			m.SetMapIndex(iter.Key(), e)
+		}
+		v.Set(m)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		e := reflect.New(v.Elem().Type()).Elem()
+		e.Set(v.Elem())
+		cloneExported(e)
+		v.Set(e)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				cloneExported(v.Field(i))
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadOnly(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"APIEndpoints": [{"ID": "DEFAULT", "Address": "https://api.example.com", "Token": "t0ken", "Addresses": ["https://a.example.com"]}],
+		"Databases": [{"ID": "reporting-eu", "GroupID": "reporting", "ConnectionString": "postgres://localhost/eu", "MaxOpenConnection": 10}],
+		"Flags": [{"key": "Beta", "value": "on"}]
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithReadOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, err := range map[string]error{
+		"Save":       config.Save(),
+		"SetField":   config.SetField("HostPort", 9000),
+		"Reload":     config.Reload(),
+		"Rollback":   config.Rollback(),
+		"RefreshEnv": config.RefreshEnv(),
+		"WatchFile":  config.WatchFile(context.Background(), 0),
+	} {
+		if !errors.Is(err, ErrReadOnly) {
+			t.Fatalf(`Expected %s to return ErrReadOnly, got %v`, name, err)
+		}
+	}
+
+	ep := config.GetEndpointInfo("DEFAULT")
+	*ep.Token = "changed"
+	ep.Addresses[0] = "https://b.example.com"
+	if ep = config.GetEndpointInfo("DEFAULT"); *ep.Token != "t0ken" || ep.Addresses[0] != "https://a.example.com" {
+		t.Fatalf(`Expected the lookups to return copies, got %s %v`, *ep.Token, ep.Addresses)
+	}
+	if ep.balance == nil {
+		t.Fatal(`Expected the state of the endpoint to be kept`)
+	}
+	*config.Flag("Beta").Value = "off"
+	if !*config.Flag("Beta").Bool() {
+		t.Fatal(`Expected the flag to be a copy`)
+	}
+	// the lookups of several entries return copies too
+	*config.GetDatabaseInfos("reporting-")[0].MaxOpenConnection = 99
+	*config.GetDatabaseInfoGroup("reporting")[0].MaxOpenConnection = 99
+	if n := *config.GetDatabaseInfo("reporting-eu").MaxOpenConnection; n != 10 {
+		t.Fatalf(`Expected the databases to be copies, got %d`, n)
+	}
+
+	if config, err = Load(fn); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.SetField("HostPort", 9000); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+package cfg
+
+// redactedString encodes a value as JSON with the secrets located at the pattern replaced
+func redactedString(v any, pattern string) string {
+	return formatValue(redactEntry(v, pattern))
+}
+
+// Redacted encodes the configuration as JSON with the secrets replaced, so logging it does not leak them.
+// Unlike the entries, the configuration does not implement fmt.Stringer, which would be promoted to
+// the structs embedding it and hide their own fields.
+func (c *Configuration) Redacted() string {
+	return redactedString(c.view(), "")
+}
+
+// String encodes the endpoint as JSON with the token replaced
+func (ep EndpointInfo) String() string {
+	return redactedString(ep, "APIEndpoints[]")
+}
+
+// String encodes the API key as JSON with the key and token replaced
+func (k APIKeyInfo) String() string {
+	return redactedString(k, "APIKeys[]")
+}
+
+// String encodes the cache as JSON with the password replaced
+func (ch CacheInfo) String() string {
+	return redactedString(ch, "Cache")
+}
+
+// String encodes the database as JSON with the connection string replaced
+func (db DatabaseInfo) String() string {
+	return redactedString(db, "Databases[]")
+}
+
+// String encodes the domain as JSON with the authorized password replaced
+func (d DomainInfo) String() string {
+	return redactedString(d, "Domains[]")
+}
+
+// String encodes the JSON Web Token setting as JSON with the keys replaced
+func (j JWTInfo) String() string {
+	return redactedString(j, "JWT")
+}
+
+// String encodes the notification as JSON with the password replaced
+func (nf NotificationInfo) String() string {
+	return redactedString(nf, "Notifications[]")
+}
+
+// String encodes the OAuth provider as JSON with the client secret replaced
+func (oa OAuthProviderInfo) String() string {
+	return redactedString(oa, "OAuths[]")
+}
+
+// String encodes the proxy as JSON with the password replaced
+func (p ProxyInfo) String() string {
+	return redactedString(p, "Proxy")
+}
+
+// String encodes the webhook as JSON with the secret replaced
+func (wh WebhookInfo) String() string {
+	return redactedString(wh, "Webhooks[]")
+}
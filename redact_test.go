@@ -0,0 +1,35 @@
+package cfg
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStringRedacted(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := config.GetDatabaseInfo("DEFAULT")
+	for _, s := range []string{config.Redacted(), fmt.Sprint(db), fmt.Sprint(*db)} {
+		if strings.Contains(s, "fantastic4") {
+			t.Fatalf(`Expected the password to be redacted from %s`, s)
+		}
+		if !strings.Contains(s, redacted) {
+			t.Fatalf(`Expected the redacted marker in %s`, s)
+		}
+	}
+	ch := CacheInfo{ID: "DEFAULT", Address: "localhost:6379", Password: "s3cr3t"}
+	if s := ch.String(); s != `{"Address":"localhost:6379","DB":0,"ID":"DEFAULT","Password":"*****","Provider":""}` {
+		t.Fatalf(`Unexpected cache %s`, s)
+	}
+}
+
+func TestStringEmbedded(t *testing.T) {
+	// the configuration does not take over the formatting of the structs embedding it
+	ec := EmbeddedConfiguration{ID: "orders", Name: "Orders"}
+	if s := fmt.Sprintf("%v", ec); !strings.Contains(s, "orders") || !strings.Contains(s, "Orders") {
+		t.Fatalf(`Expected the fields of the embedding struct in %s`, s)
+	}
+}
@@ -0,0 +1,91 @@
+package cfg
+
+//go:generate go run ./internal/docgen docs_gen.go
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// defaultPattern finds the default value written on a field comment
+var defaultPattern = regexp.MustCompile(`Default is ([^ ]+(?: [^ .]+)*?)\.?(?: |$)`)
+
+// Reference returns a Markdown reference of every configuration field with its type,
+// default and description, starting from the Configuration and following its sections.
+func Reference() []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString("# Configuration reference\n")
+	seen := make(map[reflect.Type]bool)
+	queue := []reflect.Type{reflect.TypeOf(Configuration{})}
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		fmt.Fprintf(buf, "\n## %s\n\n", t.Name())
+		buf.WriteString("| Field | Type | Default | Description |\n")
+		buf.WriteString("| --- | --- | --- | --- |\n")
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Name == "FileName" {
+				continue
+			}
+			if f.Anonymous {
+				queue = append(queue, elemType(f.Type))
+				continue
+			}
+			doc := fieldDocs[t.Name()][f.Name]
+			def := ""
+			if m := defaultPattern.FindStringSubmatch(doc); m != nil {
+				def = "`" + m[1] + "`"
+			}
+			typ := typeName(f.Type)
+			if et := elemType(f.Type); et.Kind() == reflect.Struct && et.PkgPath() == t.PkgPath() {
+				typ = fmt.Sprintf("[%s](#%s)", typ, strings.ToLower(et.Name()))
+				queue = append(queue, et)
+			}
+			fmt.Fprintf(buf, "| %s | %s | %s | %s |\n", jsonName(f), typ, def, strings.ReplaceAll(doc, "|", `\|`))
+		}
+	}
+	return buf.Bytes()
+}
+
+// elemType removes pointers, slices and maps from a type
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// typeName returns the type name without pointers as optional fields are noted by the default
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + typeName(t.Elem())
+	case reflect.Map:
+		return "map[" + typeName(t.Key()) + "]" + typeName(t.Elem())
+	}
+	return t.Name()
+}
+
+// jsonName returns the key of the field in the document
+func jsonName(f reflect.StructField) string {
+	if tag, ok := f.Tag.Lookup("json"); ok {
+		if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
@@ -0,0 +1,23 @@
+package cfg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReference(t *testing.T) {
+	out := string(Reference())
+	for _, want := range []string{
+		"## Configuration\n",
+		"| Databases | [[]DatabaseInfo](#databaseinfo) |  | Configured databases for this application use |",
+		"## DatabaseInfo\n",
+		"| ParameterPlaceholder | string | `'?'` |",
+		"| CookieDomain | string | `localhost` |",
+		"## Flag\n",
+		"| key | string |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in reference", want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package cfg
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshState - time of the last load or reload, shared across reloads
+type refreshState struct {
+	sync.Mutex
+	last time.Time
+}
+
+// WithAutoRefresh reloads a remote configuration, fetched from a URL or a Source like etcd or Consul,
+// at the interval until the context is done, so it keeps itself current. Each refresh is validated and
+// swapped in like ReloadWith, and a failed refresh keeps the current configuration.
+// Local files are not refreshed, use WatchFile.
+func WithAutoRefresh(ctx context.Context, interval time.Duration) Option {
+	return func(o *options) {
+		o.refreshCtx, o.refreshEvery = ctx, interval
+	}
+}
+
+// LastRefreshed returns the time the configuration was last loaded or reloaded successfully
+func (c *Configuration) LastRefreshed() time.Time {
+	if c.refreshed == nil {
+		return time.Time{}
+	}
+	c.refreshed.Lock()
+	defer c.refreshed.Unlock()
+	return c.refreshed.last
+}
+
+// touch sets the time of the last successful load or reload
+func (c *Configuration) touch(t time.Time) {
+	if c.refreshed == nil {
+		c.refreshed = &refreshState{}
+	}
+	c.refreshed.Lock()
+	c.refreshed.last = t
+	c.refreshed.Unlock()
+}
+
+// autoRefresh starts the refresh of a remote configuration when the options set it
+func (c *Configuration) autoRefresh(o *options) {
+	if o.refreshCtx == nil || o.refreshEvery <= 0 || c.local || c.bundle != nil || strings.HasPrefix(c.FileName, envSource) {
+		return
+	}
+	go func() {
+		tk := time.NewTicker(o.refreshEvery)
+		defer tk.Stop()
+		for {
+			select {
+			case <-o.refreshCtx.Done():
+				return
+			case <-tk.C:
+			}
+			// failures are logged and observed by the reload
+			c.reload(newOptions(), nil)
+		}
+	}()
+}
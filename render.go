@@ -0,0 +1,111 @@
+package cfg
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Render encodes the effective configuration, after interpolation and defaulting, in the format.
+// Secrets are replaced with ***** when redact is set.
+func (c *Configuration) Render(f Format, redact bool) ([]byte, error) {
+	c = c.view()
+	doc, err := configDocument(c)
+	if err != nil {
+		return nil, err
+	}
+	if redact {
+		redactPattern(doc, "")
+	}
+	return encodeDocument(doc, f)
+}
+
+// Encode writes the effective configuration in the format like Render.
+// Secrets are replaced with ***** when redact is set.
+func (c *Configuration) Encode(w io.Writer, f Format, redact bool) error {
+	b, err := c.Render(f, redact)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Explain renders the effective configuration in YAML, annotating each interpolated field
+// with the environment variables it consumed and whether they were set.
+func (c *Configuration) Explain(redact bool) ([]byte, error) {
+	doc, err := configDocument(c)
+	if err != nil {
+		return nil, err
+	}
+	if redact {
+		redactPattern(doc, "")
+	}
+	notes := make(map[string]string, len(c.interpolations))
+	for _, ip := range c.interpolations {
+		vars := make([]string, 0, len(ip.Vars))
+		for _, v := range ip.Vars {
+			state := "set"
+			if !v.Set {
+				state = "unset"
+			}
+			vars = append(vars, "${"+v.Name+"} ("+state+")")
+		}
+		notes[strings.ToLower(ip.Path)] = "from " + strings.Join(vars, ", ")
+	}
+	node, err := explainNode(doc, "", notes)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(node); err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// explainNode builds a YAML node of the value with the notes as line comments
+func explainNode(v any, path string, notes map[string]string) (*yaml.Node, error) {
+	switch t := v.(type) {
+	case map[string]any:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			vn, err := explainNode(t[k], joinPath(path, k), notes)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, vn)
+		}
+		return n, nil
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for i, sv := range t {
+			vn, err := explainNode(sv, path+"["+strconv.Itoa(i)+"]", notes)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, vn)
+		}
+		return n, nil
+	}
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return nil, err
+	}
+	n.LineComment = notes[strings.ToLower(path)]
+	return n, nil
+}
@@ -0,0 +1,123 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExplain(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(fn, []byte(`{
+		"ApplicationName": "${APP_NAME}",
+		"Databases": [{"ID": "DEFAULT", "ConnectionString": "postgres://${DB_USER}:${DB_PASSWORD}@db/app"}]
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("APP_NAME", "Orders")
+	t.Setenv("DB_USER", "app")
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); db.ConnectionString != "postgres://app:@db/app" {
+		t.Fatalf(`Unexpected connection string %s`, db.ConnectionString)
+	}
+	if ips := config.Interpolations(); len(ips) != 2 || ips[1].Path != "Databases[0].ConnectionString" {
+		t.Fatalf(`Unexpected interpolations %+v`, ips)
+	}
+	b, err := config.Explain(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(b)
+	for _, want := range []string{
+		"ApplicationName: Orders # from ${APP_NAME} (set)",
+		"ConnectionString: '*****' # from ${DB_USER} (set), ${DB_PASSWORD} (unset)",
+		"CookieDomain: localhost",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in\n%s", want, out)
+		}
+	}
+	b, err = config.Render(FormatJSON, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "postgres://app:@db/app") {
+		t.Errorf("Expected effective connection string in\n%s", b)
+	}
+}
+
+func TestEncodeSymmetric(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err = config.Encode(&buf, FormatYAML, true); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); strings.Contains(s, "fantastic4") || !strings.Contains(s, "ApplicationID:") {
+		t.Fatalf(`Unexpected redacted YAML %s`, s)
+	}
+
+	for _, f := range []Format{FormatYAML, FormatTOML} {
+		b, err := config.Render(f, false)
+		if err != nil {
+			t.Fatalf(`Encoding %s: %v`, f, err)
+		}
+		doc, err := decodeDocument(b, f)
+		if err != nil {
+			t.Fatalf(`Decoding %s: %v`, f, err)
+		}
+		if b, err = json.Marshal(doc); err != nil {
+			t.Fatal(err)
+		}
+		decoded := Configuration{}
+		if err = json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf(`Decoding %s: %v`, f, err)
+		}
+		changes, err := Diff(config, &decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changes) != 0 {
+			t.Fatalf(`Expected the %s round trip to keep the configuration, got %v`, f, changes)
+		}
+	}
+}
+
+func TestEncodeEmbedded(t *testing.T) {
+	// the configuration does not take over the encoding of the structs embedding it
+	ec := EmbeddedConfiguration{ID: "orders", Name: "Orders", Configuration: Configuration{ApplicationID: new_string("orders-app")}}
+	b, err := yaml.Marshal(ec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got EmbeddedConfiguration
+	if err = yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "orders" || got.Name != "Orders" {
+		t.Fatalf(`Expected the fields of the embedding struct in %s`, b)
+	}
+	var buf bytes.Buffer
+	if err = toml.NewEncoder(&buf).Encode(ec); err != nil {
+		t.Fatal(err)
+	}
+	got = EmbeddedConfiguration{}
+	if _, err = toml.Decode(buf.String(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "orders" || got.Name != "Orders" {
+		t.Fatalf(`Expected the fields of the embedding struct in %s`, buf.String())
+	}
+}
@@ -0,0 +1,131 @@
+package cfg
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// LoadReport - hygiene report of a loaded configuration
+	LoadReport struct {
+		Warnings    []Warning // Non-fatal problems found on load
+		UnknownKeys []string  // Keys of the document that did not map to any field, like Databases[0].Shema
+		Unaccessed  []string  // Sections and entries never accessed, set only when loaded with WithAccessAudit
+		Defaulted   []string  // Fields set to their implicit defaults on load, like CookieDomain
+	}
+
+	// Warning - a non-fatal problem found on load, like an unset environment variable or a deprecated field
+	Warning struct {
+		Path    string // Path of the field
+		Message string // Description of the problem
+	}
+)
+
+// LoadWithReport loads the configuration like Load and returns the load report, so the warnings
+// can be logged prominently without failing the startup. The report is empty when the configuration
+// could not be read or decoded.
+func LoadWithReport(source string, opts ...Option) (*Configuration, LoadReport, error) {
+	config, err := Load(source, opts...)
+	if config == nil {
+		return config, LoadReport{}, err
+	}
+	return config, config.LoadReport(), err
+}
+
+// String returns the warning with its path
+func (w Warning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// LoadReport returns the warnings, the keys of the document that were ignored and the fields defaulted on load and, when loaded
+// with WithAccessAudit, the sections and entries that were not accessed so far.
+// Unlike a strict load, the report never fails the startup.
+func (c *Configuration) LoadReport() LoadReport {
+	rpt := LoadReport{
+		Warnings:    append([]Warning(nil), c.warnings...),
+		UnknownKeys: append([]string(nil), c.unknownKeys...),
+		Defaulted:   c.DefaultedFields(),
+	}
+	if ar := c.AccessReport(); ar != nil {
+		rpt.Unaccessed = ar.Unaccessed
+	}
+	return rpt
+}
+
+// DefaultedFields returns the paths of the fields set to their implicit defaults on load, like JWTSecret,
+// CookieDomain or Databases[DEFAULT].ParameterPlaceholder, so a production configuration running on fallback
+// values is noticed. It is empty when loaded with WithoutDefaults.
+func (c *Configuration) DefaultedFields() []string {
+	return append([]string(nil), c.defaulted...)
+}
+
+// reportDefaulted sets the fields defaulted between the documents before and after the defaults,
+// warning about the ones defaulted to insecure values
+func (c *Configuration) reportDefaulted(before, after map[string]any) {
+	c.defaulted = defaultedFields(before, after)
+	for _, f := range c.defaulted {
+		recordLayer(c.layers, f, Provenance{Layer: LayerDefault})
+		if f == "JWTSecret" {
+			c.warnings = append(c.warnings, Warning{Path: f, Message: "JWTSecret defaulted to an insecure value"})
+			c.log().Warn("field defaulted to an insecure value", "field", f)
+			continue
+		}
+		c.log().Info("field defaulted", "field", f)
+	}
+}
+
+// defaultedFields returns the paths of the fields that were empty before and set after
+func defaultedFields(before, after map[string]any) []string {
+	fields := make([]string, 0)
+	for _, ch := range diffDocuments(before, after) {
+		if ch.Kind == ChangeAdded || (ch.Kind == ChangeModified && (ch.Old == nil || ch.Old == "")) {
+			fields = append(fields, ch.Path)
+		}
+	}
+	return fields
+}
+
+// unknownKeys returns the keys of the document that do not map to a field of the type.
+// Keys are matched case-insensitively the way encoding/json does.
+func unknownKeys(v any, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var keys []string
+	switch vv := v.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := make(map[string]reflect.Type, t.NumField())
+			for i := 0; i < t.NumField(); i++ {
+				f := t.Field(i)
+				if !f.IsExported() || f.Tag.Get("json") == "-" {
+					continue
+				}
+				fields[strings.ToLower(jsonName(f))] = f.Type
+			}
+			for k, e := range vv {
+				ft, ok := fields[strings.ToLower(k)]
+				if !ok {
+					keys = append(keys, joinPath(path, k))
+					continue
+				}
+				keys = append(keys, unknownKeys(e, ft, joinPath(path, k))...)
+			}
+		case reflect.Map:
+			for k, e := range vv {
+				keys = append(keys, unknownKeys(e, t.Elem(), joinPath(path, k))...)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, e := range vv {
+				keys = append(keys, unknownKeys(e, t.Elem(), path+"["+strconv.Itoa(i)+"]")...)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package cfg
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadReport(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"ApplicationID": "app",
+		"Colour": "blue",
+		"Databases": [{"ID": "DEFAULT", "ConnectionString": "x", "Shema": "dbo"}],
+		"Flags": [{"key": "Beta", "value": "true", "Owner": "ops"}],
+		"Health": {"livenesspath": "/live", "Probe": 1}
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithAccessAudit())
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.GetDatabaseInfo("DEFAULT")
+
+	rpt := config.LoadReport()
+	want := []string{"Colour", "Databases[0].Shema", "Flags[0].Owner", "Health.Probe"}
+	if !reflect.DeepEqual(rpt.UnknownKeys, want) {
+		t.Fatalf("Unexpected unknown keys\nwant: %v\ngot:  %v", want, rpt.UnknownKeys)
+	}
+	want = []string{"Flags[Beta]", "Health", "JWTSecret"}
+	if !reflect.DeepEqual(rpt.Unaccessed, want) {
+		t.Fatalf("Unexpected unaccessed entries\nwant: %v\ngot:  %v", want, rpt.Unaccessed)
+	}
+	if !reflect.DeepEqual(rpt.Defaulted, config.DefaultedFields()) {
+		t.Fatalf(`Unexpected defaulted fields %v`, rpt.Defaulted)
+	}
+	defaulted := make(map[string]bool)
+	for _, f := range rpt.Defaulted {
+		defaulted[f] = true
+	}
+	for _, f := range []string{"JWTSecret", "CookieDomain", "Databases[DEFAULT].ParameterPlaceholder"} {
+		if !defaulted[f] {
+			t.Fatalf(`Expected %s to be defaulted, got %v`, f, rpt.Defaulted)
+		}
+	}
+	if defaulted["ApplicationID"] {
+		t.Fatalf(`Expected ApplicationID not to be defaulted`)
+	}
+}
+
+func TestLoadWithReport(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"JWTSecret": "secret", "ApplicationName": "${CFG_TEST_UNSET_NAME}"}`
+	if err := os.WriteFile(fn, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, rpt, err := LoadWithReport(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Warning{
+		{Path: "JWTSecret", Message: "JWTSecret is deprecated, use the JWT section"},
+		{Path: "ApplicationName", Message: "environment variable CFG_TEST_UNSET_NAME is not set"},
+	}
+	if !reflect.DeepEqual(rpt.Warnings, want) {
+		t.Fatalf("Unexpected warnings\nwant: %v\ngot:  %v", want, rpt.Warnings)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, rpt, _ = LoadWithReport(fn)
+	if len(rpt.Warnings) != 1 || rpt.Warnings[0].String() != "JWTSecret: JWTSecret defaulted to an insecure value" {
+		t.Fatalf(`Unexpected warnings %v`, rpt.Warnings)
+	}
+}
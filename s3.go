@@ -0,0 +1,245 @@
+package cfg
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+type (
+	// s3Folder - a folder of an S3 bucket, or of a compatible store like MinIO, as a key prefix.
+	// The location is s3://bucket/prefix with the region and endpoint in the query, like
+	// s3://drop/inbound?region=ap-southeast-1 or s3://drop/inbound?endpoint=http://minio:9000.
+	s3Folder struct {
+		endpoint *url.URL
+		bucket   string
+		prefix   string
+		region   string
+		access   string
+		secret   string
+		token    string
+		client   *http.Client
+		now      func() time.Time
+	}
+
+	// s3ListResult - the fields of the ListObjectsV2 result used by the folder
+	s3ListResult struct {
+		Contents []struct {
+			Key          string
+			Size         int64
+			LastModified time.Time
+		}
+		IsTruncated           bool
+		NextContinuationToken string
+	}
+)
+
+// emptyHash is the SHA-256 of an empty payload
+const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// openS3Folder opens the folder of an S3 bucket. The Username and Password of the source are the
+// access key id and secret access key, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY when not set.
+func openS3Folder(u *url.URL, si *SourceInfo) (Folder, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("s3 folder %s: bucket is not set", u.Redacted())
+	}
+	q := u.Query()
+	f := &s3Folder{
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+		region: q.Get("region"),
+		access: si.Username,
+		secret: si.Password,
+		client: http.DefaultClient,
+		now:    time.Now,
+	}
+	if f.prefix != "" {
+		f.prefix += "/"
+	}
+	if f.region == "" {
+		f.region = os.Getenv("AWS_REGION")
+	}
+	if f.region == "" {
+		f.region = "us-east-1"
+	}
+	if f.access == "" {
+		f.access, f.secret, f.token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+	}
+	ep := q.Get("endpoint")
+	if ep == "" {
+		ep = "https://s3." + f.region + ".amazonaws.com"
+	}
+	var err error
+	if f.endpoint, err = url.Parse(ep); err != nil {
+		return nil, fmt.Errorf("s3 folder %s: %w", u.Redacted(), err)
+	}
+	return f, nil
+}
+
+// List lists the objects directly under the prefix, oldest first
+func (f *s3Folder) List(ctx context.Context) ([]Pickup, error) {
+	pks := make([]Pickup, 0)
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "delimiter": {"/"}}
+		if f.prefix != "" {
+			q.Set("prefix", f.prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		res, err := f.do(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		var lr s3ListResult
+		err = xml.NewDecoder(res.Body).Decode(&lr)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range lr.Contents {
+			if strings.HasSuffix(o.Key, "/") {
+				continue
+			}
+			pks = append(pks, Pickup{Path: o.Key, Name: path.Base(o.Key), Size: o.Size, ModTime: o.LastModified})
+		}
+		if !lr.IsTruncated || lr.NextContinuationToken == "" {
+			break
+		}
+		token = lr.NextContinuationToken
+	}
+	sortPickups(pks)
+	return pks, nil
+}
+
+// Open gets the object
+func (f *s3Folder) Open(ctx context.Context, p Pickup) (io.ReadCloser, error) {
+	res, err := f.do(ctx, http.MethodGet, p.Path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Move copies the object under the prefix of the folder, in the same bucket, and deletes it
+func (f *s3Folder) Move(ctx context.Context, p Pickup, folder string) (string, error) {
+	prefix := folder
+	if isFolderURI(folder) {
+		u, err := url.Parse(folder)
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(u.Scheme, "s3") || u.Host != f.bucket {
+			return "", fmt.Errorf("%w: moving an object of bucket %s to %s", ErrUnsupportedFolder, f.bucket, u.Redacted())
+		}
+		prefix = u.Path
+	}
+	key := strings.TrimPrefix(path.Join(prefix, path.Base(p.Path)), "/")
+	src := "/" + f.bucket + "/" + s3Escape(p.Path)
+	res, err := f.do(ctx, http.MethodPut, key, nil, http.Header{"X-Amz-Copy-Source": {src}})
+	if err != nil {
+		return "", err
+	}
+	res.Body.Close()
+	if err = f.Remove(ctx, p); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// Remove deletes the object
+func (f *s3Folder) Remove(ctx context.Context, p Pickup) error {
+	res, err := f.do(ctx, http.MethodDelete, p.Path, nil, nil)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
+
+// do sends a signed request for the key of the bucket with path-style addressing.
+// Statuses other than 2xx are returned as errors, with 404 as os.ErrNotExist.
+func (f *s3Folder) do(ctx context.Context, method, key string, q url.Values, h http.Header) (*http.Response, error) {
+	u := *f.endpoint
+	u.Path = path.Join("/", f.endpoint.Path, f.bucket)
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = path.Join("/", f.endpoint.Path, f.bucket)
+	if key != "" {
+		u.RawPath += "/" + s3Escape(key)
+	}
+	u.RawQuery = s3Query(q)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range h {
+		req.Header[k] = vs
+	}
+	f.sign(req)
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		res.Body.Close()
+		if res.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("s3 %s %s: %w", method, key, os.ErrNotExist)
+		}
+		return nil, fmt.Errorf("s3 %s %s: status %d: %s", method, key, res.StatusCode, strings.TrimSpace(string(b)))
+	}
+	return res, nil
+}
+
+// sign signs the request with AWS Signature Version 4. Requests are sent without a payload.
+func (f *s3Folder) sign(req *http.Request) {
+	awsSigner{access: f.access, secret: f.secret, token: f.token, region: f.region, service: "s3"}.sign(req, emptyHash, f.now())
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the data
+func hmacSHA256(key []byte, data string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte(data))
+	return m.Sum(nil)
+}
+
+// s3Escape escapes a key by the URI encoding of AWS, keeping the slashes
+func s3Escape(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(url.QueryEscape(p), "+", "%20")
+	}
+	return strings.Join(parts, "/")
+}
+
+// s3Query encodes the query sorted by name with the URI encoding of AWS
+func s3Query(q url.Values) string {
+	if len(q) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(q))
+	for k := range q {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, k := range names {
+		for _, v := range q[k] {
+			parts = append(parts, s3Escape(k)+"="+strings.ReplaceAll(s3Escape(v), "/", "%2F"))
+		}
+	}
+	return strings.Join(parts, "&")
+}
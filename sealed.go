@@ -0,0 +1,164 @@
+package cfg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// sealedMagic starts the header line of an encrypted configuration file
+const sealedMagic = "cfg-encrypted:v1"
+
+// passphraseEnvVar is the environment variable of the passphrase when the WithPassphraseEnv option is not set
+const passphraseEnvVar = "CONFIG_PASSPHRASE"
+
+// scrypt cost of the encrypted files, taking about 32 MiB of memory
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// bounds of the scrypt cost read from the header of an encrypted file, checked before the key is derived
+const (
+	maxScryptN      = 1 << 20
+	maxScryptRP     = 64
+	maxScryptMemory = 256 << 20 // bytes taken by the derivation, 128*N*r
+)
+
+var ErrNoPassphrase = errors.New("configuration file is encrypted and no passphrase is set")
+
+// WithPassphrase sets the passphrase of an encrypted configuration file.
+// On reload, the passphrase of the loaded configuration is used when this option is not set.
+func WithPassphrase(passphrase string) Option {
+	return func(o *options) {
+		o.passphrase = passphrase
+	}
+}
+
+// WithPassphraseEnv sets the environment variable of the passphrase of an encrypted configuration file
+// instead of CONFIG_PASSPHRASE
+func WithPassphraseEnv(name string) Option {
+	return func(o *options) {
+		o.passEnv = name
+	}
+}
+
+// IsPassphraseEncrypted checks if the document is a configuration file encrypted with a passphrase
+func IsPassphraseEncrypted(b []byte) bool {
+	return bytes.HasPrefix(bytes.TrimLeft(b, " \t\r\n"), []byte(sealedMagic))
+}
+
+// EncryptWithPassphrase encrypts a whole configuration document of any format with AES-256-GCM and a key derived
+// from the passphrase with scrypt. The file starts with a header line naming the key derivation and its salt,
+// followed by the base64 encoded nonce and ciphertext.
+func EncryptWithPassphrase(passphrase string, b []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, ErrNoPassphrase
+	}
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	header := fmt.Sprintf("%s scrypt N=%d r=%d p=%d salt=%s", sealedMagic, scryptN, scryptR, scryptP, base64.RawStdEncoding.EncodeToString(salt))
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	body := base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, b, []byte(header)))
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for len(body) > 64 {
+		sb.WriteString(body[:64] + "\n")
+		body = body[64:]
+	}
+	sb.WriteString(body + "\n")
+	return []byte(sb.String()), nil
+}
+
+// DecryptWithPassphrase decrypts a configuration file encrypted by EncryptWithPassphrase with the passphrase.
+// It fails with ErrDecryptionFailed on a wrong passphrase or a tampered file.
+func DecryptWithPassphrase(passphrase string, b []byte) ([]byte, error) {
+	header, body, _ := strings.Cut(strings.TrimLeft(string(b), " \t\r\n"), "\n")
+	header = strings.TrimRight(header, "\r")
+	fields := strings.Fields(header)
+	if len(fields) < 2 || fields[0] != sealedMagic || fields[1] != "scrypt" {
+		return nil, fmt.Errorf("%w: unknown encrypted file header", ErrDecryptionFailed)
+	}
+	params := make(map[string]string)
+	for _, f := range fields[2:] {
+		if k, v, ok := strings.Cut(f, "="); ok {
+			params[k] = v
+		}
+	}
+	n, _ := strconv.Atoi(params["N"])
+	r, _ := strconv.Atoi(params["r"])
+	p, _ := strconv.Atoi(params["p"])
+	// the header is not authenticated until the key is derived, so its cost is bounded first
+	if n <= 1 || r <= 0 || p <= 0 {
+		return nil, fmt.Errorf("%w: invalid scrypt cost", ErrDecryptionFailed)
+	}
+	if n > maxScryptN || r*p > maxScryptRP || 128*int64(n)*int64(r) > maxScryptMemory {
+		return nil, fmt.Errorf("%w: scrypt cost is too large", ErrDecryptionFailed)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(params["salt"])
+	if err != nil || len(salt) == 0 {
+		return nil, fmt.Errorf("%w: invalid salt", ErrDecryptionFailed)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecryptionFailed, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(header))
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plain, nil
+}
+
+// unsealDocument decrypts the document when it is an encrypted configuration file
+// and returns the passphrase that decrypted it
+func unsealDocument(b []byte, o *options) ([]byte, string, error) {
+	if !IsPassphraseEncrypted(b) {
+		return b, "", nil
+	}
+	pass := o.passphrase
+	if pass == "" {
+		name := o.passEnv
+		if name == "" {
+			name = passphraseEnvVar
+		}
+		pass, _ = o.env()(name)
+	}
+	if pass == "" {
+		return nil, "", ErrNoPassphrase
+	}
+	plain, err := DecryptWithPassphrase(pass, b)
+	if err != nil {
+		return nil, "", err
+	}
+	return plain, pass, nil
+}
@@ -0,0 +1,67 @@
+package cfg
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSealedScryptBounds(t *testing.T) {
+	// the cost of the unauthenticated header is rejected before the key is derived
+	for _, cost := range []string{"N=1048576 r=2048 p=1", "N=4194304 r=8 p=1", "N=1024 r=8 p=1024", "N=0 r=8 p=1", "N=16384 r=-1 p=1"} {
+		b := []byte(sealedMagic + " scrypt " + cost + " salt=c2FsdHNhbHQ\nAAAA\n")
+		if _, err := DecryptWithPassphrase("secret", b); !errors.Is(err, ErrDecryptionFailed) {
+			t.Fatalf(`Expected ErrDecryptionFailed for %s, got %v`, cost, err)
+		}
+	}
+}
+
+func TestPassphraseEncryptedFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	b, err := EncryptWithPassphrase("correct horse", []byte(`{"ApplicationID": "orders"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsPassphraseEncrypted(b) || bytes.Contains(b, []byte("orders")) {
+		t.Fatalf(`Unexpected encrypted file %s`, b)
+	}
+	if _, err = DecryptWithPassphrase("wrong", b); !errors.Is(err, ErrDecryptionFailed) {
+		t.Fatalf(`Expected ErrDecryptionFailed, got %v`, err)
+	}
+	if err = os.WriteFile(fn, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Load(fn, WithLookupEnv(func(string) (string, bool) { return "", false })); !errors.Is(err, ErrNoPassphrase) {
+		t.Fatalf(`Expected ErrNoPassphrase, got %v`, err)
+	}
+	lookup := func(name string) (string, bool) {
+		if name == "EDGE_PASSPHRASE" {
+			return "correct horse", true
+		}
+		return "", false
+	}
+	config, err := Load(fn, WithPassphraseEnv("EDGE_PASSPHRASE"), WithLookupEnv(lookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" {
+		t.Fatalf(`Unexpected application id %s`, *config.ApplicationID)
+	}
+
+	config.ApplicationID = new_string("billing")
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if b, err = os.ReadFile(fn); err != nil || !IsPassphraseEncrypted(b) || bytes.Contains(b, []byte("billing")) {
+		t.Fatalf(`Expected the file to be saved encrypted, got %s, %v`, b, err)
+	}
+	if err = config.Reload(); err != nil || *config.ApplicationID != "billing" {
+		t.Fatalf(`Unexpected reload %v`, err)
+	}
+	if config, err = Load(fn, WithPassphrase("correct horse")); err != nil || *config.ApplicationID != "billing" {
+		t.Fatalf(`Unexpected load with the passphrase %v`, err)
+	}
+}
@@ -0,0 +1,215 @@
+package cfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// encryptedPrefix marks a value in the encrypted-value format
+const encryptedPrefix = "enc:v1:"
+
+var (
+	ErrInvalidKey       = errors.New("encryption key must be 32 bytes in base64, hex or raw form")
+	ErrDecryptionFailed = errors.New("decryption failed")
+)
+
+// sensitivePaths are the paths of the fields that hold secrets. [] stands for any index.
+var sensitivePaths = []string{
+	"APIEndpoints[].Signing.Secret",
+	"APIEndpoints[].Token",
+	"APIKeys[].Key",
+	"APIKeys[].Token",
+	"Cache.Password",
+	"Databases[].Connection.Password",
+	"Databases[].ConnectionString",
+	"Domains[].AuthorizedPassword",
+	"JWT.Key",
+	"JWT.Keys[].Key",
+	"JWTSecret",
+	"Notifications[].Password",
+	"OAuths[].ClientSecret",
+	"Proxy.Password",
+	"Sources[].Password",
+	"Webhooks[].Secret",
+}
+
+// IsEncrypted checks if the value is in the encrypted-value format
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, encryptedPrefix)
+}
+
+// ParseKey parses a 32 byte AES-256 key given in base64, hex or raw form
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := base64.StdEncoding.DecodeString(s); err == nil && len(b) == 32 {
+		return b, nil
+	}
+	if b, err := hex.DecodeString(s); err == nil && len(b) == 32 {
+		return b, nil
+	}
+	if len(s) == 32 {
+		return []byte(s), nil
+	}
+	return nil, ErrInvalidKey
+}
+
+// EncryptValue encrypts the value with AES-256-GCM in the encrypted-value format.
+// Values that are already encrypted are returned as is.
+func EncryptValue(key []byte, value string) (string, error) {
+	if IsEncrypted(value) {
+		return value, nil
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptValue decrypts a value in the encrypted-value format.
+// Values that are not encrypted are returned as is.
+func DecryptValue(key []byte, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, encryptedPrefix))
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrDecryptionFailed
+	}
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrDecryptionFailed
+	}
+	return string(plain), nil
+}
+
+// newGCM creates the AES-256-GCM cipher
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, ErrInvalidKey
+	}
+	blk, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(blk)
+}
+
+// EncryptDocument encrypts the sensitive fields of a configuration document in the format
+func EncryptDocument(b []byte, f Format, key []byte) ([]byte, error) {
+	return transformDocument(b, f, func(v string) (string, error) {
+		return EncryptValue(key, v)
+	})
+}
+
+// DecryptDocument decrypts the sensitive fields of a configuration document in the format
+func DecryptDocument(b []byte, f Format, key []byte) ([]byte, error) {
+	return transformDocument(b, f, func(v string) (string, error) {
+		return DecryptValue(key, v)
+	})
+}
+
+// transformDocument applies the function to the sensitive fields of a document
+func transformDocument(b []byte, f Format, fn func(string) (string, error)) ([]byte, error) {
+	doc, err := decodeDocument(b, f)
+	if err != nil {
+		return nil, err
+	}
+	err = transformSecrets(doc, "", "", func(_, v string) (string, error) {
+		return fn(v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encodeDocument(doc, f)
+}
+
+// decryptSecrets decrypts the sensitive fields of a document, returning the encrypted values as written
+// so they are saved instead of the decrypted ones
+func decryptSecrets(doc map[string]any, key []byte) ([]original, error) {
+	origs := make([]original, 0)
+	err := transformSecrets(doc, "", "", func(path, v string) (string, error) {
+		d, err := DecryptValue(key, v)
+		if err == nil && d != v {
+			origs = append(origs, original{path: path, raw: v, value: d})
+		}
+		return d, err
+	})
+	return origs, err
+}
+
+// transformSecrets walks the document and applies the function to the non-empty string values of sensitive fields.
+// The path locates the value like Databases[0].ConnectionString while the pattern matches the sensitive paths.
+func transformSecrets(v any, path, pattern string, fn func(path, value string) (string, error)) error {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, mv := range t {
+			p, pp := joinPath(path, k), joinPath(pattern, k)
+			if s, ok := mv.(string); ok && s != "" && isSensitivePath(pp) {
+				ns, err := fn(p, s)
+				if err != nil {
+					return fmt.Errorf("%s: %w", p, err)
+				}
+				t[k] = ns
+				continue
+			}
+			if err := transformSecrets(mv, p, pp, fn); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, sv := range t {
+			if err := transformSecrets(sv, path+"["+strconv.Itoa(i)+"]", pattern+"[]", fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// environmentsPath matches the overrides of an environment in a path, like .Environments.prod
+var environmentsPath = regexp.MustCompile(`(?i)\.Environments\.[^.\[]+`)
+
+// optionsPath matches the path of a provider option of a notification, like Notifications[].Options.region
+var optionsPath = regexp.MustCompile(`(?i)^Notifications\[\]\.Options\.(.+)$`)
+
+// sensitiveOptions are the words of the names of the options that hold secrets
+var sensitiveOptions = []string{"secret", "password", "token", "key"}
+
+// isSensitivePath checks if the path holds a secret. Overrides of an environment hold the secrets of the entry.
+func isSensitivePath(path string) bool {
+	path = environmentsPath.ReplaceAllString(path, "")
+	if m := optionsPath.FindStringSubmatch(path); m != nil {
+		name := strings.ToLower(m[1])
+		for _, so := range sensitiveOptions {
+			if strings.Contains(name, so) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, sp := range sensitivePaths {
+		if strings.EqualFold(sp, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptValue(t *testing.T) {
+	key, err := ParseKey(strings.Repeat("ab", 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := EncryptValue(key, "fantastic4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsEncrypted(ev) || strings.Contains(ev, "fantastic4") {
+		t.Fatalf(`Unexpected encrypted value %s`, ev)
+	}
+	if again, _ := EncryptValue(key, ev); again != ev {
+		t.Fatal(`Encrypted value was encrypted twice`)
+	}
+	dv, err := DecryptValue(key, ev)
+	if err != nil || dv != "fantastic4" {
+		t.Fatalf(`Unexpected decrypted value %s: %v`, dv, err)
+	}
+	other, _ := ParseKey(strings.Repeat("cd", 32))
+	if _, err = DecryptValue(other, ev); !errors.Is(err, ErrDecryptionFailed) {
+		t.Fatalf(`Expected ErrDecryptionFailed, got %v`, err)
+	}
+}
+
+func TestLoadEncrypted(t *testing.T) {
+	key, _ := ParseKey(strings.Repeat("ab", 32))
+	b, err := os.ReadFile("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	eb, err := EncryptDocument(b, FormatJSON, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(eb), "fantastic4") || strings.Contains(string(eb), "D0m@1nAdm1n") {
+		t.Fatal(`Sensitive values were not encrypted`)
+	}
+	if !strings.Contains(string(eb), `"Key": "Joan"`) {
+		t.Fatal(`Flag keys must not be encrypted`)
+	}
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err = os.WriteFile(fn, eb, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithDecryptionKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); !strings.Contains(db.ConnectionString, "fantastic4") {
+		t.Fatalf(`Connection string was not decrypted: %s`, db.ConnectionString)
+	}
+	// the key is kept for the reloads
+	if err = config.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); !strings.Contains(db.ConnectionString, "fantastic4") {
+		t.Fatalf(`Connection string was not decrypted on reload: %s`, db.ConnectionString)
+	}
+
+	// the values are saved encrypted as written
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	sb, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sb), "fantastic4") || strings.Contains(string(sb), "D0m@1nAdm1n") {
+		t.Fatal(`Decrypted values were saved`)
+	}
+	if config, err = Load(fn, WithDecryptionKey(key)); err != nil {
+		t.Fatal(err)
+	}
+	if db := config.GetDatabaseInfo("DEFAULT"); !strings.Contains(db.ConnectionString, "fantastic4") {
+		t.Fatalf(`Saved connection string was not decrypted: %s`, db.ConnectionString)
+	}
+}
@@ -0,0 +1,133 @@
+package cfg
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server serves the configuration over HTTP in JSON so other services can load it with Load
+// and WithBearerToken. Secrets are redacted unless the request carries one of the full tokens.
+// Responses carry an ETag and conditional requests with If-None-Match get 304 Not Modified.
+// Requests accepting text/event-stream get a change event whenever the served configuration changes,
+// which WatchEvents subscribes to.
+type Server struct {
+	Config       *Configuration // Configuration to serve
+	Tokens       []string       // Bearer tokens allowed to read the redacted configuration. Empty allows anonymous access
+	FullTokens   []string       // Bearer tokens allowed to read the full configuration with secrets
+	PollInterval time.Duration  // Interval of checking the configuration for change events. Default is 1 second
+}
+
+// ServeHTTP serves the configuration on GET and HEAD
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	token := ""
+	if auth := r.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		token = strings.TrimSpace(auth[7:])
+	}
+	redact := true
+	switch {
+	case token != "" && hasToken(s.FullTokens, token):
+		redact = false
+	case len(s.Tokens) == 0 || token != "" && hasToken(s.Tokens, token):
+	default:
+		w.Header().Set("WWW-Authenticate", `Bearer realm="config"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		s.serveEvents(w, r, redact)
+		return
+	}
+	b, etag, err := s.render(redact)
+	if err != nil {
+		s.Config.view().log().Warn("configuration render failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", "private, no-cache")
+	h.Set("Vary", "Authorization")
+	for _, m := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if m = strings.TrimSpace(m); m == etag || m == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+	h.Set("Content-Type", "application/json")
+	if r.Method == http.MethodHead {
+		return
+	}
+	w.Write(b)
+}
+
+// hasToken checks if the token is one of the tokens in constant time
+func hasToken(tokens []string, token string) bool {
+	found := 0
+	for _, t := range tokens {
+		found |= subtle.ConstantTimeCompare([]byte(t), []byte(token))
+	}
+	return found == 1
+}
+
+// render renders the configuration and its ETag
+func (s *Server) render(redact bool) ([]byte, string, error) {
+	b, err := s.Config.Render(FormatJSON, redact)
+	if err != nil {
+		return nil, "", err
+	}
+	sum := sha256.Sum256(b)
+	return b, `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// serveEvents streams a ready event with the ETag of the configuration, then a change event
+// with the new ETag whenever it changes, until the client disconnects
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request, redact bool) {
+	fl, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusNotImplemented)
+		return
+	}
+	_, etag, err := s.render(redact)
+	if err != nil {
+		s.Config.view().log().Warn("configuration render failed", "error", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, "event: ready\ndata: %s\n\n", etag)
+	fl.Flush()
+
+	interval := s.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	tk := time.NewTicker(interval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-tk.C:
+			_, cur, err := s.render(redact)
+			if err != nil || cur == etag {
+				continue
+			}
+			etag = cur
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", etag)
+			fl.Flush()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package cfg
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	config, err := Load("samples/config.mssql.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(&Server{Config: config, Tokens: []string{"reader"}, FullTokens: []string{"admin"}})
+	defer srv.Close()
+
+	get := func(token, etag string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	if res := get("", ""); res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf(`Expected 401 without token, got %d`, res.StatusCode)
+	}
+	res := get("reader", "")
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(b), redacted) || strings.Contains(string(b), "fantastic4") {
+		t.Fatalf(`Expected a redacted configuration, got %d %s`, res.StatusCode, b)
+	}
+	etag := res.Header.Get("ETag")
+	if res = get("reader", etag); res.StatusCode != http.StatusNotModified {
+		t.Fatalf(`Expected 304 on matching ETag, got %d`, res.StatusCode)
+	}
+	if res = get("admin", etag); res.StatusCode != http.StatusOK || res.Header.Get("ETag") == etag {
+		t.Fatalf(`Expected the full configuration with another ETag, got %d`, res.StatusCode)
+	}
+	res.Body.Close()
+
+	remote, err := Load(srv.URL, WithBearerToken("admin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db := remote.GetDatabaseInfo("DEFAULT"); db == nil || db.ConnectionString != config.GetDatabaseInfo("DEFAULT").ConnectionString {
+		t.Fatalf(`Unexpected database loaded from the server %+v`, db)
+	}
+}
@@ -0,0 +1,233 @@
+package cfg
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+type (
+	// sftpFolder - a folder of an SFTP server. The location is sftp://host:22/path, with the host key checked
+	// against the SHA256 fingerprint in the query, like sftp://files/inbound?fingerprint=SHA256:..., or else
+	// against the known_hosts file in the query or ~/.ssh/known_hosts. Each call connects to the server.
+	sftpFolder struct {
+		addr   string
+		dir    string
+		config *ssh.ClientConfig
+	}
+
+	// sftpConn - a connection to an SFTP server
+	sftpConn struct {
+		*sftp.Client
+		conn *ssh.Client
+	}
+
+	// sftpFile - a file of an SFTP server, closing its connection when closed
+	sftpFile struct {
+		*sftp.File
+		sc *sftpConn
+	}
+)
+
+// sftpTimeout is the time to connect to an SFTP server
+const sftpTimeout = 30 * time.Second
+
+// openSFTPFolder opens the folder of an SFTP server. The Username and Password of the source, or else the
+// user of the location, sign in, with the private key file in the key query parameter when set.
+func openSFTPFolder(u *url.URL, si *SourceInfo) (Folder, error) {
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("sftp folder %s: host is not set", u.Redacted())
+	}
+	user, pass := si.Username, si.Password
+	if user == "" && u.User != nil {
+		user = u.User.Username()
+		pass, _ = u.User.Password()
+	}
+	if user == "" {
+		return nil, fmt.Errorf("sftp folder %s: user is not set", u.Redacted())
+	}
+	q := u.Query()
+	auth := make([]ssh.AuthMethod, 0, 2)
+	if kf := q.Get("key"); kf != "" {
+		b, err := os.ReadFile(kf)
+		if err != nil {
+			return nil, fmt.Errorf("sftp folder %s: %w", u.Redacted(), err)
+		}
+		signer, err := ssh.ParsePrivateKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("sftp folder %s: %w", u.Redacted(), err)
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if pass != "" {
+		auth = append(auth, ssh.Password(pass))
+	}
+	hostKey, err := sftpHostKey(q)
+	if err != nil {
+		return nil, fmt.Errorf("sftp folder %s: %w", u.Redacted(), err)
+	}
+	dir := u.Path
+	if dir == "" {
+		dir = "."
+	}
+	return &sftpFolder{
+		addr:   sftpAddr(u),
+		dir:    dir,
+		config: &ssh.ClientConfig{User: user, Auth: auth, HostKeyCallback: hostKey, Timeout: sftpTimeout},
+	}, nil
+}
+
+// sftpAddr returns the address of the server, on port 22 unless set
+func sftpAddr(u *url.URL) string {
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), "22")
+	}
+	return u.Host
+}
+
+// sftpHostKey returns the check of the host key, by the fingerprint or else by the known hosts
+func sftpHostKey(q url.Values) (ssh.HostKeyCallback, error) {
+	if fp := q.Get("fingerprint"); fp != "" {
+		return func(host string, remote net.Addr, key ssh.PublicKey) error {
+			if ssh.FingerprintSHA256(key) != fp {
+				return fmt.Errorf("host key of %s does not match the fingerprint", host)
+			}
+			return nil
+		}, nil
+	}
+	kh := q.Get("known_hosts")
+	if kh == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		kh = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	return knownhosts.New(kh)
+}
+
+// connect connects to the server
+func (f *sftpFolder) connect(ctx context.Context) (*sftpConn, error) {
+	d := net.Dialer{Timeout: f.config.Timeout}
+	nc, err := d.DialContext(ctx, "tcp", f.addr)
+	if err != nil {
+		return nil, err
+	}
+	c, chans, reqs, err := ssh.NewClientConn(nc, f.addr, f.config)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+	conn := ssh.NewClient(c, chans, reqs)
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &sftpConn{Client: client, conn: conn}, nil
+}
+
+// Close closes the client and its connection
+func (sc *sftpConn) Close() error {
+	sc.Client.Close()
+	return sc.conn.Close()
+}
+
+// Close closes the file and its connection
+func (sf *sftpFile) Close() error {
+	err := sf.File.Close()
+	if cerr := sf.sc.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// List lists the regular files directly in the folder, oldest first
+func (f *sftpFolder) List(ctx context.Context) ([]Pickup, error) {
+	sc, err := f.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer sc.Close()
+	fis, err := sc.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	pks := make([]Pickup, 0, len(fis))
+	for _, fi := range fis {
+		if !fi.Mode().IsRegular() {
+			continue
+		}
+		pks = append(pks, Pickup{Path: path.Join(f.dir, fi.Name()), Name: fi.Name(), Size: fi.Size(), ModTime: fi.ModTime()})
+	}
+	sortPickups(pks)
+	return pks, nil
+}
+
+// Open opens the file, connected until it is closed
+func (f *sftpFolder) Open(ctx context.Context, p Pickup) (io.ReadCloser, error) {
+	sc, err := f.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	file, err := sc.Open(p.Path)
+	if err != nil {
+		sc.Close()
+		return nil, err
+	}
+	return &sftpFile{File: file, sc: sc}, nil
+}
+
+// Move renames the file into the folder on the same server, with a unique name when the folder has a file of the name
+func (f *sftpFolder) Move(ctx context.Context, p Pickup, folder string) (string, error) {
+	dir := folder
+	if isFolderURI(folder) {
+		u, err := url.Parse(folder)
+		if err != nil {
+			return "", err
+		}
+		if !strings.EqualFold(u.Scheme, "sftp") || !strings.EqualFold(sftpAddr(u), f.addr) {
+			return "", fmt.Errorf("%w: moving a file of %s to %s", ErrUnsupportedFolder, f.addr, u.Redacted())
+		}
+		dir = u.Path
+	}
+	sc, err := f.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer sc.Close()
+	if err = sc.MkdirAll(dir); err != nil {
+		return "", err
+	}
+	base := path.Base(p.Path)
+	dst := path.Join(dir, base)
+	if _, err = sc.Stat(dst); err == nil {
+		ext := path.Ext(base)
+		dst = path.Join(dir, fmt.Sprintf("%s-%d%s", strings.TrimSuffix(base, ext), time.Now().UnixNano(), ext))
+	}
+	if err = sc.Rename(p.Path, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// Remove removes the file
+func (f *sftpFolder) Remove(ctx context.Context, p Pickup) error {
+	sc, err := f.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	return sc.Client.Remove(p.Path)
+}
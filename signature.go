@@ -0,0 +1,80 @@
+package cfg
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/url"
+)
+
+// signatureVerifier checks the signature of the document
+type signatureVerifier func(doc, sig []byte) bool
+
+var ErrInvalidSignature = errors.New("configuration signature is missing or invalid")
+
+// SignatureSuffix is appended to the source to get its detached signature, like config.json.sig
+const SignatureSuffix = ".sig"
+
+// WithHMACSignature verifies the HMAC-SHA256 detached signature of the document with the secret
+// before parsing. The signature is read from the source with the .sig suffix, in raw, hex or base64 form.
+// On reload, the verification of the loaded configuration is used when this option is not set.
+func WithHMACSignature(secret []byte) Option {
+	return func(o *options) {
+		o.verifier = func(doc, sig []byte) bool {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write(doc)
+			return hmac.Equal(mac.Sum(nil), sig)
+		}
+	}
+}
+
+// WithEd25519Signature verifies the Ed25519 detached signature of the document with the public key
+// before parsing. The signature is read from the source with the .sig suffix, in raw, hex or base64 form.
+// On reload, the verification of the loaded configuration is used when this option is not set.
+func WithEd25519Signature(pub ed25519.PublicKey) Option {
+	return func(o *options) {
+		o.verifier = func(doc, sig []byte) bool {
+			return len(pub) == ed25519.PublicKeySize && ed25519.Verify(pub, doc, sig)
+		}
+	}
+}
+
+// verifySource reads the detached signature of the source and verifies the document with it
+func verifySource(ctx context.Context, source string, local bool, doc []byte, o *options) error {
+	sigSource := source + SignatureSuffix
+	if !local {
+		u, err := url.Parse(source)
+		if err != nil {
+			return wrapError(ErrInvalidSignature, err)
+		}
+		u.Path += SignatureSuffix
+		sigSource = u.String()
+	}
+	sig, err := readSource(ctx, sigSource, local, o, Limits{MaxSize: 1 << 10})
+	if err != nil {
+		return wrapError(ErrInvalidSignature, err)
+	}
+	if !o.verifier(doc, decodeSignature(sig)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// decodeSignature decodes a signature in hex or base64 form, or returns it as is
+func decodeSignature(b []byte) []byte {
+	s := string(bytes.TrimSpace(b))
+	if d, err := hex.DecodeString(s); err == nil {
+		return d
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if d, err := enc.DecodeString(s); err == nil {
+			return d
+		}
+	}
+	return b
+}
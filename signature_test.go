@@ -0,0 +1,115 @@
+package cfg
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadHMACSignature(t *testing.T) {
+	secret := []byte("publisher secret")
+	doc := []byte(`{"ApplicationID": "orders"}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(doc)
+	sig := hex.EncodeToString(mac.Sum(nil)) + "\n"
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, doc, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(fn, WithHMACSignature(secret)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature on missing signature, got %v`, err)
+	}
+	if err := os.WriteFile(fn+".sig", []byte(sig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn, WithHMACSignature(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" {
+		t.Fatalf(`Unexpected application id %q`, *config.ApplicationID)
+	}
+	if _, err = Load(fn, WithHMACSignature([]byte("other"))); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature with another secret, got %v`, err)
+	}
+
+	if err = os.WriteFile(fn, []byte(`{"ApplicationID": "evil"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Reload(); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected the verification to be kept on reload, got %v`, err)
+	}
+	if *config.ApplicationID != "orders" {
+		t.Fatalf(`Tampered configuration was applied: %q`, *config.ApplicationID)
+	}
+}
+
+func TestLoadEd25519Signature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := []byte(`{"ApplicationID": "orders"}`)
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, doc))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.json":
+			w.Write(doc)
+		case "/config.json.sig":
+			w.Write([]byte(sig))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	config, err := Load(srv.URL+"/config.json?env=prod", WithEd25519Signature(pub))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.ApplicationID != "orders" {
+		t.Fatalf(`Unexpected application id %q`, *config.ApplicationID)
+	}
+	other, _, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err = Load(srv.URL+"/config.json", WithEd25519Signature(other)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature with another key, got %v`, err)
+	}
+}
+
+func TestUnverifiableSignature(t *testing.T) {
+	secret := []byte("publisher secret")
+	src := &memorySource{doc: `{"ApplicationID": "orders"}`, changes: make(chan struct{})}
+	if _, err := LoadSource(context.Background(), src, WithHMACSignature(secret)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature from a source, got %v`, err)
+	}
+
+	doc := []byte(`{"ApplicationID": "orders"}`)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(doc)
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, doc, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(fn+".sig", []byte(hex.EncodeToString(mac.Sum(nil))), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewLoader(WithHMACSignature(secret)).File(fn).Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SIGTEST_APPLICATIONID", "evil")
+	_, err := NewLoader(WithHMACSignature(secret)).File(fn).Env("SIGTEST_").Load(context.Background())
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf(`Expected ErrInvalidSignature from an environment layer, got %v`, err)
+	}
+}
@@ -0,0 +1,185 @@
+package cfg
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Types of the signing of the requests to an endpoint
+const (
+	SigningHMAC  = "HMAC"      // Signs the body with a shared secret
+	SigningAWSV4 = "AWS-SIGV4" // Signs the request with AWS Signature Version 4
+)
+
+type (
+	// awsSigner - credential and scope of AWS Signature Version 4
+	awsSigner struct {
+		access  string
+		secret  string
+		token   string
+		region  string
+		service string
+	}
+
+	// signingTransport - round tripper signing the requests by the signing of an endpoint
+	signingTransport struct {
+		base    http.RoundTripper
+		signing SigningInfo
+		now     func() time.Time
+	}
+)
+
+// RoundTripper returns a round tripper signing the requests by the Signing of the endpoint before sending them
+// with the base, or the base when the endpoint has no Signing. A nil base is the default transport.
+func (ep EndpointInfo) RoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if ep.Signing == nil {
+		return base
+	}
+	return &signingTransport{base: base, signing: *ep.Signing, now: time.Now}
+}
+
+// RoundTrip signs a copy of the request and sends it
+func (t *signingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	req := r.Clone(r.Context())
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+	sg := t.signing
+	switch strings.ToUpper(sg.Type) {
+	case SigningHMAC:
+		newHash, err := hmacHash(sg.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		mac := hmac.New(newHash, []byte(sg.Secret))
+		mac.Write(body)
+		header := sg.Header
+		if header == "" {
+			header = "X-Signature"
+		}
+		req.Header.Set(header, hex.EncodeToString(mac.Sum(nil)))
+	case SigningAWSV4:
+		sum := sha256.Sum256(body)
+		sg.awsSigner().sign(req, hex.EncodeToString(sum[:]), t.now())
+	default:
+		return nil, fmt.Errorf("signing type %s is not supported", sg.Type)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// readBody reads and closes the body of the request
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
+
+// hmacHash returns the hash of the HMAC algorithm
+func hmacHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case "", "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	case "SHA1":
+		return sha1.New, nil
+	}
+	return nil, fmt.Errorf("signing algorithm %s is not supported", algorithm)
+}
+
+// awsSigner returns the signer of the credential and scope, from the environment when not set
+func (sg SigningInfo) awsSigner() awsSigner {
+	s := awsSigner{access: sg.KeyID, secret: sg.Secret, region: sg.Region, service: sg.Service}
+	if s.access == "" && s.secret == "" {
+		s.access, s.secret, s.token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if s.region == "" {
+		s.region = os.Getenv("AWS_REGION")
+	}
+	if s.region == "" {
+		s.region = "us-east-1"
+	}
+	return s
+}
+
+// sign signs the request with AWS Signature Version 4 with the SHA-256 of its payload in hex
+func (s awsSigner) sign(req *http.Request, payloadHash string, now time.Time) {
+	t := now.UTC()
+	amzDate := t.Format("20060102T150405Z")
+	day := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.token != "" {
+		req.Header.Set("X-Amz-Security-Token", s.token)
+	}
+	if s.access == "" {
+		// anonymous access like to a public bucket
+		return
+	}
+
+	names := []string{"host"}
+	for k := range req.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-amz-") || lk == "content-type" {
+			names = append(names, lk)
+		}
+	}
+	sort.Strings(names)
+	var ch strings.Builder
+	for _, n := range names {
+		v := req.URL.Host
+		if n != "host" {
+			v = strings.TrimSpace(req.Header.Get(n))
+		}
+		ch.WriteString(n + ":" + v + "\n")
+	}
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if s.service != "s3" {
+		// the path of the services other than S3 is encoded twice
+		path = s3Escape(path)
+	}
+	signed := strings.Join(names, ";")
+	canonical := strings.Join([]string{
+		req.Method,
+		path,
+		s3Query(req.URL.Query()),
+		ch.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + s.region + "/" + s.service + "/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+	key := hmacSHA256([]byte("AWS4"+s.secret), day)
+	for _, p := range []string{s.region, s.service, "aws4_request"} {
+		key = hmacSHA256(key, p)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.access, scope, signed, hex.EncodeToString(hmacSHA256(key, toSign))))
+}
@@ -0,0 +1,85 @@
+package cfg
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigning(t *testing.T) {
+	var (
+		header, auth, date string
+		body               []byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, auth, date = r.Header.Get("X-Hub-Signature"), r.Header.Get("Authorization"), r.Header.Get("X-Amz-Date")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	config := Configuration{
+		APIEndpoints: &[]EndpointInfo{
+			{ID: "hooks", Address: srv.URL, Signing: &SigningInfo{Type: "hmac", Header: "X-Hub-Signature", Algorithm: "SHA512", Secret: "s3cret"}},
+			{ID: "search", Address: srv.URL, Signing: &SigningInfo{Type: SigningAWSV4, KeyID: "AKIDEXAMPLE", Secret: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", Region: "ap-southeast-1", Service: "es"}},
+			{ID: "plain", Address: srv.URL},
+		},
+	}
+	client, err := config.EndpointClient("hooks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.Post(srv.URL, "application/json", strings.NewReader(`{"event":"order.created"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	mac := hmac.New(sha512.New, []byte("s3cret"))
+	mac.Write([]byte(`{"event":"order.created"}`))
+	if header != hex.EncodeToString(mac.Sum(nil)) || string(body) != `{"event":"order.created"}` {
+		t.Fatalf(`Unexpected signature %s of %s`, header, body)
+	}
+
+	if client, err = config.EndpointClient("search"); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = client.Get(srv.URL + "/orders/_search?q=status:new"); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(auth, "/ap-southeast-1/es/aws4_request") || date == "" {
+		t.Fatalf(`Unexpected signature %s`, auth)
+	}
+	if config.GetEndpointInfo("plain").RoundTripper(nil) != http.DefaultTransport {
+		t.Fatal(`Expected the base transport without signing`)
+	}
+
+	if err = config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	(*config.APIEndpoints)[1].Signing.Service = ""
+	(*config.APIEndpoints)[0].Signing.Algorithm = "MD5"
+	if err = config.Validate(); !errors.Is(err, ErrRequired) || !errors.Is(err, ErrInvalidEnum) {
+		t.Fatalf(`Expected ErrRequired and ErrInvalidEnum, got %v`, err)
+	}
+}
+
+func TestAWSSignature(t *testing.T) {
+	// the credential and scope of the get-vanilla example of the Signature Version 4 test suite
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := awsSigner{access: "AKIDEXAMPLE", secret: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", region: "us-east-1", service: "service"}
+	s.sign(req, emptyHash, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, "
+	if got := req.Header.Get("Authorization"); !strings.HasPrefix(got, want) {
+		t.Fatalf(`Unexpected signature %s`, got)
+	}
+}
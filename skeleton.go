@@ -0,0 +1,17 @@
+package cfg
+
+import (
+	_ "embed"
+)
+
+//go:embed skeleton.yaml
+var skeleton []byte
+
+// Skeleton returns a template configuration with every section and its defaults.
+// The YAML template is commented. Comments are dropped on other formats.
+func Skeleton(f Format) ([]byte, error) {
+	if f == FormatYAML {
+		return append([]byte(nil), skeleton...), nil
+	}
+	return Convert(skeleton, FormatYAML, f)
+}
@@ -0,0 +1,51 @@
+package cfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	b, err := Skeleton(FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The skeleton must map to the configuration fields only
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&Configuration{}); err != nil {
+		t.Fatalf(`Skeleton has fields unknown to the configuration: %v`, err)
+	}
+	// The skeleton must have every section
+	doc, err := decodeDocument(b, FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := reflect.TypeOf(Configuration{})
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		switch {
+		case !f.IsExported(), f.Name == "FileName", f.Name == "JWTSecret":
+			continue
+		}
+		if _, ok := doc[f.Name]; !ok {
+			t.Errorf(`Skeleton is missing %s`, f.Name)
+		}
+	}
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err = os.WriteFile(fn, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("JWT_KEY", "key")
+	c, err := Load(fn)
+	if err != nil {
+		t.Fatalf(`Skeleton does not load: %v`, err)
+	}
+	if err = c.Validate(); err != nil {
+		t.Fatalf(`Skeleton is not valid: %v`, err)
+	}
+}
@@ -0,0 +1,75 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type (
+	// Source fetches the configuration document from a custom location like a configuration service
+	Source interface {
+		String() string                            // Name of the source, set as the file name of the configuration
+		Fetch(ctx context.Context) ([]byte, error) // Fetches the document in JSON
+	}
+
+	// SourceWatcher is implemented by sources that push change notifications.
+	// Watch calls changed on each change until the context is done or the stream fails.
+	SourceWatcher interface {
+		Watch(ctx context.Context, changed func()) error
+	}
+)
+
+// LoadSource loads the configuration from the source. Defaults and validation apply like Load.
+// The configuration reloads from the source, can be watched with WatchEvents when the source
+// implements SourceWatcher, and can not be saved. Signature options are rejected with
+// ErrInvalidSignature, since a source has no detached signature.
+func LoadSource(ctx context.Context, src Source, opts ...Option) (*Configuration, error) {
+	o := newOptions(opts...)
+	start := o.now()
+	config, err := loadSource(ctx, src, o)
+	if config == nil {
+		(&Configuration{instrumentation: o.instrumentation, clock: o.clock}).observe(EventLoad, src.String(), start, err)
+		return nil, err
+	}
+	if o.audit {
+		config.audit = &accessAudit{counts: make(map[string]uint64)}
+	}
+	if err == nil && o.history != nil {
+		config.history = o.history
+		config.snapshot(EventLoad)
+	}
+	if err == nil {
+		config.touch(o.now())
+	}
+	config.observe(EventLoad, src.String(), start, err)
+	if err == nil {
+		// started last, since the refreshes swap the configuration
+		config.autoRefresh(o)
+	}
+	return config, err
+}
+
+// loadSource fetches the document of the source and parses it
+func loadSource(ctx context.Context, src Source, o *options) (*Configuration, error) {
+	if o.verifier != nil {
+		// a source has no detached signature to verify the document with
+		return nil, wrapError(ErrInvalidSignature, fmt.Errorf("source %s can not be verified", src))
+	}
+	b, err := src.Fetch(ctx)
+	if err != nil {
+		if errors.Is(err, ErrRemoteFetch) {
+			return nil, err
+		}
+		return nil, wrapError(ErrRemoteFetch, err)
+	}
+	config := newConfiguration(o)
+	config.source = src
+	if len(b) == 0 {
+		return config, ErrNoDataFromSource
+	}
+	if lim := o.limits.withDefaults(); lim.MaxSize >= 0 && int64(len(b)) > lim.MaxSize {
+		return nil, fmt.Errorf("%w: document is larger than %d bytes", ErrLimitExceeded, lim.MaxSize)
+	}
+	return parse(config, src.String(), b, o)
+}
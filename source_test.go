@@ -0,0 +1,114 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySource - a source pushing changes of an in-memory document
+type memorySource struct {
+	mu      sync.Mutex
+	doc     string
+	changes chan struct{}
+}
+
+func (s *memorySource) String() string {
+	return "memory"
+}
+
+func (s *memorySource) Fetch(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return []byte(s.doc), nil
+}
+
+func (s *memorySource) Watch(ctx context.Context, changed func()) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.changes:
+			changed()
+		}
+	}
+}
+
+func (s *memorySource) set(doc string) {
+	s.mu.Lock()
+	s.doc = doc
+	s.mu.Unlock()
+	s.changes <- struct{}{}
+}
+
+func TestLoadSource(t *testing.T) {
+	src := &memorySource{doc: `{"HostPort": 8000}`, changes: make(chan struct{})}
+	reloaded := make(chan int, 1)
+	config, err := LoadSource(context.Background(), src, WithReloadCheck(func(c *Configuration) error {
+		reloaded <- *c.HostPort
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostPort != 8000 || config.FileName != "memory" {
+		t.Fatalf(`Unexpected configuration %d %s`, *config.HostPort, config.FileName)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- config.WatchEvents(ctx)
+	}()
+	src.set(`{"HostPort": 8080}`)
+	select {
+	case port := <-reloaded:
+		if port != 8080 {
+			t.Fatalf(`Unexpected port %d`, port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal(`Expected a reload on the change`)
+	}
+	cancel()
+	if err = <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected the watch to stop on cancel, got %v`, err)
+	}
+	if err = config.Save(); !errors.Is(err, ErrSaveNotLocalFile) {
+		t.Fatalf(`Expected ErrSaveNotLocalFile, got %v`, err)
+	}
+}
+
+func TestAutoRefresh(t *testing.T) {
+	src := &memorySource{doc: `{"HostPort": 8000}`}
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reloaded := make(chan int, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	config, err := LoadSource(context.Background(), src, WithClock(func() time.Time { return clock }), WithAutoRefresh(ctx, 10*time.Millisecond), WithReloadCheck(func(c *Configuration) error {
+		select {
+		case reloaded <- *c.HostPort:
+		default:
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.LastRefreshed().Equal(clock) {
+		t.Fatalf(`Unexpected last refresh %v`, config.LastRefreshed())
+	}
+
+	src.mu.Lock()
+	src.doc = `{"HostPort": 8080}`
+	src.mu.Unlock()
+	deadline := time.After(2 * time.Second)
+	for port := 0; port != 8080; {
+		select {
+		case port = <-reloaded:
+		case <-deadline:
+			t.Fatal(`Expected the configuration to be refreshed`)
+		}
+	}
+}
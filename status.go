@@ -0,0 +1,51 @@
+package cfg
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// statusSnippet is the length of the response body included in the errors of the status
+const statusSnippet = 200
+
+// fetchClient returns the client fetching remote configurations, following the redirects of the options
+func (o *options) fetchClient() *http.Client {
+	client := httpClient(o.proxy, o.transport)
+	if o.redirects == nil {
+		return client
+	}
+	max := *o.redirects
+	return &http.Client{
+		Transport: client.Transport,
+		Timeout:   client.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > max {
+				// the redirect response is returned and rejected by its status
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+}
+
+// checkStatus rejects the responses with a status other than 2xx, with the beginning of the body
+func checkStatus(res *http.Response) error {
+	if res.StatusCode/100 == 2 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(res.Body, statusSnippet))
+	for len(b) > 0 && !utf8.Valid(b) {
+		b = b[:len(b)-1]
+	}
+	snippet := strings.Join(strings.Fields(string(b)), " ")
+	if loc := res.Header.Get("Location"); loc != "" && res.StatusCode/100 == 3 {
+		snippet = "redirected to " + loc
+	}
+	if snippet == "" {
+		return fmt.Errorf("%w: %s", ErrHTTPStatus, res.Status)
+	}
+	return fmt.Errorf("%w: %s: %s", ErrHTTPStatus, res.Status, snippet)
+}
@@ -0,0 +1,52 @@
+package cfg
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoadStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config.json":
+			w.Write([]byte(`{"ApplicationID": "orders"}`))
+		case "/moved":
+			http.Redirect(w, r, "/config.json", http.StatusFound)
+		case "/unavailable":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error": "maintenance"}`))
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":   "no such configuration"}`))
+		}
+	}))
+	defer srv.Close()
+
+	_, err := Load(srv.URL + "/missing.json")
+	if !errors.Is(err, ErrHTTPStatus) || !errors.Is(err, ErrRemoteFetch) {
+		t.Fatalf(`Expected ErrHTTPStatus, got %v`, err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "404 Not Found") || !strings.Contains(msg, `{"message": "no such configuration"}`) {
+		t.Fatalf(`Expected the status and the body in the error, got %s`, msg)
+	}
+	if _, err = Load(srv.URL + "/unavailable"); !errors.Is(err, ErrHTTPStatus) || !strings.Contains(err.Error(), "503") {
+		t.Fatalf(`Expected ErrHTTPStatus, got %v`, err)
+	}
+
+	config, err := Load(srv.URL + "/moved")
+	if err != nil || *config.ApplicationID != "orders" {
+		t.Fatalf(`Expected the redirect to be followed, got %v`, err)
+	}
+	_, err = Load(srv.URL+"/moved", WithRedirects(0))
+	if !errors.Is(err, ErrHTTPStatus) || !strings.Contains(err.Error(), "redirected to /config.json") {
+		t.Fatalf(`Expected the redirect to be rejected, got %v`, err)
+	}
+	if _, err = Load(srv.URL+"/moved", WithRedirects(1)); err != nil {
+		t.Fatalf(`Expected one redirect to be followed, got %v`, err)
+	}
+}
@@ -0,0 +1,158 @@
+package cfg
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type (
+	// templateData - data of the templates in configuration values
+	templateData struct {
+		Env map[string]string // Environment variables referenced by the templates
+		Cfg map[string]any    // The document after the ${NAME} interpolation
+	}
+
+	// original - a value as written, replaced on load
+	original struct {
+		path  string // Path of the field like Databases[0].ConnectionString
+		raw   string // The value as written
+		value string // The value after load
+	}
+)
+
+// templateEnvPattern matches the .Env.NAME references of templates
+var templateEnvPattern = regexp.MustCompile(`\.Env\.([A-Za-z_][A-Za-z0-9_]*)`)
+
+// WithTemplates evaluates the values with text/template actions like {{ .Env.NAME }} or {{ .Cfg.HostExternalURL }}
+// on load, after the ${NAME} interpolation. .Cfg is the document before the templates are evaluated.
+// The functions env, default, upper, lower, trim, replace, base64dec and base64enc are available.
+// Save writes the templates back for the values that were not changed.
+// On reload, the templates are evaluated when the loaded configuration had them evaluated.
+func WithTemplates() Option {
+	return func(o *options) {
+		o.templates = true
+	}
+}
+
+// templateFuncs returns the functions of the templates
+func templateFuncs(lookup func(string) (string, bool)) template.FuncMap {
+	return template.FuncMap{
+		"env": func(name string) string {
+			v, _ := lookup(name)
+			return v
+		},
+		"default": func(def, v any) any {
+			if v == nil || v == "" {
+				return def
+			}
+			return v
+		},
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"trim":    strings.TrimSpace,
+		"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"base64dec": func(s string) (string, error) {
+			return pipeFuncs["base64dec"](s, "")
+		},
+		"base64enc": func(s string) string {
+			v, _ := pipeFuncs["base64enc"](s, "")
+			return v
+		},
+	}
+}
+
+// templateDocument evaluates the templates of every string value of a document and returns the originals
+func templateDocument(doc map[string]any, lookup func(string) (string, bool)) ([]original, error) {
+	data := templateData{Env: make(map[string]string), Cfg: doc}
+	origs := make([]original, 0)
+	walkStrings(doc, "", func(path, s string) (string, error) {
+		if strings.Contains(s, "{{") {
+			for _, m := range templateEnvPattern.FindAllStringSubmatch(s, -1) {
+				data.Env[m[1]], _ = lookup(m[1])
+			}
+		}
+		return s, nil
+	})
+	// the templates see the document as written
+	values := make(map[string]string)
+	err := walkStrings(doc, "", func(path, s string) (string, error) {
+		if !strings.Contains(s, "{{") {
+			return s, nil
+		}
+		tpl, err := template.New(path).Option("missingkey=error").Funcs(templateFuncs(lookup)).Parse(s)
+		if err != nil {
+			return s, err
+		}
+		var sb strings.Builder
+		if err = tpl.Execute(&sb, data); err != nil {
+			return s, err
+		}
+		values[path] = sb.String()
+		origs = append(origs, original{path: path, raw: s, value: sb.String()})
+		return s, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	walkStrings(doc, "", func(path, s string) (string, error) {
+		if v, ok := values[path]; ok {
+			return v, nil
+		}
+		return s, nil
+	})
+	sort.Slice(origs, func(i, j int) bool {
+		return origs[i].path < origs[j].path
+	})
+	return origs, nil
+}
+
+// walkStrings replaces every string value of a document by the result of the function, stopping on an error
+func walkStrings(v any, path string, fn func(path, s string) (string, error)) error {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, mv := range t {
+			if s, ok := mv.(string); ok {
+				ns, err := fn(joinPath(path, k), s)
+				if err != nil {
+					return err
+				}
+				t[k] = ns
+				continue
+			}
+			if err := walkStrings(mv, joinPath(path, k), fn); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, sv := range t {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			if s, ok := sv.(string); ok {
+				ns, err := fn(p, s)
+				if err != nil {
+					return err
+				}
+				t[i] = ns
+				continue
+			}
+			if err := walkStrings(sv, p, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restoreOriginals writes the values as written back to a document for the values not changed since load.
+// The values are restored in the reverse order they were replaced on load, so a value overridden by
+// the environment, interpolated and templated is saved as written.
+func restoreOriginals(doc map[string]any, origs []original) {
+	for i := len(origs) - 1; i >= 0; i-- {
+		o := origs[i]
+		if v, err := getPath(doc, o.path); err == nil && v == o.value {
+			setPath(doc, o.path, o.raw)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cfg
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithTemplates(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"HostExternalURL": "https://{{ .Env.HOST | lower }}",
+		"CookieDomain": "{{ .Env.DOMAIN | default \"localhost\" }}",
+		"APIEndpoints": [{"ID": "self", "Address": "{{ .Cfg.HostExternalURL }}/api"}],
+		"ApplicationTheme": "{{ env \"THEME\" | upper }}"
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := map[string]string{"HOST": "Example.COM", "THEME": "dark"}[name]
+		return v, ok
+	}
+	config, err := Load(fn, WithTemplates(), WithLookupEnv(lookup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostExternalURL != "https://example.com" || *config.CookieDomain != "localhost" || *config.ApplicationTheme != "DARK" {
+		t.Fatalf(`Unexpected values %s %s %s`, *config.HostExternalURL, *config.CookieDomain, *config.ApplicationTheme)
+	}
+	// .Cfg is the document as written
+	if ep := config.GetEndpointInfo("self"); ep == nil || ep.Address != "https://{{ .Env.HOST | lower }}/api" {
+		t.Fatalf(`Unexpected endpoint %+v`, ep)
+	}
+
+	config.ApplicationTheme = new_string("light")
+	if err = config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := make(map[string]any)
+	if err = json.Unmarshal(b, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved["HostExternalURL"] != "https://{{ .Env.HOST | lower }}" || saved["ApplicationTheme"] != "light" {
+		t.Fatalf(`Expected the templates to be saved unless changed, got %v %v`, saved["HostExternalURL"], saved["ApplicationTheme"])
+	}
+
+	// without the option, templates are kept as written
+	if config, err = Load(fn, WithLookupEnv(lookup)); err != nil {
+		t.Fatal(err)
+	}
+	if *config.HostExternalURL != "https://{{ .Env.HOST | lower }}" {
+		t.Fatalf(`Unexpected value %s`, *config.HostExternalURL)
+	}
+}
+
+func TestWithTemplatesInvalid(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostExternalURL": "{{ .Cfg.Missing }}"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(fn, WithTemplates()); !errors.Is(err, ErrDecode) {
+		t.Fatalf(`Expected ErrDecode, got %v`, err)
+	}
+}
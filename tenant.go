@@ -0,0 +1,100 @@
+package cfg
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// GetDatabaseInfoForTenant gets the database of a tenant from the Tenancy section. The database is
+// looked up from the Tenants map first. Otherwise the group is taken from the longest matching prefix
+// of Prefixes, or is the GroupID, and the tenant id is hashed to one of the Shards first databases of the
+// group, so the tenants keep their database when databases are added to the group. It returns nil when
+// there is no Tenancy section, Shards is not set or is more than the databases of the group, or no database is found.
+func (c *Configuration) GetDatabaseInfoForTenant(tenantID string) *DatabaseInfo {
+	c = c.view()
+	if c.Tenancy == nil || tenantID == "" {
+		return nil
+	}
+	tn := c.Tenancy
+	for t, id := range tn.Tenants {
+		if c.sameKey(t, tenantID) {
+			return c.GetDatabaseInfo(id)
+		}
+	}
+	group := tn.GroupID
+	if p := c.tenantPrefix(tenantID); p != "" {
+		group = tn.Prefixes[p]
+	}
+	dbs := c.GetDatabaseInfoGroup(group)
+	if tn.Shards <= 0 || tn.Shards > len(dbs) {
+		return nil
+	}
+	return &dbs[shardOf(c.keyPolicy.normalize(tenantID), tn.Shards)]
+}
+
+// tenantPrefix returns the longest prefix of Prefixes the tenant id starts with
+func (c *Configuration) tenantPrefix(tenantID string) string {
+	ps := make([]string, 0, len(c.Tenancy.Prefixes))
+	for p := range c.Tenancy.Prefixes {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return len(ps[i]) > len(ps[j]) })
+	id := strings.ToLower(tenantID)
+	for _, p := range ps {
+		if p != "" && strings.HasPrefix(id, strings.ToLower(p)) {
+			return p
+		}
+	}
+	return ""
+}
+
+// shardOf hashes the tenant id to a shard
+func shardOf(tenantID string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(tenantID))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// checkTenancy checks that the Tenancy section refers to configured databases
+func (c *Configuration) checkTenancy(v *ValidationError) {
+	tn := c.Tenancy
+	ts := make([]string, 0, len(tn.Tenants))
+	for t := range tn.Tenants {
+		ts = append(ts, t)
+	}
+	sort.Strings(ts)
+	for _, t := range ts {
+		if c.GetDatabaseInfo(tn.Tenants[t]) == nil {
+			v.add(fmt.Sprintf("Tenancy.Tenants[%s]", t), fmt.Errorf("database %s: %w", tn.Tenants[t], ErrTenancyReference))
+		}
+	}
+	groups := make([]string, 0, len(tn.Prefixes))
+	for p := range tn.Prefixes {
+		groups = append(groups, p)
+	}
+	sort.Strings(groups)
+	for _, p := range groups {
+		c.checkShards(v, fmt.Sprintf("Tenancy.Prefixes[%s]", p), tn.Prefixes[p])
+	}
+	if tn.GroupID != "" {
+		c.checkShards(v, "Tenancy.GroupID", tn.GroupID)
+	}
+	switch {
+	case tn.Shards < 0:
+		v.add("Tenancy.Shards", ErrOutOfRange)
+	case tn.Shards == 0 && (tn.GroupID != "" || len(tn.Prefixes) > 0):
+		v.add("Tenancy.Shards", ErrRequired)
+	}
+}
+
+// checkShards checks that the database group of the tenants has at least Shards databases
+func (c *Configuration) checkShards(v *ValidationError, path, group string) {
+	n := len(c.GetDatabaseInfoGroup(group))
+	if n == 0 {
+		v.add(path, fmt.Errorf("database group %s: %w", group, ErrTenancyReference))
+	} else if c.Tenancy.Shards > n {
+		v.add(path, fmt.Errorf("%w: %d shards, group %s has %d databases", ErrOutOfRange, c.Tenancy.Shards, group, n))
+	}
+}
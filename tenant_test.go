@@ -0,0 +1,90 @@
+package cfg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetDatabaseInfoForTenant(t *testing.T) {
+	group := func(s string) *string { return &s }
+	config := &Configuration{
+		Databases: &[]DatabaseInfo{
+			{ID: "SHARD0", GroupID: group("SHARDS")},
+			{ID: "SHARD1", GroupID: group("SHARDS")},
+			{ID: "SHARD2", GroupID: group("SHARDS")},
+			{ID: "EU0", GroupID: group("EU")},
+			{ID: "EU1", GroupID: group("EU")},
+			{ID: "EU2", GroupID: group("EU")},
+			{ID: "DEDICATED"},
+		},
+		Tenancy: &TenancyInfo{
+			GroupID:  "SHARDS",
+			Tenants:  map[string]string{"acme": "DEDICATED"},
+			Prefixes: map[string]string{"eu-": "EU", "eu-x": "MISSING"},
+			Shards:   3,
+		},
+	}
+	if db := config.GetDatabaseInfoForTenant("ACME"); db == nil || db.ID != "DEDICATED" {
+		t.Fatalf(`Expected the mapped database, got %+v`, db)
+	}
+	if db := config.GetDatabaseInfoForTenant("eu-globex"); db == nil || !strings.HasPrefix(db.ID, "EU") {
+		t.Fatalf(`Expected the database of the prefix, got %+v`, db)
+	}
+	if db := config.GetDatabaseInfoForTenant("eu-xyz"); db != nil {
+		t.Fatalf(`Expected no database for the longest prefix of a missing group, got %+v`, db)
+	}
+
+	// the tenants are spread over the shards, always to the same shard
+	seen := make(map[string]bool)
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8"} {
+		db := config.GetDatabaseInfoForTenant(id)
+		if db == nil {
+			t.Fatalf(`Expected a shard for %s`, id)
+		}
+		if again := config.GetDatabaseInfoForTenant(id); again.ID != db.ID {
+			t.Fatalf(`Expected %s on %s, got %s`, id, db.ID, again.ID)
+		}
+		seen[db.ID] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf(`Expected the tenants on several shards, got %v`, seen)
+	}
+
+	// a database added to the group keeps the tenants on their shards
+	placed := make(map[string]string)
+	for _, id := range []string{"t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8"} {
+		placed[id] = config.GetDatabaseInfoForTenant(id).ID
+	}
+	*config.Databases = append(*config.Databases, DatabaseInfo{ID: "SHARD3", GroupID: group("SHARDS")})
+	for id, db := range placed {
+		if got := config.GetDatabaseInfoForTenant(id); got.ID != db {
+			t.Fatalf(`Expected %s kept on %s, got %s`, id, db, got.ID)
+		}
+	}
+	config.Tenancy.Shards = 1
+	if db := config.GetDatabaseInfoForTenant("t1"); db.ID != "SHARD0" {
+		t.Fatalf(`Expected the first shard, got %s`, db.ID)
+	}
+	if db := (&Configuration{}).GetDatabaseInfoForTenant("t1"); db != nil {
+		t.Fatalf(`Expected no database without tenancy, got %+v`, db)
+	}
+	config.Tenancy.Shards = 0
+	if db := config.GetDatabaseInfoForTenant("t1"); db != nil {
+		t.Fatalf(`Expected no database without shards, got %+v`, db)
+	}
+	if err := config.Validate(); !errors.Is(err, ErrRequired) {
+		t.Fatalf(`Expected ErrRequired, got %v`, err)
+	}
+	config.Tenancy.Shards = 1
+
+	err := config.Validate()
+	if !errors.Is(err, ErrTenancyReference) {
+		t.Fatalf(`Expected ErrTenancyReference, got %v`, err)
+	}
+	config.Tenancy.Shards = 5
+	delete(config.Tenancy.Prefixes, "eu-x")
+	if err = config.Validate(); !errors.Is(err, ErrOutOfRange) || errors.Is(err, ErrTenancyReference) {
+		t.Fatalf(`Expected ErrOutOfRange, got %v`, err)
+	}
+}
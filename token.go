@@ -0,0 +1,163 @@
+package cfg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenMargin is the time before its expiry a client credentials token is requested again
+const tokenMargin = 30 * time.Second
+
+type (
+	// tokenCache - client credentials token of a provider, shared by its copies
+	tokenCache struct {
+		sync.Mutex
+		token   string
+		expires time.Time // Zero when the token does not expire
+	}
+
+	// tokenResponse - fields of the token endpoint response used by the configuration
+	tokenResponse struct {
+		AccessToken      string `json:"access_token"`
+		TokenType        string `json:"token_type"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	// tokenTransport - round tripper sending the client credentials token of a provider
+	tokenTransport struct {
+		base   http.RoundTripper
+		oauth  OAuthProviderInfo
+		client *http.Client // Client of the token requests
+	}
+)
+
+// ClientCredentialsToken gets an access token of the client credentials flow from the TokenURL with the Scope.
+// The token is reused by the copies of the provider until shortly before it expires.
+// The token is requested with the DefaultHTTPClient of the configuration the provider was loaded with.
+func (oa *OAuthProviderInfo) ClientCredentialsToken(ctx context.Context) (string, error) {
+	client := oa.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return oa.clientToken(ctx, client)
+}
+
+// clientToken gets the cached client credentials token, or requests a new one with the client
+func (oa *OAuthProviderInfo) clientToken(ctx context.Context, client *http.Client) (string, error) {
+	tc := oa.tokens
+	if tc == nil {
+		tc = &tokenCache{}
+		oa.tokens = tc
+	}
+	tc.Lock()
+	defer tc.Unlock()
+	if tc.token != "" && (tc.expires.IsZero() || oa.now().Before(tc.expires)) {
+		return tc.token, nil
+	}
+	tu := oa.TokenURL()
+	if tu == "" {
+		return "", fmt.Errorf("oauth %s: provider api uri is not set", oa.ID)
+	}
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if oa.Scope != "" {
+		form.Set("scope", oa.Scope)
+	}
+	req, err := oa.tokenRequest(ctx, tu, form)
+	if err != nil {
+		return "", err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("oauth %s: %w", oa.ID, err)
+	}
+	defer res.Body.Close()
+	var tr tokenResponse
+	if err = json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&tr); err != nil && res.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("oauth %s: %w", oa.ID, err)
+	}
+	if res.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		if tr.Error != "" {
+			return "", fmt.Errorf("oauth %s: token endpoint returned status %d: %s %s", oa.ID, res.StatusCode, tr.Error, tr.ErrorDescription)
+		}
+		return "", fmt.Errorf("oauth %s: token endpoint returned status %d", oa.ID, res.StatusCode)
+	}
+	if tr.TokenType != "" && !strings.EqualFold(tr.TokenType, "bearer") {
+		return "", fmt.Errorf("oauth %s: token type %s is not supported", oa.ID, tr.TokenType)
+	}
+	tc.token, tc.expires = tr.AccessToken, time.Time{}
+	if tr.ExpiresIn > 0 {
+		tc.expires = oa.now().Add(time.Duration(tr.ExpiresIn)*time.Second - tokenMargin)
+	}
+	return tc.token, nil
+}
+
+// now returns the current time from the clock of the provider
+func (oa *OAuthProviderInfo) now() time.Time {
+	if oa.clock == nil {
+		return time.Now()
+	}
+	return oa.clock()
+}
+
+// forget drops the cached token when it is the one rejected
+func (tc *tokenCache) forget(token string) {
+	tc.Lock()
+	defer tc.Unlock()
+	if tc.token == token {
+		tc.token = ""
+	}
+}
+
+// EndpointClient returns the client of the calls to an endpoint by id, with the setting of DefaultHTTPClient.
+// When the endpoint has an OAuthID, the client sends the client credentials token of the provider as a
+// bearer token, requesting a new one when it expires or is rejected with status 401.
+// When the endpoint has a Signing, the client signs the requests.
+func (c *Configuration) EndpointClient(id string) (*http.Client, error) {
+	ep := c.GetEndpointInfo(id)
+	if ep == nil {
+		return nil, fmt.Errorf("endpoint %s is not found", id)
+	}
+	client := c.DefaultHTTPClient()
+	if ep.OAuthID == "" && ep.Signing == nil {
+		return client, nil
+	}
+	base := ep.RoundTripper(client.Transport)
+	if ep.OAuthID == "" {
+		return &http.Client{Transport: base, Timeout: client.Timeout}, nil
+	}
+	oa := c.GetOAuthInfo(ep.OAuthID)
+	if oa == nil {
+		return nil, fmt.Errorf("endpoint %s: %w: %s", ep.ID, ErrOAuthReference, ep.OAuthID)
+	}
+	if oa.tokens == nil {
+		oa.tokens = &tokenCache{}
+	}
+	return &http.Client{Transport: &tokenTransport{base: base, oauth: *oa, client: client}, Timeout: client.Timeout}, nil
+}
+
+// RoundTrip sends the request with the token
+func (t *tokenTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.oauth.clientToken(r.Context(), t.client)
+	if err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		return nil, err
+	}
+	req := r.Clone(r.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	res, err := t.base.RoundTrip(req)
+	if err == nil && res.StatusCode == http.StatusUnauthorized {
+		t.oauth.tokens.forget(token)
+	}
+	return res, err
+}
@@ -0,0 +1,158 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEndpointClient(t *testing.T) {
+	var tokens, rejected int
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if err := r.ParseForm(); err != nil || !ok || id != "orders" || secret != "s3cret" ||
+			r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "billing.read" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+			return
+		}
+		tokens++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, tokens)
+	}))
+	defer idp.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-1" && rejected == 0 {
+			// the first token is revoked after a call
+			rejected++
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer api.Close()
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := fmt.Sprintf(`{
+		"APIEndpoints": [{"ID": "billing", "Address": %q, "OAuthID": "idp"}, {"ID": "public", "Address": %q}],
+		"OAuths": [{"ID": "idp", "ClientID": "orders", "ClientSecret": "s3cret", "ProviderApiUri": %q, "TokenPath": "/token", "Scope": "billing.read"}]
+	}`, api.URL, api.URL, idp.URL)
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := config.EndpointClient("billing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := func(c *http.Client) int {
+		res, err := c.Get(api.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+	if s := status(client); s != http.StatusOK {
+		t.Fatalf(`Unexpected status %d`, s)
+	}
+	if s := status(client); s != http.StatusUnauthorized {
+		t.Fatalf(`Expected the revoked token to be rejected, got %d`, s)
+	}
+	// the rejected token is requested again, by the clients of the copies of the provider
+	if client, err = config.EndpointClient("billing"); err != nil {
+		t.Fatal(err)
+	}
+	if s := status(client); s != http.StatusOK || tokens != 2 {
+		t.Fatalf(`Unexpected status %d after %d tokens`, s, tokens)
+	}
+	if client, err = config.EndpointClient("public"); err != nil || status(client) != http.StatusUnauthorized {
+		t.Fatalf(`Expected no token for the endpoint without OAuthID, got %v`, err)
+	}
+
+	if oa := config.GetOAuthInfo("idp"); oa.client != config.DefaultHTTPClient() {
+		t.Fatal(`Expected the tokens to be requested with the client of the configuration`)
+	}
+
+	(*config.OAuths)[0].ClientSecret = "wrong"
+	(*config.OAuths)[0].tokens = nil
+	if client, err = config.EndpointClient("billing"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.Get(api.URL); err == nil {
+		t.Fatal(`Expected the token request to fail`)
+	}
+
+	doc = `{"APIEndpoints": [{"ID": "billing", "Address": "https://billing", "OAuthID": "missing"}]}`
+	if err = os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(fn); !errors.Is(err, ErrOAuthReference) {
+		t.Fatalf(`Expected ErrOAuthReference, got %v`, err)
+	}
+
+	doc = fmt.Sprintf(`{
+		"APIEndpoints": [{"ID": "search", "Address": "https://search", "OAuthID": "idp", "Signing": {"Type": "AWS-SIGV4", "KeyID": "AKID", "Secret": "s3cret", "Service": "es"}}],
+		"OAuths": [{"ID": "idp", "ClientID": "orders", "ProviderApiUri": %q}]
+	}`, idp.URL)
+	if err = os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if config, err = Load(fn); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.Validate(); !errors.Is(err, ErrConflict) {
+		t.Fatalf(`Expected ErrConflict with AWS-SIGV4 signing, got %v`, err)
+	}
+}
+
+func TestTokenExpiryClock(t *testing.T) {
+	var tokens int
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, tokens)
+	}))
+	defer idp.Close()
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := fmt.Sprintf(`{"OAuths": [{"ID": "idp", "ClientID": "orders", "ClientSecret": "s3cret", "ProviderApiUri": %q, "TokenPath": "/token"}]}`, idp.URL)
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	config, err := Load(fn, WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := func() string {
+		s, err := config.GetOAuthInfo("idp").ClientCredentialsToken(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	if s := token(); s != "token-1" {
+		t.Fatalf(`Unexpected token %s`, s)
+	}
+	// the token is kept until the margin before its expiry on the clock of the configuration
+	now = now.Add(time.Hour - tokenMargin - time.Second)
+	if s := token(); s != "token-1" {
+		t.Fatalf(`Expected the cached token, got %s`, s)
+	}
+	now = now.Add(time.Second)
+	if s := token(); s != "token-2" {
+		t.Fatalf(`Expected a new token at the margin, got %s`, s)
+	}
+}
@@ -0,0 +1,356 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type (
+	// Problem - a problem found on validating the configuration
+	Problem struct {
+		Path string // Path of the field like Databases[0].ID
+		Err  error  // The problem
+	}
+
+	// ValidationError - problems found on validating the configuration
+	ValidationError struct {
+		Problems []Problem
+	}
+)
+
+var (
+	ErrRequired    = errors.New("value is required")
+	ErrDuplicateID = errors.New("duplicate id")
+	ErrInvalidEnum = errors.New("value is not supported")
+	ErrOutOfRange  = errors.New("value is out of range")
+	ErrConflict    = errors.New("value conflicts with another setting")
+)
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[reflect.Type][]func(any) error{}
+)
+
+// Error lists the problems
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		msgs = append(msgs, p.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Is checks if the target is ErrValidation or if any of the problems matches the target error
+func (e *ValidationError) Is(target error) bool {
+	if target == ErrValidation {
+		return true
+	}
+	for _, p := range e.Problems {
+		if errors.Is(p.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// add adds a problem
+func (e *ValidationError) add(path string, err error) {
+	e.Problems = append(e.Problems, Problem{Path: path, Err: err})
+}
+
+// err returns the validation error, or nil if there are no problems
+func (e *ValidationError) err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// String formats the problem with its path
+func (p Problem) String() string {
+	return p.Path + ": " + p.Err.Error()
+}
+
+// Validate checks the configuration for missing and duplicate ids, unsupported values,
+// cross references between sections and the rules of the registered validators.
+// All problems are returned in a *ValidationError.
+func (c *Configuration) Validate() error {
+	v := &ValidationError{}
+	c.checkSchema(v)
+	c.checkReferences(v)
+	c.checkRegistered(v)
+	return v.err()
+}
+
+// RegisterValidator registers a validator run by Validate for each entry or section of the type,
+// like DatabaseInfo or EndpointInfo, so applications enforce their own rules like naming conventions
+// or banned hosts. The problems are reported with the path of the entry like Databases[0].
+func RegisterValidator[T any](fn func(T) error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+	validators[t] = append(validators[t], func(v any) error {
+		return fn(v.(T))
+	})
+}
+
+// validate checks the cross references between sections.
+// This is run on load.
+func (c *Configuration) validate() error {
+	v := &ValidationError{}
+	c.checkReferences(v)
+	return v.err()
+}
+
+// checkReferences checks the cross references between sections
+func (c *Configuration) checkReferences(v *ValidationError) {
+	if c.Sessions != nil {
+		for i, ss := range *c.Sessions {
+			if ss.StoreType != `CACHE` {
+				continue
+			}
+			if c.Cache == nil || !c.sameKey(c.Cache.ID, ss.CacheID) {
+				v.add(fmt.Sprintf("Sessions[%d].CacheID", i), fmt.Errorf("session %s: %w", ss.ID, ErrSessionNoCache))
+			}
+		}
+	}
+	if c.Jobs != nil {
+		for i, jb := range *c.Jobs {
+			if err := validateSchedule(jb.Schedule); err != nil {
+				v.add(fmt.Sprintf("Jobs[%d].Schedule", i), fmt.Errorf("job %s: %w", jb.ID, err))
+			}
+		}
+	}
+	if c.Maintenance != nil {
+		for i, w := range c.Maintenance.Windows {
+			if _, _, err := w.bounds(); err != nil {
+				v.add(fmt.Sprintf("Maintenance.Windows[%d]", i), err)
+			}
+		}
+	}
+	if c.Health != nil {
+		for i, id := range c.Health.DatabaseIDs {
+			if c.GetDatabaseInfo(id) == nil {
+				v.add(fmt.Sprintf("Health.DatabaseIDs[%d]", i), fmt.Errorf("database %s: %w", id, ErrHealthReference))
+			}
+		}
+		for i, id := range c.Health.EndpointIDs {
+			if c.endpoint(id) == nil {
+				v.add(fmt.Sprintf("Health.EndpointIDs[%d]", i), fmt.Errorf("endpoint %s: %w", id, ErrHealthReference))
+			}
+		}
+		if c.Health.CacheID != "" && (c.Cache == nil || !c.sameKey(c.Cache.ID, c.Health.CacheID)) {
+			v.add("Health.CacheID", fmt.Errorf("cache %s: %w", c.Health.CacheID, ErrHealthReference))
+		}
+		if c.Health.Queue && c.Queue == nil {
+			v.add("Health.Queue", fmt.Errorf("queue: %w", ErrHealthReference))
+		}
+	}
+	if c.APIEndpoints != nil {
+		for i, ep := range *c.APIEndpoints {
+			if ep.OAuthID != "" && c.GetOAuthInfo(ep.OAuthID) == nil {
+				v.add(fmt.Sprintf("APIEndpoints[%d].OAuthID", i), fmt.Errorf("endpoint %s: %w", ep.ID, ErrOAuthReference))
+			}
+		}
+	}
+	if c.Tenancy != nil {
+		c.checkTenancy(v)
+	}
+}
+
+// checkSchema checks for missing and duplicate ids and unsupported values
+func (c *Configuration) checkSchema(v *ValidationError) {
+	checkIDs(v, c.keyPolicy, "APIEndpoints", c.APIEndpoints, func(e EndpointInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "APIKeys", c.APIKeys, func(e APIKeyInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Databases", c.Databases, func(e DatabaseInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Jobs", c.Jobs, func(e JobInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Notifications", c.Notifications, func(e NotificationInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "OAuths", c.OAuths, func(e OAuthProviderInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "RateLimits", c.RateLimits, func(e RateLimitInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Sessions", c.Sessions, func(e SessionInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Sources", c.Sources, func(e SourceInfo) string { return e.ID })
+	checkIDs(v, c.keyPolicy, "Webhooks", c.Webhooks, func(e WebhookInfo) string { return e.ID })
+
+	if c.APIEndpoints != nil {
+		for i, ep := range *c.APIEndpoints {
+			if ep.Strategy != "" {
+				checkEnum(v, fmt.Sprintf("APIEndpoints[%d].Strategy", i), ep.Strategy, StrategyRoundRobin, StrategyRandom, StrategyFailover)
+			}
+			if sg := ep.Signing; sg != nil {
+				checkEnum(v, fmt.Sprintf("APIEndpoints[%d].Signing.Type", i), sg.Type, SigningHMAC, SigningAWSV4)
+				if sg.Algorithm != "" {
+					checkEnum(v, fmt.Sprintf("APIEndpoints[%d].Signing.Algorithm", i), sg.Algorithm, "SHA256", "SHA512", "SHA1")
+				}
+				if strings.EqualFold(sg.Type, SigningHMAC) && sg.Secret == "" {
+					v.add(fmt.Sprintf("APIEndpoints[%d].Signing.Secret", i), ErrRequired)
+				}
+				if strings.EqualFold(sg.Type, SigningAWSV4) && sg.Service == "" {
+					v.add(fmt.Sprintf("APIEndpoints[%d].Signing.Service", i), ErrRequired)
+				}
+				if strings.EqualFold(sg.Type, SigningAWSV4) && ep.OAuthID != "" {
+					// both set the Authorization header of the request
+					v.add(fmt.Sprintf("APIEndpoints[%d].OAuthID", i), fmt.Errorf("endpoint %s: %w: %s signing sets the authorization", ep.ID, ErrConflict, SigningAWSV4))
+				}
+			}
+			if cb := ep.Breaker; cb != nil {
+				if cb.FailureThreshold < 0 {
+					v.add(fmt.Sprintf("APIEndpoints[%d].Breaker.FailureThreshold", i), ErrOutOfRange)
+				}
+				if cb.OpenDuration != nil && *cb.OpenDuration < 0 {
+					v.add(fmt.Sprintf("APIEndpoints[%d].Breaker.OpenDuration", i), ErrOutOfRange)
+				}
+				if cb.HalfOpenProbes < 0 {
+					v.add(fmt.Sprintf("APIEndpoints[%d].Breaker.HalfOpenProbes", i), ErrOutOfRange)
+				}
+			}
+		}
+	}
+	if c.Databases != nil {
+		for i, db := range *c.Databases {
+			checkEnum(v, fmt.Sprintf("Databases[%d].StorageType", i), db.StorageType, "SERVER", "FILE")
+			if _, err := db.DSN(); err != nil {
+				v.add(fmt.Sprintf("Databases[%d].Connection", i), err)
+			}
+			if db.DefaultQueryTimeout != nil && *db.DefaultQueryTimeout < 0 {
+				v.add(fmt.Sprintf("Databases[%d].DefaultQueryTimeout", i), ErrOutOfRange)
+			}
+			if db.DefaultTxIsolation != "" {
+				checkEnum(v, fmt.Sprintf("Databases[%d].DefaultTxIsolation", i), db.DefaultTxIsolation,
+					"READ-UNCOMMITTED", "READ-COMMITTED", "REPEATABLE-READ", "SNAPSHOT", "SERIALIZABLE")
+			}
+			if db.AutoMigrate && db.MigrationsPath == "" {
+				v.add(fmt.Sprintf("Databases[%d].MigrationsPath", i), ErrRequired)
+			}
+		}
+	}
+	if c.RateLimits != nil {
+		for i, rl := range *c.RateLimits {
+			checkEnum(v, fmt.Sprintf("RateLimits[%d].KeyBy", i), rl.KeyBy, "IP", "USER", "API-KEY")
+			if rl.Requests <= 0 {
+				v.add(fmt.Sprintf("RateLimits[%d].Requests", i), ErrOutOfRange)
+			}
+			if rl.Window <= 0 {
+				v.add(fmt.Sprintf("RateLimits[%d].Window", i), ErrOutOfRange)
+			}
+		}
+	}
+	if c.Sessions != nil {
+		for i, ss := range *c.Sessions {
+			checkEnum(v, fmt.Sprintf("Sessions[%d].StoreType", i), ss.StoreType, "MEMORY", "CACHE")
+		}
+	}
+	if c.Sources != nil {
+		for i, si := range *c.Sources {
+			if si.RetentionDays < 0 {
+				v.add(fmt.Sprintf("Sources[%d].RetentionDays", i), ErrOutOfRange)
+			}
+			if si.MaxFileSize < 0 {
+				v.add(fmt.Sprintf("Sources[%d].MaxFileSize", i), ErrOutOfRange)
+			}
+		}
+	}
+	if c.Webhooks != nil {
+		for i, wh := range *c.Webhooks {
+			if wh.URL == "" {
+				v.add(fmt.Sprintf("Webhooks[%d].URL", i), ErrRequired)
+			}
+		}
+	}
+	if hc := c.HTTPClient; hc != nil {
+		if hc.Timeout != nil && *hc.Timeout < 0 {
+			v.add("HTTPClient.Timeout", ErrOutOfRange)
+		}
+		if hc.MaxIdleConns < 0 {
+			v.add("HTTPClient.MaxIdleConns", ErrOutOfRange)
+		}
+		if hc.TLSMinVersion != "" {
+			checkEnum(v, "HTTPClient.TLSMinVersion", hc.TLSMinVersion, "1.0", "1.1", "1.2", "1.3")
+		}
+	}
+	if c.JWT != nil {
+		checkEnum(v, "JWT.Algorithm", c.JWT.Algorithm,
+			"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "PS256", "PS384", "PS512", "EDDSA")
+	}
+	if c.HostPort != nil && (*c.HostPort < 0 || *c.HostPort > 65535) {
+		v.add("HostPort", ErrOutOfRange)
+	}
+	if c.HostListenAddress != nil {
+		if _, _, err := c.listenAddr(); err != nil {
+			v.add("HostListenAddress", err)
+		}
+	}
+}
+
+// checkRegistered runs the registered validators on the sections and their entries
+func (c *Configuration) checkRegistered(v *ValidationError) {
+	validatorsMu.RLock()
+	fns := make(map[reflect.Type][]func(any) error, len(validators))
+	for t, vs := range validators {
+		fns[t] = vs
+	}
+	validatorsMu.RUnlock()
+	if len(fns) == 0 {
+		return
+	}
+	run := func(path string, rv reflect.Value) {
+		for _, fn := range fns[rv.Type()] {
+			if err := fn(rv.Interface()); err != nil {
+				v.add(path, err)
+			}
+		}
+	}
+	cv := reflect.ValueOf(c).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		f, fv := cv.Type().Field(i), cv.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() != reflect.Slice {
+			run(f.Name, fv)
+			continue
+		}
+		for j := 0; j < fv.Len(); j++ {
+			run(fmt.Sprintf("%s[%d]", f.Name, j), fv.Index(j))
+		}
+	}
+}
+
+// checkIDs checks that the entries of a section have non-empty ids, unique under the key policy
+func checkIDs[T any](v *ValidationError, p KeyPolicy, section string, items *[]T, id func(T) string) {
+	if items == nil {
+		return
+	}
+	seen := make(map[string]int)
+	for i, item := range *items {
+		path := fmt.Sprintf("%s[%d].ID", section, i)
+		if id(item) == "" {
+			v.add(path, ErrRequired)
+			continue
+		}
+		k := p.normalize(id(item))
+		if j, ok := seen[k]; ok {
+			v.add(path, fmt.Errorf("%w %s, also in %s[%d]", ErrDuplicateID, id(item), section, j))
+			continue
+		}
+		seen[k] = i
+	}
+}
+
+// checkEnum checks that the value is one of the supported values
+func checkEnum(v *ValidationError, path, value string, supported ...string) {
+	for _, s := range supported {
+		if strings.EqualFold(value, s) {
+			return
+		}
+	}
+	v.add(path, fmt.Errorf("%w: %q, expected one of %s", ErrInvalidEnum, value, strings.Join(supported, ", ")))
+}
@@ -0,0 +1,49 @@
+package cfg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterValidator(t *testing.T) {
+	errBanned := errors.New("host is banned")
+	RegisterValidator(func(ep EndpointInfo) error {
+		if strings.Contains(ep.Address, "banned.example.com") {
+			return errBanned
+		}
+		return nil
+	})
+	RegisterValidator(func(h HealthInfo) error {
+		if h.LivenessPath == "/banned" {
+			return errBanned
+		}
+		return nil
+	})
+
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{
+		"APIEndpoints": [
+			{"ID": "DEFAULT", "Address": "https://api.example.com"},
+			{"ID": "LEGACY", "Address": "https://banned.example.com"}
+		],
+		"Health": {"LivenessPath": "/banned"}
+	}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.Validate()
+	var ve *ValidationError
+	if !errors.As(err, &ve) || !errors.Is(err, errBanned) {
+		t.Fatalf(`Expected the registered validators to fail, got %v`, err)
+	}
+	if len(ve.Problems) != 2 || ve.Problems[0].Path != "APIEndpoints[1]" || ve.Problems[1].Path != "Health" {
+		t.Fatalf(`Unexpected problems %v`, ve.Problems)
+	}
+}
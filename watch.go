@@ -0,0 +1,197 @@
+package cfg
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrWatchUnsupported = errors.New("configuration source does not support watching")
+
+// maxWatchBackoff is the longest wait before reconnecting a watch
+const maxWatchBackoff = 30 * time.Second
+
+// WatchEvents subscribes to the change events of a remote configuration served by a Server, or pushed
+// by a Source implementing SourceWatcher, instead of polling, and reloads the configuration through the
+// validators on each change like ReloadWith. It reconnects with backoff when the stream ends and returns
+// when the context is done, or with ErrWatchUnsupported when the source does not stream events so the
+// caller can fall back to polling.
+func (c *Configuration) WatchEvents(ctx context.Context, validators ...func(*Configuration) error) error {
+	cur := c.view()
+	if err := cur.writable(); err != nil {
+		return err
+	}
+	sw, isWatcher := cur.source.(SourceWatcher)
+	if cur.source != nil && !isWatcher || cur.local || cur.loader != nil || strings.HasPrefix(cur.FileName, envSource) {
+		return ErrWatchUnsupported
+	}
+	backoff := time.Second
+	for {
+		var (
+			connected bool
+			err       error
+		)
+		if isWatcher {
+			connected, err = true, sw.Watch(ctx, func() {
+				c.changed(validators)
+			})
+		} else {
+			connected, err = c.watchEvents(ctx, validators)
+		}
+		if errors.Is(err, ErrWatchUnsupported) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if connected {
+			backoff = time.Second
+		}
+		cur.log().Warn("configuration watch disconnected", "source", cur.FileName, "error", err, "retry", backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxWatchBackoff {
+			backoff = maxWatchBackoff
+		}
+	}
+}
+
+// watchEvents reads the event stream until it ends and reports if it was connected
+func (c *Configuration) watchEvents(ctx context.Context, validators []func(*Configuration) error) (bool, error) {
+	cur := c.view()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cur.FileName, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if cur.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+cur.bearer)
+	}
+	// the stream is read until it ends, beyond the timeout of the requests
+	client := *httpClient(cur.Proxy, cur.HTTPClient)
+	client.Timeout = 0
+	res, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		return false, ErrWatchUnsupported
+	}
+
+	event := ""
+	sc := bufio.NewScanner(res.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case line == "":
+			if event == "change" {
+				c.changed(validators)
+			}
+			event = ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(line[len("event:"):])
+		}
+	}
+	if err = sc.Err(); err == nil {
+		err = errors.New("event stream ended")
+	}
+	return true, err
+}
+
+// changed observes a change seen by a watcher and reloads the configuration through the validators
+func (c *Configuration) changed(validators []func(*Configuration) error) {
+	cur := c.view()
+	cur.observe(EventWatch, cur.FileName, cur.now(), nil)
+	// failures are logged and observed by the reload
+	c.reload(newOptions(), validators)
+}
+
+// kubeDataDir is the symlink kubelet swaps atomically on updating a mounted ConfigMap or Secret
+const kubeDataDir = "..data"
+
+// WatchFile polls the local configuration file at the interval, default 1 second, and reloads the
+// configuration through the validators on each change like ReloadWith, until the context is done.
+// Changes are detected on the resolved path, modification time and size of the file, so the atomic
+// swap of the ..data symlink of Kubernetes projected volumes is detected even when the new file
+// has the same modification time and size. It returns ErrWatchUnsupported for remote configurations.
+func (c *Configuration) WatchFile(ctx context.Context, interval time.Duration, validators ...func(*Configuration) error) error {
+	cur := c.view()
+	if err := cur.writable(); err != nil {
+		return err
+	}
+	if !cur.local || cur.loader != nil || cur.source != nil {
+		return ErrWatchUnsupported
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	last, err := fileFingerprint(cur.FileName)
+	if err != nil {
+		return err
+	}
+	tk := time.NewTicker(interval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tk.C:
+		}
+		fp, err := fileFingerprint(cur.FileName)
+		if err != nil || fp == last {
+			// the file can be missing for a moment while it is replaced
+			continue
+		}
+		last = fp
+		c.changed(validators)
+	}
+}
+
+// WatchEnv refreshes the interpolated values at the interval, default 1 minute, like RefreshEnv,
+// until the context is done. Failed refreshes are logged and the values are kept.
+func (c *Configuration) WatchEnv(ctx context.Context, interval time.Duration) error {
+	if err := c.view().writable(); err != nil {
+		return err
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	tk := time.NewTicker(interval)
+	defer tk.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tk.C:
+		}
+		if err := c.RefreshEnv(); err != nil {
+			cur := c.view()
+			cur.log().Warn("environment refresh failed", "source", cur.FileName, "error", err)
+		}
+	}
+}
+
+// fileFingerprint identifies the version of a file by its resolved path, the target of the
+// ..data symlink in its directory, its modification time and size
+func fileFingerprint(name string) (string, error) {
+	real, err := filepath.EvalSymlinks(name)
+	if err != nil {
+		return "", err
+	}
+	fi, err := os.Stat(real)
+	if err != nil {
+		return "", err
+	}
+	data, _ := os.Readlink(filepath.Join(filepath.Dir(name), kubeDataDir))
+	return fmt.Sprintf("%s|%s|%d|%d", real, data, fi.ModTime().UnixNano(), fi.Size()), nil
+}
@@ -0,0 +1,182 @@
+package cfg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchEvents(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(fn, []byte(`{"HostPort": 8000}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	central, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(&Server{Config: central, PollInterval: 10 * time.Millisecond})
+	defer srv.Close()
+
+	reloaded := make(chan int, 1)
+	config, err := Load(srv.URL, WithReloadCheck(func(c *Configuration) error {
+		reloaded <- *c.HostPort
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- config.WatchEvents(ctx)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if err = os.WriteFile(fn, []byte(`{"HostPort": 8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = central.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case port := <-reloaded:
+		if port != 8080 {
+			t.Fatalf(`Unexpected port %d`, port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal(`Expected a reload on the change event`)
+	}
+	cancel()
+	if err = <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected the watch to stop on cancel, got %v`, err)
+	}
+	if st := config.Stats(); st.WatchEvents != 1 {
+		t.Fatalf(`Unexpected stats %+v`, st)
+	}
+}
+
+func TestWatchEventsUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"HostPort": 8000}`))
+	}))
+	defer srv.Close()
+	config, err := Load(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = config.WatchEvents(context.Background()); !errors.Is(err, ErrWatchUnsupported) {
+		t.Fatalf(`Expected ErrWatchUnsupported, got %v`, err)
+	}
+	if config, err = Load("samples/config.mssql.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err = config.WatchEvents(context.Background()); !errors.Is(err, ErrWatchUnsupported) {
+		t.Fatalf(`Expected ErrWatchUnsupported for a local file, got %v`, err)
+	}
+}
+
+func TestWatchFileProjectedVolume(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	version := func(name, doc string) {
+		vd := filepath.Join(dir, name)
+		if err := os.Mkdir(vd, 0755); err != nil {
+			t.Fatal(err)
+		}
+		fn := filepath.Join(vd, "config.json")
+		if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fn, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		// kubelet swaps the ..data symlink atomically with a rename
+		tmp := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(name, tmp); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(tmp, filepath.Join(dir, kubeDataDir)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	version("..2024_01_01_00_00_00.1", `{"HostPort": 8000}`)
+	fn := filepath.Join(dir, "config.json")
+	if err := os.Symlink(filepath.Join(kubeDataDir, "config.json"), fn); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan int, 1)
+	config, err := Load(fn, WithReloadCheck(func(c *Configuration) error {
+		reloaded <- *c.HostPort
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- config.WatchFile(ctx, 10*time.Millisecond)
+	}()
+
+	// let the watch take the fingerprint of the first version
+	time.Sleep(50 * time.Millisecond)
+	// same size and modification time as the previous version
+	version("..2024_01_01_00_05_00.2", `{"HostPort": 8080}`)
+	select {
+	case port := <-reloaded:
+		if port != 8080 {
+			t.Fatalf(`Unexpected port %d`, port)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal(`Expected a reload on the ..data swap`)
+	}
+	cancel()
+	if err = <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf(`Expected the watch to stop on cancel, got %v`, err)
+	}
+}
+
+func TestReloadWhileLookingUp(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "config.json")
+	doc := `{"Databases": [{"ID": "DEFAULT", "ConnectionString": "postgres://localhost/orders"}], "Flags": [{"key": "MaxLimit", "value": "10"}],
+		"Health": {"DatabaseIDs": ["DEFAULT"], "Timeout": 1}}`
+	if err := os.WriteFile(fn, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := Load(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			config.Reload()
+			config.Rollback()
+		}
+	}()
+	// the lookups read the configuration swapped by the reloads, run with -race
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if config.GetDatabaseInfo("DEFAULT") == nil || config.Flag("MaxLimit").Value == nil {
+			t.Fatal(`Expected the lookups to find the entries during the reloads`)
+		}
+		rpt := config.CheckHealth(context.Background(), HealthCheckers{
+			Database: func(ctx context.Context, db DatabaseInfo) error { return nil },
+		})
+		if !rpt.Healthy || len(rpt.Results) != 1 {
+			t.Fatalf(`Expected the health checks to run during the reloads, got %+v`, rpt)
+		}
+	}
+}
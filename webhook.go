@@ -0,0 +1,19 @@
+package cfg
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SigningSecret returns the secret with environment placeholders resolved
+func (w WebhookInfo) SigningSecret() string {
+	return w.memo.resolve(w.Secret)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the payload
+func (w WebhookInfo) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.SigningSecret()))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}